@@ -24,6 +24,119 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for AnnealingOptionsCooling.
+const (
+	Geometric AnnealingOptionsCooling = "geometric"
+	Linear    AnnealingOptionsCooling = "linear"
+)
+
+// Defines values for BidPolicy.
+const (
+	FewestHours BidPolicy = "fewest-hours"
+	FirstCome   BidPolicy = "first-come"
+	Preference  BidPolicy = "preference"
+	Seniority   BidPolicy = "seniority"
+)
+
+// Defines values for BidStatus.
+const (
+	BidStatusAwarded    BidStatus = "awarded"
+	BidStatusPending    BidStatus = "pending"
+	BidStatusWaitlisted BidStatus = "waitlisted"
+)
+
+// Defines values for GeneticOptionsCrossover.
+const (
+	OnePoint GeneticOptionsCrossover = "one-point"
+	Uniform  GeneticOptionsCrossover = "uniform"
+)
+
+// Defines values for GeneticOptionsMutation.
+const (
+	Flip GeneticOptionsMutation = "flip"
+	Move GeneticOptionsMutation = "move"
+)
+
+// Defines values for LockoutKind.
+const (
+	Account LockoutKind = "account"
+	Ip      LockoutKind = "ip"
+)
+
+// Defines values for Permission.
+const (
+	AssignmentsWrite Permission = "assignments:write"
+	RolesManage      Permission = "roles:manage"
+	RulesManage      Permission = "rules:manage"
+	ScheduleRead     Permission = "schedule:read"
+	ShiftsRead       Permission = "shifts:read"
+	ShiftsWrite      Permission = "shifts:write"
+	SwapsManage      Permission = "swaps:manage"
+	TeamsManage      Permission = "teams:manage"
+	TimeOffManage    Permission = "time-off:manage"
+	WorkersRead      Permission = "workers:read"
+	WorkersWrite     Permission = "workers:write"
+)
+
+// Defines values for PreferenceWeight.
+const (
+	Avoid          PreferenceWeight = "avoid"
+	Neutral        PreferenceWeight = "neutral"
+	Prefer         PreferenceWeight = "prefer"
+	StronglyAvoid  PreferenceWeight = "strongly_avoid"
+	StronglyPrefer PreferenceWeight = "strongly_prefer"
+)
+
+// Defines values for RuleName.
+const (
+	MaxConsecutiveDays   RuleName = "max-consecutive-days"
+	MaxConsecutiveNights RuleName = "max-consecutive-nights"
+	MaxWeeklyHours       RuleName = "max-weekly-hours"
+	MinRest              RuleName = "min-rest"
+	SameDay              RuleName = "same-day"
+)
+
+// Defines values for ScheduleRequestSolver.
+const (
+	Annealing ScheduleRequestSolver = "annealing"
+	Genetic   ScheduleRequestSolver = "genetic"
+	Greedy    ScheduleRequestSolver = "greedy"
+)
+
+// Defines values for SwapStatus.
+const (
+	SwapStatusAccepted  SwapStatus = "accepted"
+	SwapStatusCancelled SwapStatus = "cancelled"
+	SwapStatusCompleted SwapStatus = "completed"
+	SwapStatusOpen      SwapStatus = "open"
+	SwapStatusProposed  SwapStatus = "proposed"
+	SwapStatusRejected  SwapStatus = "rejected"
+)
+
+// Defines values for TimeOffDecisionStatus.
+const (
+	TimeOffDecisionStatusApproved TimeOffDecisionStatus = "approved"
+	TimeOffDecisionStatusRejected TimeOffDecisionStatus = "rejected"
+)
+
+// Defines values for TimeOffStatus.
+const (
+	TimeOffStatusApproved TimeOffStatus = "approved"
+	TimeOffStatusPending  TimeOffStatus = "pending"
+	TimeOffStatusRejected TimeOffStatus = "rejected"
+)
+
+// Defines values for Weekday.
+const (
+	Friday    Weekday = "friday"
+	Monday    Weekday = "monday"
+	Saturday  Weekday = "saturday"
+	Sunday    Weekday = "sunday"
+	Thursday  Weekday = "thursday"
+	Tuesday   Weekday = "tuesday"
+	Wednesday Weekday = "wednesday"
+)
+
 // Defines values for SpanLength.
 const (
 	SpanLengthDay  SpanLength = "day"
@@ -36,6 +149,12 @@ const (
 	GetMeScheduleParamsSpanWeek GetMeScheduleParamsSpan = "week"
 )
 
+// Defines values for GetScheduleFeasibilityParamsSpan.
+const (
+	GetScheduleFeasibilityParamsSpanDay  GetScheduleFeasibilityParamsSpan = "day"
+	GetScheduleFeasibilityParamsSpanWeek GetScheduleFeasibilityParamsSpan = "week"
+)
+
 // Defines values for GetShiftsParamsSpan.
 const (
 	GetShiftsParamsSpanDay  GetShiftsParamsSpan = "day"
@@ -44,14 +163,78 @@ const (
 
 // Defines values for GetWorkerScheduleParamsSpan.
 const (
-	GetWorkerScheduleParamsSpanDay  GetWorkerScheduleParamsSpan = "day"
-	GetWorkerScheduleParamsSpanWeek GetWorkerScheduleParamsSpan = "week"
+	Day  GetWorkerScheduleParamsSpan = "day"
+	Week GetWorkerScheduleParamsSpan = "week"
 )
 
+// Absence defines model for Absence.
+type Absence struct {
+	// EndDate Last day of absence (inclusive)
+	EndDate openapi_types.Date `json:"end_date"`
+
+	// StartDate First day of absence
+	StartDate openapi_types.Date `json:"start_date"`
+}
+
+// AbsenceResult defines model for AbsenceResult.
+type AbsenceResult struct {
+	// Applied Whether the replacements were assigned
+	Applied bool `json:"applied"`
+
+	// Removed Shift assignments removed for the absent worker
+	Removed   []ShiftAssignment `json:"removed"`
+	Vacancies []Vacancy         `json:"vacancies"`
+}
+
+// AnnealingOptions defines model for AnnealingOptions.
+type AnnealingOptions struct {
+	// Cooling Cooling schedule (defaults to "geometric")
+	Cooling *AnnealingOptionsCooling `json:"cooling,omitempty"`
+
+	// CoolingRate Temperature multiplier per iteration for geometric cooling (defaults to 0.999)
+	CoolingRate *float64 `json:"cooling_rate,omitempty"`
+
+	// InitialTemperature Starting temperature (defaults to 100)
+	InitialTemperature *float64 `json:"initial_temperature,omitempty"`
+
+	// Iterations Number of iterations (defaults to 10000)
+	Iterations *int32 `json:"iterations,omitempty"`
+
+	// Seed Random number generator seed (defaults to a time-based value)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// AnnealingOptionsCooling Cooling schedule (defaults to "geometric")
+type AnnealingOptionsCooling string
+
+// Bid defines model for Bid.
+type Bid struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        BidId     `json:"id"`
+
+	// Position Place in the shift's waitlist (for waitlisted bids, starting from 1)
+	Position *int      `json:"position,omitempty"`
+	ShiftId  ShiftId   `json:"shift_id"`
+	Status   BidStatus `json:"status"`
+	WorkerId WorkerId  `json:"worker_id"`
+}
+
+// BidId defines model for BidId.
+type BidId = int64
+
+// BidPolicy defines model for BidPolicy.
+type BidPolicy string
+
+// BidStatus defines model for BidStatus.
+type BidStatus string
+
 // Credentials defines model for Credentials.
 type Credentials struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken string `json:"access_token"`
+
+	// RecoveryCodes New two-factor recovery codes, after enrolling during login
+	RecoveryCodes *[]string `json:"recovery_codes,omitempty"`
+	RefreshToken  string    `json:"refresh_token"`
 }
 
 // CredentialsRefresh defines model for CredentialsRefresh.
@@ -64,48 +247,477 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// Feasibility defines model for Feasibility.
+type Feasibility struct {
+	// Capacity Total number of places in all shifts
+	Capacity int32 `json:"capacity"`
+
+	// MaxCoverage Maximum number of places that can be filled
+	MaxCoverage int32 `json:"max_coverage"`
+
+	// Unfillable Shifts that can't be filled in a maximum coverage assignment
+	Unfillable []ShiftShortfall `json:"unfillable"`
+}
+
+// GeneticOptions defines model for GeneticOptions.
+type GeneticOptions struct {
+	// Crossover Crossover operator (defaults to "one-point")
+	Crossover *GeneticOptionsCrossover `json:"crossover,omitempty"`
+
+	// Generations Number of generations (defaults to 200)
+	Generations *int32 `json:"generations,omitempty"`
+
+	// Mutation Mutation operator (defaults to "move")
+	Mutation *GeneticOptionsMutation `json:"mutation,omitempty"`
+
+	// MutationRate Mutation probability per assignment matrix entry (defaults to 0.05)
+	MutationRate *float64 `json:"mutation_rate,omitempty"`
+
+	// PopulationSize Number of genomes in each generation (defaults to 50)
+	PopulationSize *int32 `json:"population_size,omitempty"`
+
+	// Seed Random number generator seed (defaults to a time-based value)
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// GeneticOptionsCrossover Crossover operator (defaults to "one-point")
+type GeneticOptionsCrossover string
+
+// GeneticOptionsMutation Mutation operator (defaults to "move")
+type GeneticOptionsMutation string
+
+// Lockout Audit record of logins being locked after too many failures, for an account (the subject is the email address used) or a client IP address
+type Lockout struct {
+	Failures    int         `json:"failures"`
+	Id          int64       `json:"id"`
+	Kind        LockoutKind `json:"kind"`
+	LockedAt    time.Time   `json:"locked_at"`
+	LockedUntil time.Time   `json:"locked_until"`
+	Subject     string      `json:"subject"`
+	WorkerId    *WorkerId   `json:"worker_id,omitempty"`
+}
+
+// LockoutKind defines model for Lockout.Kind.
+type LockoutKind string
+
 // Login defines model for Login.
 type Login struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// PasswordChange defines model for PasswordChange.
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// PasswordResetRequest defines model for PasswordResetRequest.
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// PasswordTokenRedemption A token from an invitation or password reset email, with the new password
+type PasswordTokenRedemption struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// Permission A permission for a group of operations. "workers:read" and "workers:write" cover viewing and managing workers; "shifts:read" covers viewing every team's shifts, shift templates and bids, and "shifts:write" covers managing shifts and shift templates; "assignments:write" covers assigning workers to shifts, absences, bid resolution and schedule solving; "schedule:read" covers viewing workers' schedules and preferences, feasibility checks and rule overrides; "time-off:manage", "swaps:manage", "rules:manage", "teams:manage" and "roles:manage" cover managing time off requests, shift swaps, rule sets, teams and roles.
+type Permission string
+
+// PreferenceId defines model for PreferenceId.
+type PreferenceId = int64
+
+// PreferenceWeight defines model for PreferenceWeight.
+type PreferenceWeight string
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	// RecoveryCodes Single-use codes to use when the authenticator app isn't available
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ReplacementCandidate defines model for ReplacementCandidate.
+type ReplacementCandidate struct {
+	// Hours Hours already assigned in the week of the shift
+	Hours    float64  `json:"hours"`
+	WorkerId WorkerId `json:"worker_id"`
+}
+
+// Role A named set of permissions, such as "scheduler" or "payroll viewer"
+type Role struct {
+	Id          *RoleId      `json:"id,omitempty"`
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// RoleId defines model for RoleId.
+type RoleId = int64
+
+// Rule defines model for Rule.
+type Rule struct {
+	// Limit Rule parameter: minimum hours of rest, maximum hours per week, or maximum number of consecutive days or nights (not used for the same-day rule)
+	Limit *float64 `json:"limit,omitempty"`
+	Name  RuleName `json:"name"`
+
+	// Soft Soft rules are penalised by the solvers rather than enforced
+	Soft *bool `json:"soft,omitempty"`
+}
+
+// RuleName defines model for RuleName.
+type RuleName string
+
+// RuleOverride defines model for RuleOverride.
+type RuleOverride struct {
+	// AdminId Admin who made the assignment
+	AdminId   WorkerId  `json:"admin_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Id        int64     `json:"id"`
+	Reason    string    `json:"reason"`
+	ShiftId   ShiftId   `json:"shift_id"`
+
+	// Violations Explanation of the soft rules broken
+	Violations string   `json:"violations"`
+	WorkerId   WorkerId `json:"worker_id"`
+}
+
+// RuleSet defines model for RuleSet.
+type RuleSet struct {
+	// EffectiveFrom First day the rule set applies (defaults to today)
+	EffectiveFrom *openapi_types.Date `json:"effective_from,omitempty"`
+
+	// Id Rule set ID (missing for the built-in default rules)
+	Id    *RuleSetId `json:"id,omitempty"`
+	Rules []Rule     `json:"rules"`
+}
+
+// RuleSetId defines model for RuleSetId.
+type RuleSetId = int64
+
+// ScheduleProposal defines model for ScheduleProposal.
+type ScheduleProposal struct {
+	// Assignments New shift assignments (existing assignments are not included)
+	Assignments []ShiftAssignment `json:"assignments"`
+
+	// Committed Whether the proposed shift assignments were committed
+	Committed bool `json:"committed"`
+
+	// History Best score after each iteration (for iterative solvers)
+	History *[]float64 `json:"history,omitempty"`
+
+	// Score Schedule penalty score (lower is better, zero means all shifts are full)
+	Score float64 `json:"score"`
+}
+
+// ScheduleRequest defines model for ScheduleRequest.
+type ScheduleRequest struct {
+	Annealing *AnnealingOptions `json:"annealing,omitempty"`
+
+	// EndDate Last day of schedule (inclusive)
+	EndDate openapi_types.Date `json:"end_date"`
+	Genetic *GeneticOptions    `json:"genetic,omitempty"`
+
+	// Solver Solver algorithm to use (defaults to "greedy")
+	Solver *ScheduleRequestSolver `json:"solver,omitempty"`
+
+	// StartDate First day of schedule
+	StartDate openapi_types.Date `json:"start_date"`
+}
+
+// ScheduleRequestSolver Solver algorithm to use (defaults to "greedy")
+type ScheduleRequestSolver string
+
 // Shift defines model for Shift.
 type Shift struct {
 	AssignedWorkers *[]WorkerId `json:"assigned_workers,omitempty"`
-	Capacity        int32       `json:"capacity"`
-	EndTime         time.Time   `json:"end_time"`
-	Id              *ShiftId    `json:"id,omitempty"`
-	StartTime       time.Time   `json:"start_time"`
+
+	// BiddingClosed Whether bidding for the shift has been resolved. After that, workers can only join the shift's waitlist when it's full.
+	BiddingClosed *bool     `json:"bidding_closed,omitempty"`
+	Capacity      int32     `json:"capacity"`
+	EndTime       time.Time `json:"end_time"`
+	Id            *ShiftId  `json:"id,omitempty"`
+
+	// Requirements Places reserved for workers with particular skills (each worker fills at most one of them). Any other places can be filled by any worker.
+	Requirements *[]ShiftRequirement `json:"requirements,omitempty"`
+	StartTime    time.Time           `json:"start_time"`
+
+	// TeamId Team that owns the shift. Only members of the team can work it. Shifts without a team are open to every worker.
+	TeamId *TeamId `json:"team_id,omitempty"`
+}
+
+// ShiftAssignment defines model for ShiftAssignment.
+type ShiftAssignment struct {
+	ShiftId  ShiftId  `json:"shift_id"`
+	WorkerId WorkerId `json:"worker_id"`
+}
+
+// ShiftGeneration defines model for ShiftGeneration.
+type ShiftGeneration struct {
+	// EndDate Last day to generate shifts for (inclusive)
+	EndDate openapi_types.Date `json:"end_date"`
+
+	// StartDate First day to generate shifts for
+	StartDate openapi_types.Date `json:"start_date"`
+
+	// TemplateIds Templates to use (defaults to all templates)
+	TemplateIds *[]ShiftTemplateId `json:"template_ids,omitempty"`
+}
+
+// ShiftGenerationResult defines model for ShiftGenerationResult.
+type ShiftGenerationResult struct {
+	// Created New shifts
+	Created []Shift `json:"created"`
+
+	// Skipped Existing shifts matching generated ones
+	Skipped []Shift `json:"skipped"`
 }
 
 // ShiftId defines model for ShiftId.
 type ShiftId = int64
 
+// ShiftPreference A worker's preference either for a single shift (if shift_id is given) or for a recurring pattern of shifts (matching weekday, start hour or both).
+type ShiftPreference struct {
+	Id        *PreferenceId    `json:"id,omitempty"`
+	ShiftId   *ShiftId         `json:"shift_id,omitempty"`
+	StartHour *int32           `json:"start_hour,omitempty"`
+	Weekday   *Weekday         `json:"weekday,omitempty"`
+	Weight    PreferenceWeight `json:"weight"`
+	WorkerId  *WorkerId        `json:"worker_id,omitempty"`
+}
+
+// ShiftRequirement A number of places on a shift reserved for workers with a skill
+type ShiftRequirement struct {
+	Count int32 `json:"count"`
+	Skill Skill `json:"skill"`
+}
+
+// ShiftShortfall defines model for ShiftShortfall.
+type ShiftShortfall struct {
+	ShiftId ShiftId `json:"shift_id"`
+
+	// Unfilled Number of places that can't be filled
+	Unfilled int32 `json:"unfilled"`
+}
+
+// ShiftTemplate A recurring shift, happening on each of a set of weekdays between two hours of the day. If end_hour isn't after start_hour, the shift runs overnight into the next day.
+type ShiftTemplate struct {
+	Capacity  int32            `json:"capacity"`
+	EndHour   int32            `json:"end_hour"`
+	Id        *ShiftTemplateId `json:"id,omitempty"`
+	Name      *string          `json:"name,omitempty"`
+	StartHour int32            `json:"start_hour"`
+
+	// TeamId Team that owns the shifts generated from the template
+	TeamId   *TeamId   `json:"team_id,omitempty"`
+	Weekdays []Weekday `json:"weekdays"`
+}
+
+// ShiftTemplateId defines model for ShiftTemplateId.
+type ShiftTemplateId = int64
+
+// Skill defines model for Skill.
+type Skill = string
+
+// Swap defines model for Swap.
+type Swap struct {
+	CounterShiftId *ShiftId   `json:"counter_shift_id,omitempty"`
+	Id             SwapId     `json:"id"`
+	OffererId      WorkerId   `json:"offerer_id"`
+	ShiftId        ShiftId    `json:"shift_id"`
+	Status         SwapStatus `json:"status"`
+	TakerId        *WorkerId  `json:"taker_id,omitempty"`
+}
+
+// SwapId defines model for SwapId.
+type SwapId = int64
+
+// SwapOffer defines model for SwapOffer.
+type SwapOffer struct {
+	ShiftId ShiftId `json:"shift_id"`
+}
+
+// SwapProposal defines model for SwapProposal.
+type SwapProposal struct {
+	// CounterShiftId Shift to give the offering worker in exchange (omit to take the shift outright)
+	CounterShiftId *ShiftId `json:"counter_shift_id,omitempty"`
+}
+
+// SwapStatus defines model for SwapStatus.
+type SwapStatus string
+
+// Team defines model for Team.
+type Team struct {
+	Id   *TeamId `json:"id,omitempty"`
+	Name string  `json:"name"`
+}
+
+// TeamId defines model for TeamId.
+type TeamId = int64
+
+// TeamMember defines model for TeamMember.
+type TeamMember struct {
+	// IsAdmin Whether the worker can manage the team's shifts and members
+	IsAdmin bool `json:"is_admin"`
+}
+
+// TeamMembership defines model for TeamMembership.
+type TeamMembership struct {
+	// IsAdmin Whether the worker can manage the team's shifts and members
+	IsAdmin bool   `json:"is_admin"`
+	TeamId  TeamId `json:"team_id"`
+}
+
+// TimeOff defines model for TimeOff.
+type TimeOff struct {
+	EndTime   time.Time      `json:"end_time"`
+	Id        *TimeOffId     `json:"id,omitempty"`
+	Reason    *string        `json:"reason,omitempty"`
+	StartTime time.Time      `json:"start_time"`
+	Status    *TimeOffStatus `json:"status,omitempty"`
+	WorkerId  *WorkerId      `json:"worker_id,omitempty"`
+}
+
+// TimeOffDecision defines model for TimeOffDecision.
+type TimeOffDecision struct {
+	Status TimeOffDecisionStatus `json:"status"`
+}
+
+// TimeOffDecisionStatus defines model for TimeOffDecision.Status.
+type TimeOffDecisionStatus string
+
+// TimeOffDecisionResult defines model for TimeOffDecisionResult.
+type TimeOffDecisionResult struct {
+	// Conflicts Shifts the worker is assigned to during approved time off
+	Conflicts []Shift `json:"conflicts"`
+	TimeOff   TimeOff `json:"time_off"`
+}
+
+// TimeOffId defines model for TimeOffId.
+type TimeOffId = int64
+
+// TimeOffStatus defines model for TimeOffStatus.
+type TimeOffStatus string
+
+// TwoFactorChallenge The second step of a login. Workers who haven't enrolled for two-factor authentication but must use it get a new TOTP secret to enrol with, and completing the login turns it on.
+type TwoFactorChallenge struct {
+	ChallengeToken string              `json:"challenge_token"`
+	Enrolment      *TwoFactorEnrolment `json:"enrolment,omitempty"`
+}
+
+// TwoFactorCode defines model for TwoFactorCode.
+type TwoFactorCode struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorEnrolment defines model for TwoFactorEnrolment.
+type TwoFactorEnrolment struct {
+	// OtpauthUrl otpauth:// URL for authenticator apps, usually shown as a QR code
+	OtpauthUrl string `json:"otpauth_url"`
+
+	// Secret Base32-encoded TOTP secret
+	Secret string `json:"secret"`
+}
+
+// TwoFactorLogin defines model for TwoFactorLogin.
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token"`
+
+	// Code TOTP code, or a recovery code
+	Code string `json:"code"`
+}
+
+// TwoFactorStatus defines model for TwoFactorStatus.
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// Vacancy defines model for Vacancy.
+type Vacancy struct {
+	// Candidates Possible replacements, best first
+	Candidates  []ReplacementCandidate `json:"candidates"`
+	Replacement *WorkerId              `json:"replacement,omitempty"`
+	Shift       Shift                  `json:"shift"`
+}
+
+// Weekday defines model for Weekday.
+type Weekday string
+
 // Worker defines model for Worker.
 type Worker struct {
-	Email    string    `json:"email"`
-	Id       *WorkerId `json:"id,omitempty"`
-	IsAdmin  bool      `json:"is_admin"`
-	Name     string    `json:"name"`
-	Password *string   `json:"password,omitempty"`
+	Email   string    `json:"email"`
+	Id      *WorkerId `json:"id,omitempty"`
+	IsAdmin bool      `json:"is_admin"`
+	Name    string    `json:"name"`
+
+	// Password Password to set for the worker. Workers created without one can't log in until they accept an invitation, and updating a worker without one keeps their current password.
+	Password *string `json:"password,omitempty"`
+
+	// Roles Roles giving the worker permissions
+	Roles *[]RoleId `json:"roles,omitempty"`
+
+	// Skills Skills or roles the worker is qualified for
+	Skills *[]Skill `json:"skills,omitempty"`
+
+	// Teams Teams the worker belongs to
+	Teams *[]TeamMembership `json:"teams,omitempty"`
 }
 
 // WorkerId defines model for WorkerId.
 type WorkerId = int64
 
+// BidIdParam defines model for BidIdParam.
+type BidIdParam = BidId
+
+// OverrideParam defines model for OverrideParam.
+type OverrideParam = bool
+
+// OverrideReasonParam defines model for OverrideReasonParam.
+type OverrideReasonParam = string
+
+// PreferenceIdParam defines model for PreferenceIdParam.
+type PreferenceIdParam = PreferenceId
+
+// RoleIdParam defines model for RoleIdParam.
+type RoleIdParam = RoleId
+
+// RuleSetIdParam defines model for RuleSetIdParam.
+type RuleSetIdParam = RuleSetId
+
 // ShiftIdParam defines model for ShiftIdParam.
 type ShiftIdParam = ShiftId
 
+// ShiftTemplateIdParam defines model for ShiftTemplateIdParam.
+type ShiftTemplateIdParam = ShiftTemplateId
+
 // SpanDate defines model for SpanDate.
 type SpanDate = openapi_types.Date
 
 // SpanLength defines model for SpanLength.
 type SpanLength string
 
+// SwapIdParam defines model for SwapIdParam.
+type SwapIdParam = SwapId
+
+// TeamFilter defines model for TeamFilter.
+type TeamFilter = TeamId
+
+// TeamIdParam defines model for TeamIdParam.
+type TeamIdParam = TeamId
+
+// TimeOffIdParam defines model for TimeOffIdParam.
+type TimeOffIdParam = TimeOffId
+
 // WorkerIdParam defines model for WorkerIdParam.
 type WorkerIdParam = WorkerId
 
+// DeleteMeTwoFactorParams defines parameters for DeleteMeTwoFactor.
+type DeleteMeTwoFactorParams struct {
+	// Code Current TOTP code or a recovery code
+	Code string `form:"code" json:"code"`
+}
+
 // GetMeScheduleParams defines parameters for GetMeSchedule.
 type GetMeScheduleParams struct {
 	// Date Date including in weekly schedule to fetch (defaults to today)
@@ -118,6 +730,30 @@ type GetMeScheduleParams struct {
 // GetMeScheduleParamsSpan defines parameters for GetMeSchedule.
 type GetMeScheduleParamsSpan string
 
+// GetRuleOverridesParams defines parameters for GetRuleOverrides.
+type GetRuleOverridesParams struct {
+	// ShiftId Only return overrides for this shift
+	ShiftId *ShiftId `form:"shift-id,omitempty" json:"shift-id,omitempty"`
+}
+
+// GetScheduleFeasibilityParams defines parameters for GetScheduleFeasibility.
+type GetScheduleFeasibilityParams struct {
+	// Date Date including in weekly schedule to fetch (defaults to today)
+	Date *SpanDate `form:"date,omitempty" json:"date,omitempty"`
+
+	// Span Span of schedule ("week" or "day", defaults to "week")
+	Span *GetScheduleFeasibilityParamsSpan `form:"span,omitempty" json:"span,omitempty"`
+}
+
+// GetScheduleFeasibilityParamsSpan defines parameters for GetScheduleFeasibility.
+type GetScheduleFeasibilityParamsSpan string
+
+// SolveScheduleParams defines parameters for SolveSchedule.
+type SolveScheduleParams struct {
+	// Commit Commit the proposed shift assignments (defaults to false)
+	Commit *bool `form:"commit,omitempty" json:"commit,omitempty"`
+}
+
 // GetShiftsParams defines parameters for GetShifts.
 type GetShiftsParams struct {
 	// Date Date including in weekly schedule to fetch (defaults to today)
@@ -125,11 +761,59 @@ type GetShiftsParams struct {
 
 	// Span Span of schedule ("week" or "day", defaults to "week")
 	Span *GetShiftsParamsSpan `form:"span,omitempty" json:"span,omitempty"`
+
+	// Team Only include items belonging to this team
+	Team *TeamFilter `form:"team,omitempty" json:"team,omitempty"`
 }
 
 // GetShiftsParamsSpan defines parameters for GetShifts.
 type GetShiftsParamsSpan string
 
+// CreateWorkerShiftAssignmentParams defines parameters for CreateWorkerShiftAssignment.
+type CreateWorkerShiftAssignmentParams struct {
+	// Override Allow soft scheduling rules to be broken (defaults to false)
+	Override *OverrideParam `form:"override,omitempty" json:"override,omitempty"`
+
+	// Reason Reason for overriding scheduling rules (required with override)
+	Reason *OverrideReasonParam `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// MoveWorkerShiftAssignmentParams defines parameters for MoveWorkerShiftAssignment.
+type MoveWorkerShiftAssignmentParams struct {
+	// To Shift to move the worker to
+	To ShiftId `form:"to" json:"to"`
+
+	// Override Allow soft scheduling rules to be broken (defaults to false)
+	Override *OverrideParam `form:"override,omitempty" json:"override,omitempty"`
+
+	// Reason Reason for overriding scheduling rules (required with override)
+	Reason *OverrideReasonParam `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// ResolveShiftBidsParams defines parameters for ResolveShiftBids.
+type ResolveShiftBidsParams struct {
+	// Policy Policy for ranking bids (defaults to "first-come")
+	Policy *BidPolicy `form:"policy,omitempty" json:"policy,omitempty"`
+}
+
+// GetTimeOffRequestsParams defines parameters for GetTimeOffRequests.
+type GetTimeOffRequestsParams struct {
+	// Status Only return requests with this status
+	Status *TimeOffStatus `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetWorkersParams defines parameters for GetWorkers.
+type GetWorkersParams struct {
+	// Team Only include items belonging to this team
+	Team *TeamFilter `form:"team,omitempty" json:"team,omitempty"`
+}
+
+// CreateWorkerAbsenceParams defines parameters for CreateWorkerAbsence.
+type CreateWorkerAbsenceParams struct {
+	// Apply Assign the best replacement candidates (defaults to false)
+	Apply *bool `form:"apply,omitempty" json:"apply,omitempty"`
+}
+
 // GetWorkerScheduleParams defines parameters for GetWorkerSchedule.
 type GetWorkerScheduleParams struct {
 	// Date Date including in weekly schedule to fetch (defaults to today)
@@ -142,41 +826,212 @@ type GetWorkerScheduleParams struct {
 // GetWorkerScheduleParamsSpan defines parameters for GetWorkerSchedule.
 type GetWorkerScheduleParamsSpan string
 
+// PostLoginTwoFactorJSONRequestBody defines body for PostLoginTwoFactor for application/json ContentType.
+type PostLoginTwoFactorJSONRequestBody = TwoFactorLogin
+
+// AcceptInvitationJSONRequestBody defines body for AcceptInvitation for application/json ContentType.
+type AcceptInvitationJSONRequestBody = PasswordTokenRedemption
+
 // PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
 type PostLoginJSONRequestBody = Login
 
+// RequestPasswordResetJSONRequestBody defines body for RequestPasswordReset for application/json ContentType.
+type RequestPasswordResetJSONRequestBody = PasswordResetRequest
+
+// ConfirmPasswordResetJSONRequestBody defines body for ConfirmPasswordReset for application/json ContentType.
+type ConfirmPasswordResetJSONRequestBody = PasswordTokenRedemption
+
 // PostRefreshTokenJSONRequestBody defines body for PostRefreshToken for application/json ContentType.
 type PostRefreshTokenJSONRequestBody = CredentialsRefresh
 
+// ConfirmMeTwoFactorJSONRequestBody defines body for ConfirmMeTwoFactor for application/json ContentType.
+type ConfirmMeTwoFactorJSONRequestBody = TwoFactorCode
+
+// ChangeMePasswordJSONRequestBody defines body for ChangeMePassword for application/json ContentType.
+type ChangeMePasswordJSONRequestBody = PasswordChange
+
+// CreateMePreferenceJSONRequestBody defines body for CreateMePreference for application/json ContentType.
+type CreateMePreferenceJSONRequestBody = ShiftPreference
+
+// UpdateMePreferenceJSONRequestBody defines body for UpdateMePreference for application/json ContentType.
+type UpdateMePreferenceJSONRequestBody = ShiftPreference
+
+// CreateMeSwapJSONRequestBody defines body for CreateMeSwap for application/json ContentType.
+type CreateMeSwapJSONRequestBody = SwapOffer
+
+// CreateMeTimeOffJSONRequestBody defines body for CreateMeTimeOff for application/json ContentType.
+type CreateMeTimeOffJSONRequestBody = TimeOff
+
+// CreateRoleJSONRequestBody defines body for CreateRole for application/json ContentType.
+type CreateRoleJSONRequestBody = Role
+
+// UpdateRoleJSONRequestBody defines body for UpdateRole for application/json ContentType.
+type UpdateRoleJSONRequestBody = Role
+
+// CreateRuleSetJSONRequestBody defines body for CreateRuleSet for application/json ContentType.
+type CreateRuleSetJSONRequestBody = RuleSet
+
+// SolveScheduleJSONRequestBody defines body for SolveSchedule for application/json ContentType.
+type SolveScheduleJSONRequestBody = ScheduleRequest
+
 // CreateShiftJSONRequestBody defines body for CreateShift for application/json ContentType.
 type CreateShiftJSONRequestBody = Shift
 
 // UpdateShiftJSONRequestBody defines body for UpdateShift for application/json ContentType.
 type UpdateShiftJSONRequestBody = Shift
 
+// CreateShiftTemplateJSONRequestBody defines body for CreateShiftTemplate for application/json ContentType.
+type CreateShiftTemplateJSONRequestBody = ShiftTemplate
+
+// GenerateShiftsJSONRequestBody defines body for GenerateShifts for application/json ContentType.
+type GenerateShiftsJSONRequestBody = ShiftGeneration
+
+// ProposeSwapJSONRequestBody defines body for ProposeSwap for application/json ContentType.
+type ProposeSwapJSONRequestBody = SwapProposal
+
+// CreateTeamJSONRequestBody defines body for CreateTeam for application/json ContentType.
+type CreateTeamJSONRequestBody = Team
+
+// SetTeamMemberJSONRequestBody defines body for SetTeamMember for application/json ContentType.
+type SetTeamMemberJSONRequestBody = TeamMember
+
+// DecideTimeOffRequestJSONRequestBody defines body for DecideTimeOffRequest for application/json ContentType.
+type DecideTimeOffRequestJSONRequestBody = TimeOffDecision
+
 // CreateWorkerJSONRequestBody defines body for CreateWorker for application/json ContentType.
 type CreateWorkerJSONRequestBody = Worker
 
 // UpdateWorkerJSONRequestBody defines body for UpdateWorker for application/json ContentType.
 type UpdateWorkerJSONRequestBody = Worker
 
+// CreateWorkerAbsenceJSONRequestBody defines body for CreateWorkerAbsence for application/json ContentType.
+type CreateWorkerAbsenceJSONRequestBody = Absence
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Complete a login with a two-factor authentication code
+	// (POST /auth/2fa)
+	PostLoginTwoFactor(ctx echo.Context) error
+	// Accept an invitation, setting an initial password
+	// (POST /auth/invitation)
+	AcceptInvitation(ctx echo.Context) error
 
 	// (POST /auth/login)
 	PostLogin(ctx echo.Context) error
-
+	// Log out, revoking the access and refresh tokens from current user's login
 	// (POST /auth/logout)
 	PostLogout(ctx echo.Context) error
+	// Request a password reset token by email
+	// (POST /auth/password_reset)
+	RequestPasswordReset(ctx echo.Context) error
+	// Reset a password using a reset token, logging the worker out everywhere
+	// (POST /auth/password_reset/confirm)
+	ConfirmPasswordReset(ctx echo.Context) error
 
 	// (POST /auth/refresh_token)
 	PostRefreshToken(ctx echo.Context) error
+	// Get audit records of login lockouts, newest first
+	// (GET /lockouts)
+	GetLockouts(ctx echo.Context) error
 	// Get information about current user
 	// (GET /me)
 	GetMe(ctx echo.Context) error
+	// Turn off two-factor authentication for current user
+	// (DELETE /me/2fa)
+	DeleteMeTwoFactor(ctx echo.Context, params DeleteMeTwoFactorParams) error
+	// Get current user's two-factor authentication status
+	// (GET /me/2fa)
+	GetMeTwoFactor(ctx echo.Context) error
+	// Start enrolling current user for two-factor authentication
+	// (POST /me/2fa)
+	EnrolMeTwoFactor(ctx echo.Context) error
+	// Confirm two-factor enrolment with a code, turning it on
+	// (POST /me/2fa/confirm)
+	ConfirmMeTwoFactor(ctx echo.Context) error
+	// Get shift bids and waitlist places for current user
+	// (GET /me/bids)
+	GetMeBids(ctx echo.Context) error
+	// Withdraw a shift bid or leave a waitlist
+	// (DELETE /me/bids/{bid-id})
+	DeleteMeBid(ctx echo.Context, bidId BidIdParam) error
+	// Change current user's password, logging them out everywhere
+	// (PUT /me/password)
+	ChangeMePassword(ctx echo.Context) error
+	// Get shift preferences for current user
+	// (GET /me/preferences)
+	GetMePreferences(ctx echo.Context) error
+	// Create new shift preference for current user
+	// (POST /me/preferences)
+	CreateMePreference(ctx echo.Context) error
+	// Update an existing shift preference for current user
+	// (PUT /me/preferences)
+	UpdateMePreference(ctx echo.Context) error
+	// Delete a shift preference for current user
+	// (DELETE /me/preferences/{preference-id})
+	DeleteMePreference(ctx echo.Context, preferenceId PreferenceIdParam) error
+	// Get a single shift preference for current user
+	// (GET /me/preferences/{preference-id})
+	GetMePreference(ctx echo.Context, preferenceId PreferenceIdParam) error
 	// Get schedule information for current user
 	// (GET /me/schedule)
 	GetMeSchedule(ctx echo.Context, params GetMeScheduleParams) error
+	// Get shift swaps offered or proposed by current user
+	// (GET /me/swaps)
+	GetMeSwaps(ctx echo.Context) error
+	// Offer one of current user's shifts for swap
+	// (POST /me/swaps)
+	CreateMeSwap(ctx echo.Context) error
+	// Cancel a shift swap offered by current user
+	// (DELETE /me/swaps/{swap-id})
+	CancelMeSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Get time off requests for current user
+	// (GET /me/time-off)
+	GetMeTimeOff(ctx echo.Context) error
+	// Request time off for current user
+	// (POST /me/time-off)
+	CreateMeTimeOff(ctx echo.Context) error
+	// Cancel a time off request for current user
+	// (DELETE /me/time-off/{time-off-id})
+	DeleteMeTimeOff(ctx echo.Context, timeOffId TimeOffIdParam) error
+	// Get records of admin rule overrides
+	// (GET /overrides)
+	GetRuleOverrides(ctx echo.Context, params GetRuleOverridesParams) error
+	// Get all roles
+	// (GET /roles)
+	GetRoles(ctx echo.Context) error
+	// Create new role
+	// (POST /roles)
+	CreateRole(ctx echo.Context) error
+	// Update an existing role
+	// (PUT /roles)
+	UpdateRole(ctx echo.Context) error
+	// Delete a role, taking it away from its workers
+	// (DELETE /roles/{role-id})
+	DeleteRole(ctx echo.Context, roleId RoleIdParam) error
+	// Get a single role
+	// (GET /roles/{role-id})
+	GetRole(ctx echo.Context, roleId RoleIdParam) error
+	// Get all versions of the scheduling rule set
+	// (GET /rules)
+	GetRuleSets(ctx echo.Context) error
+	// Create a new version of the scheduling rule set
+	// (POST /rules)
+	CreateRuleSet(ctx echo.Context) error
+	// Get the scheduling rule set currently in effect
+	// (GET /rules/current)
+	GetCurrentRuleSet(ctx echo.Context) error
+	// Delete a version of the scheduling rule set
+	// (DELETE /rules/{rule-set-id})
+	DeleteRuleSet(ctx echo.Context, ruleSetId RuleSetIdParam) error
+	// Get a single version of the scheduling rule set
+	// (GET /rules/{rule-set-id})
+	GetRuleSet(ctx echo.Context, ruleSetId RuleSetIdParam) error
+	// Check how much of the shift capacity for a span of time can be covered
+	// (GET /schedule/feasibility)
+	GetScheduleFeasibility(ctx echo.Context, params GetScheduleFeasibilityParams) error
+	// Generate a schedule for a range of dates
+	// (POST /schedule/solve)
+	SolveSchedule(ctx echo.Context, params SolveScheduleParams) error
 	// Get shifts for a span of time
 	// (GET /shift)
 	GetShifts(ctx echo.Context, params GetShiftsParams) error
@@ -186,6 +1041,18 @@ type ServerInterface interface {
 	// Update an existing shift
 	// (PUT /shift)
 	UpdateShift(ctx echo.Context) error
+	// Get all shift templates
+	// (GET /shift-templates)
+	GetShiftTemplates(ctx echo.Context) error
+	// Create new shift template
+	// (POST /shift-templates)
+	CreateShiftTemplate(ctx echo.Context) error
+	// Delete a shift template
+	// (DELETE /shift-templates/{template-id})
+	DeleteShiftTemplate(ctx echo.Context, templateId ShiftTemplateIdParam) error
+	// Generate shifts from templates over a range of dates
+	// (POST /shift/generate)
+	GenerateShifts(ctx echo.Context) error
 	// Delete an existing shift
 	// (DELETE /shift/{shift-id})
 	DeleteShift(ctx echo.Context, shiftId ShiftIdParam) error
@@ -198,9 +1065,78 @@ type ServerInterface interface {
 	// Create new shift assignment
 	// (POST /shift/{shift-id}/assignment)
 	CreateShiftAssignment(ctx echo.Context, shiftId ShiftIdParam) error
+	// Remove a worker from a shift
+	// (DELETE /shift/{shift-id}/assignment/{worker-id})
+	DeleteWorkerShiftAssignment(ctx echo.Context, shiftId ShiftIdParam, workerId WorkerIdParam) error
+	// Assign a worker to a shift
+	// (POST /shift/{shift-id}/assignment/{worker-id})
+	CreateWorkerShiftAssignment(ctx echo.Context, shiftId ShiftIdParam, workerId WorkerIdParam, params CreateWorkerShiftAssignmentParams) error
+	// Move a worker from one shift to another
+	// (PUT /shift/{shift-id}/assignment/{worker-id}/move)
+	MoveWorkerShiftAssignment(ctx echo.Context, shiftId ShiftIdParam, workerId WorkerIdParam, params MoveWorkerShiftAssignmentParams) error
+	// Get bids and waitlist for a shift
+	// (GET /shift/{shift-id}/bids)
+	GetShiftBids(ctx echo.Context, shiftId ShiftIdParam) error
+	// Bid for a shift, or join its waitlist if it's full
+	// (POST /shift/{shift-id}/bids)
+	CreateShiftBid(ctx echo.Context, shiftId ShiftIdParam) error
+	// Close bidding for a shift and assign places to bidders
+	// (POST /shift/{shift-id}/bids/resolve)
+	ResolveShiftBids(ctx echo.Context, shiftId ShiftIdParam, params ResolveShiftBidsParams) error
+	// Get open shift swap offers that current user can take
+	// (GET /swaps)
+	GetOpenSwaps(ctx echo.Context) error
+	// Get shift swaps awaiting admin approval
+	// (GET /swaps/pending)
+	GetPendingSwaps(ctx echo.Context) error
+	// Accept the proposal for a shift swap offered by current user
+	// (PUT /swaps/{swap-id}/accept)
+	AcceptSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Approve an accepted shift swap
+	// (PUT /swaps/{swap-id}/approve)
+	ApproveSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Decline the proposal for a shift swap offered by current user
+	// (PUT /swaps/{swap-id}/decline)
+	DeclineSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Propose to take an offered shift, optionally in exchange for another
+	// (POST /swaps/{swap-id}/proposal)
+	ProposeSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Reject an accepted shift swap
+	// (PUT /swaps/{swap-id}/reject)
+	RejectSwap(ctx echo.Context, swapId SwapIdParam) error
+	// Get all teams
+	// (GET /teams)
+	GetTeams(ctx echo.Context) error
+	// Create new team
+	// (POST /teams)
+	CreateTeam(ctx echo.Context) error
+	// Delete a team, along with its shifts
+	// (DELETE /teams/{team-id})
+	DeleteTeam(ctx echo.Context, teamId TeamIdParam) error
+	// Get a single team
+	// (GET /teams/{team-id})
+	GetTeam(ctx echo.Context, teamId TeamIdParam) error
+	// Get the members of a team
+	// (GET /teams/{team-id}/members)
+	GetTeamMembers(ctx echo.Context, teamId TeamIdParam) error
+	// Remove a worker from a team
+	// (DELETE /teams/{team-id}/members/{worker-id})
+	DeleteTeamMember(ctx echo.Context, teamId TeamIdParam, workerId WorkerIdParam) error
+	// Add a worker to a team, or change whether they're a team admin
+	// (PUT /teams/{team-id}/members/{worker-id})
+	SetTeamMember(ctx echo.Context, teamId TeamIdParam, workerId WorkerIdParam) error
+	// Get time off requests for all workers
+	// (GET /time-off)
+	GetTimeOffRequests(ctx echo.Context, params GetTimeOffRequestsParams) error
+	// Get a single time off request
+	// (GET /time-off/{time-off-id})
+	GetTimeOffRequest(ctx echo.Context, timeOffId TimeOffIdParam) error
+	// Approve or reject a time off request
+	// (PUT /time-off/{time-off-id})
+	DecideTimeOffRequest(ctx echo.Context, timeOffId TimeOffIdParam) error
 	// Get all workers
 	// (GET /worker)
-	GetWorkers(ctx echo.Context) error
+	GetWorkers(ctx echo.Context, params GetWorkersParams) error
 	// Create new worker
 	// (POST /worker)
 	CreateWorker(ctx echo.Context) error
@@ -213,6 +1149,21 @@ type ServerInterface interface {
 	// Get a single worker
 	// (GET /worker/{worker-id})
 	GetWorker(ctx echo.Context, workerId WorkerIdParam) error
+	// Record a sick call and find replacements for a worker
+	// (POST /worker/{worker-id}/absence)
+	CreateWorkerAbsence(ctx echo.Context, workerId WorkerIdParam, params CreateWorkerAbsenceParams) error
+	// Email a worker an invitation link to set their initial password
+	// (POST /worker/{worker-id}/invitation)
+	InviteWorker(ctx echo.Context, workerId WorkerIdParam) error
+	// Unlock a worker's account after too many failed logins
+	// (DELETE /worker/{worker-id}/lockout)
+	UnlockWorker(ctx echo.Context, workerId WorkerIdParam) error
+	// Log a worker out everywhere, revoking all their tokens
+	// (POST /worker/{worker-id}/logout)
+	LogoutWorker(ctx echo.Context, workerId WorkerIdParam) error
+	// Get shift preferences for a single worker
+	// (GET /worker/{worker-id}/preferences)
+	GetWorkerPreferences(ctx echo.Context, workerId WorkerIdParam) error
 	// Get schedule for a single worker
 	// (GET /worker/{worker-id}/schedule)
 	GetWorkerSchedule(ctx echo.Context, workerId WorkerIdParam, params GetWorkerScheduleParams) error
@@ -223,189 +1174,1219 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
-// PostLogin converts echo context to params.
-func (w *ServerInterfaceWrapper) PostLogin(ctx echo.Context) error {
+// PostLoginTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginTwoFactor(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.PostLogin(ctx)
+	err = w.Handler.PostLoginTwoFactor(ctx)
 	return err
 }
 
-// PostLogout converts echo context to params.
-func (w *ServerInterfaceWrapper) PostLogout(ctx echo.Context) error {
+// AcceptInvitation converts echo context to params.
+func (w *ServerInterfaceWrapper) AcceptInvitation(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.PostLogout(ctx)
+	err = w.Handler.AcceptInvitation(ctx)
 	return err
 }
 
-// PostRefreshToken converts echo context to params.
-func (w *ServerInterfaceWrapper) PostRefreshToken(ctx echo.Context) error {
+// PostLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLogin(ctx echo.Context) error {
 	var err error
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.PostRefreshToken(ctx)
+	err = w.Handler.PostLogin(ctx)
+	return err
+}
+
+// PostLogout converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLogout(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostLogout(ctx)
+	return err
+}
+
+// RequestPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) RequestPasswordReset(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RequestPasswordReset(ctx)
+	return err
+}
+
+// ConfirmPasswordReset converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmPasswordReset(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ConfirmPasswordReset(ctx)
+	return err
+}
+
+// PostRefreshToken converts echo context to params.
+func (w *ServerInterfaceWrapper) PostRefreshToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostRefreshToken(ctx)
+	return err
+}
+
+// GetLockouts converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLockouts(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"workers:read"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetLockouts(ctx)
+	return err
+}
+
+// GetMe converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMe(ctx)
+	return err
+}
+
+// DeleteMeTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteMeTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteMeTwoFactorParams
+	// ------------- Required query parameter "code" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "code", ctx.QueryParams(), &params.Code)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter code: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteMeTwoFactor(ctx, params)
+	return err
+}
+
+// GetMeTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMeTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMeTwoFactor(ctx)
+	return err
+}
+
+// EnrolMeTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) EnrolMeTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.EnrolMeTwoFactor(ctx)
+	return err
+}
+
+// ConfirmMeTwoFactor converts echo context to params.
+func (w *ServerInterfaceWrapper) ConfirmMeTwoFactor(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ConfirmMeTwoFactor(ctx)
+	return err
+}
+
+// GetMeBids converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMeBids(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMeBids(ctx)
+	return err
+}
+
+// DeleteMeBid converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteMeBid(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "bid-id" -------------
+	var bidId BidIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "bid-id", runtime.ParamLocationPath, ctx.Param("bid-id"), &bidId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter bid-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteMeBid(ctx, bidId)
+	return err
+}
+
+// ChangeMePassword converts echo context to params.
+func (w *ServerInterfaceWrapper) ChangeMePassword(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ChangeMePassword(ctx)
+	return err
+}
+
+// GetMePreferences converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMePreferences(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMePreferences(ctx)
+	return err
+}
+
+// CreateMePreference converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateMePreference(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateMePreference(ctx)
+	return err
+}
+
+// UpdateMePreference converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateMePreference(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateMePreference(ctx)
+	return err
+}
+
+// DeleteMePreference converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteMePreference(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "preference-id" -------------
+	var preferenceId PreferenceIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "preference-id", runtime.ParamLocationPath, ctx.Param("preference-id"), &preferenceId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter preference-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteMePreference(ctx, preferenceId)
+	return err
+}
+
+// GetMePreference converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMePreference(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "preference-id" -------------
+	var preferenceId PreferenceIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "preference-id", runtime.ParamLocationPath, ctx.Param("preference-id"), &preferenceId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter preference-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMePreference(ctx, preferenceId)
+	return err
+}
+
+// GetMeSchedule converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMeSchedule(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMeScheduleParams
+	// ------------- Optional query parameter "date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "date", ctx.QueryParams(), &params.Date)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// ------------- Optional query parameter "span" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "span", ctx.QueryParams(), &params.Span)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter span: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMeSchedule(ctx, params)
+	return err
+}
+
+// GetMeSwaps converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMeSwaps(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMeSwaps(ctx)
+	return err
+}
+
+// CreateMeSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateMeSwap(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateMeSwap(ctx)
+	return err
+}
+
+// CancelMeSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelMeSwap(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CancelMeSwap(ctx, swapId)
+	return err
+}
+
+// GetMeTimeOff converts echo context to params.
+func (w *ServerInterfaceWrapper) GetMeTimeOff(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetMeTimeOff(ctx)
+	return err
+}
+
+// CreateMeTimeOff converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateMeTimeOff(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateMeTimeOff(ctx)
+	return err
+}
+
+// DeleteMeTimeOff converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteMeTimeOff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "time-off-id" -------------
+	var timeOffId TimeOffIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "time-off-id", runtime.ParamLocationPath, ctx.Param("time-off-id"), &timeOffId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter time-off-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteMeTimeOff(ctx, timeOffId)
+	return err
+}
+
+// GetRuleOverrides converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRuleOverrides(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"schedule:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRuleOverridesParams
+	// ------------- Optional query parameter "shift-id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shift-id", ctx.QueryParams(), &params.ShiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRuleOverrides(ctx, params)
+	return err
+}
+
+// GetRoles converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRoles(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"roles:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRoles(ctx)
+	return err
+}
+
+// CreateRole converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRole(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"roles:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateRole(ctx)
+	return err
+}
+
+// UpdateRole converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRole(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"roles:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRole(ctx)
+	return err
+}
+
+// DeleteRole converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteRole(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "role-id" -------------
+	var roleId RoleIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "role-id", runtime.ParamLocationPath, ctx.Param("role-id"), &roleId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter role-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"roles:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteRole(ctx, roleId)
+	return err
+}
+
+// GetRole converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRole(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "role-id" -------------
+	var roleId RoleIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "role-id", runtime.ParamLocationPath, ctx.Param("role-id"), &roleId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter role-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"roles:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRole(ctx, roleId)
+	return err
+}
+
+// GetRuleSets converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRuleSets(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"rules:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRuleSets(ctx)
+	return err
+}
+
+// CreateRuleSet converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRuleSet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"rules:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateRuleSet(ctx)
+	return err
+}
+
+// GetCurrentRuleSet converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCurrentRuleSet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCurrentRuleSet(ctx)
+	return err
+}
+
+// DeleteRuleSet converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteRuleSet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "rule-set-id" -------------
+	var ruleSetId RuleSetIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "rule-set-id", runtime.ParamLocationPath, ctx.Param("rule-set-id"), &ruleSetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rule-set-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"rules:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteRuleSet(ctx, ruleSetId)
+	return err
+}
+
+// GetRuleSet converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRuleSet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "rule-set-id" -------------
+	var ruleSetId RuleSetIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "rule-set-id", runtime.ParamLocationPath, ctx.Param("rule-set-id"), &ruleSetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rule-set-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"rules:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRuleSet(ctx, ruleSetId)
+	return err
+}
+
+// GetScheduleFeasibility converts echo context to params.
+func (w *ServerInterfaceWrapper) GetScheduleFeasibility(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"schedule:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetScheduleFeasibilityParams
+	// ------------- Optional query parameter "date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "date", ctx.QueryParams(), &params.Date)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// ------------- Optional query parameter "span" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "span", ctx.QueryParams(), &params.Span)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter span: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetScheduleFeasibility(ctx, params)
+	return err
+}
+
+// SolveSchedule converts echo context to params.
+func (w *ServerInterfaceWrapper) SolveSchedule(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SolveScheduleParams
+	// ------------- Optional query parameter "commit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "commit", ctx.QueryParams(), &params.Commit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter commit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SolveSchedule(ctx, params)
+	return err
+}
+
+// GetShifts converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShifts(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShiftsParams
+	// ------------- Optional query parameter "date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "date", ctx.QueryParams(), &params.Date)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+	}
+
+	// ------------- Optional query parameter "span" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "span", ctx.QueryParams(), &params.Span)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter span: %s", err))
+	}
+
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetShifts(ctx, params)
+	return err
+}
+
+// CreateShift converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateShift(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateShift(ctx)
+	return err
+}
+
+// UpdateShift converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateShift(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateShift(ctx)
+	return err
+}
+
+// GetShiftTemplates converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShiftTemplates(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:read"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetShiftTemplates(ctx)
+	return err
+}
+
+// CreateShiftTemplate converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateShiftTemplate(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateShiftTemplate(ctx)
+	return err
+}
+
+// DeleteShiftTemplate converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteShiftTemplate(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "template-id" -------------
+	var templateId ShiftTemplateIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "template-id", runtime.ParamLocationPath, ctx.Param("template-id"), &templateId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter template-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteShiftTemplate(ctx, templateId)
+	return err
+}
+
+// GenerateShifts converts echo context to params.
+func (w *ServerInterfaceWrapper) GenerateShifts(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GenerateShifts(ctx)
+	return err
+}
+
+// DeleteShift converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteShift(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:write", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteShift(ctx, shiftId)
+	return err
+}
+
+// GetShift converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShift(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetShift(ctx, shiftId)
+	return err
+}
+
+// DeleteShiftAssignment converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteShiftAssignment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteShiftAssignment(ctx, shiftId)
+	return err
+}
+
+// CreateShiftAssignment converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateShiftAssignment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateShiftAssignment(ctx, shiftId)
+	return err
+}
+
+// DeleteWorkerShiftAssignment converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWorkerShiftAssignment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteWorkerShiftAssignment(ctx, shiftId, workerId)
+	return err
+}
+
+// CreateWorkerShiftAssignment converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateWorkerShiftAssignment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write", "team"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateWorkerShiftAssignmentParams
+	// ------------- Optional query parameter "override" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "override", ctx.QueryParams(), &params.Override)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter override: %s", err))
+	}
+
+	// ------------- Optional query parameter "reason" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reason", ctx.QueryParams(), &params.Reason)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reason: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateWorkerShiftAssignment(ctx, shiftId, workerId, params)
+	return err
+}
+
+// MoveWorkerShiftAssignment converts echo context to params.
+func (w *ServerInterfaceWrapper) MoveWorkerShiftAssignment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write", "team"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params MoveWorkerShiftAssignmentParams
+	// ------------- Required query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "override" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "override", ctx.QueryParams(), &params.Override)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter override: %s", err))
+	}
+
+	// ------------- Optional query parameter "reason" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reason", ctx.QueryParams(), &params.Reason)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reason: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.MoveWorkerShiftAssignment(ctx, shiftId, workerId, params)
+	return err
+}
+
+// GetShiftBids converts echo context to params.
+func (w *ServerInterfaceWrapper) GetShiftBids(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"shifts:read", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetShiftBids(ctx, shiftId)
+	return err
+}
+
+// CreateShiftBid converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateShiftBid(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateShiftBid(ctx, shiftId)
 	return err
 }
 
-// GetMe converts echo context to params.
-func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
+// ResolveShiftBids converts echo context to params.
+func (w *ServerInterfaceWrapper) ResolveShiftBids(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "shift-id" -------------
+	var shiftId ShiftIdParam
 
-	ctx.Set(BearerAuthScopes, []string{""})
+	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write", "team"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ResolveShiftBidsParams
+	// ------------- Optional query parameter "policy" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "policy", ctx.QueryParams(), &params.Policy)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter policy: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetMe(ctx)
+	err = w.Handler.ResolveShiftBids(ctx, shiftId, params)
 	return err
 }
 
-// GetMeSchedule converts echo context to params.
-func (w *ServerInterfaceWrapper) GetMeSchedule(ctx echo.Context) error {
+// GetOpenSwaps converts echo context to params.
+func (w *ServerInterfaceWrapper) GetOpenSwaps(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{""})
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetMeScheduleParams
-	// ------------- Optional query parameter "date" -------------
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetOpenSwaps(ctx)
+	return err
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "date", ctx.QueryParams(), &params.Date)
+// GetPendingSwaps converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPendingSwaps(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"swaps:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetPendingSwaps(ctx)
+	return err
+}
+
+// AcceptSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) AcceptSwap(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
 	}
 
-	// ------------- Optional query parameter "span" -------------
+	ctx.Set(BearerAuthScopes, []string{""})
 
-	err = runtime.BindQueryParameter("form", true, false, "span", ctx.QueryParams(), &params.Span)
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.AcceptSwap(ctx, swapId)
+	return err
+}
+
+// ApproveSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) ApproveSwap(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter span: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{"swaps:manage"})
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetMeSchedule(ctx, params)
+	err = w.Handler.ApproveSwap(ctx, swapId)
 	return err
 }
 
-// GetShifts converts echo context to params.
-func (w *ServerInterfaceWrapper) GetShifts(ctx echo.Context) error {
+// DeclineSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) DeclineSwap(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{""})
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetShiftsParams
-	// ------------- Optional query parameter "date" -------------
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeclineSwap(ctx, swapId)
+	return err
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "date", ctx.QueryParams(), &params.Date)
+// ProposeSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) ProposeSwap(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter date: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
 	}
 
-	// ------------- Optional query parameter "span" -------------
+	ctx.Set(BearerAuthScopes, []string{""})
 
-	err = runtime.BindQueryParameter("form", true, false, "span", ctx.QueryParams(), &params.Span)
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ProposeSwap(ctx, swapId)
+	return err
+}
+
+// RejectSwap converts echo context to params.
+func (w *ServerInterfaceWrapper) RejectSwap(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "swap-id" -------------
+	var swapId SwapIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "swap-id", runtime.ParamLocationPath, ctx.Param("swap-id"), &swapId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter span: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter swap-id: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{"swaps:manage"})
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetShifts(ctx, params)
+	err = w.Handler.RejectSwap(ctx, swapId)
 	return err
 }
 
-// CreateShift converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateShift(ctx echo.Context) error {
+// GetTeams converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTeams(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{""})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.CreateShift(ctx)
+	err = w.Handler.GetTeams(ctx)
 	return err
 }
 
-// UpdateShift converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateShift(ctx echo.Context) error {
+// CreateTeam converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTeam(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"teams:manage"})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateShift(ctx)
+	err = w.Handler.CreateTeam(ctx)
 	return err
 }
 
-// DeleteShift converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteShift(ctx echo.Context) error {
+// DeleteTeam converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTeam(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shift-id" -------------
-	var shiftId ShiftIdParam
+	// ------------- Path parameter "team-id" -------------
+	var teamId TeamIdParam
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "team-id", runtime.ParamLocationPath, ctx.Param("team-id"), &teamId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"teams:manage"})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteShift(ctx, shiftId)
+	err = w.Handler.DeleteTeam(ctx, teamId)
 	return err
 }
 
-// GetShift converts echo context to params.
-func (w *ServerInterfaceWrapper) GetShift(ctx echo.Context) error {
+// GetTeam converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTeam(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shift-id" -------------
-	var shiftId ShiftIdParam
+	// ------------- Path parameter "team-id" -------------
+	var teamId TeamIdParam
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "team-id", runtime.ParamLocationPath, ctx.Param("team-id"), &teamId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team-id: %s", err))
 	}
 
 	ctx.Set(BearerAuthScopes, []string{""})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetShift(ctx, shiftId)
+	err = w.Handler.GetTeam(ctx, teamId)
 	return err
 }
 
-// DeleteShiftAssignment converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteShiftAssignment(ctx echo.Context) error {
+// GetTeamMembers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTeamMembers(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shift-id" -------------
-	var shiftId ShiftIdParam
+	// ------------- Path parameter "team-id" -------------
+	var teamId TeamIdParam
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "team-id", runtime.ParamLocationPath, ctx.Param("team-id"), &teamId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{""})
+	ctx.Set(BearerAuthScopes, []string{"teams:manage", "team"})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteShiftAssignment(ctx, shiftId)
+	err = w.Handler.GetTeamMembers(ctx, teamId)
 	return err
 }
 
-// CreateShiftAssignment converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateShiftAssignment(ctx echo.Context) error {
+// DeleteTeamMember converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTeamMember(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "shift-id" -------------
-	var shiftId ShiftIdParam
+	// ------------- Path parameter "team-id" -------------
+	var teamId TeamIdParam
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "shift-id", runtime.ParamLocationPath, ctx.Param("shift-id"), &shiftId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "team-id", runtime.ParamLocationPath, ctx.Param("team-id"), &teamId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter shift-id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{""})
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"teams:manage", "team"})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.CreateShiftAssignment(ctx, shiftId)
+	err = w.Handler.DeleteTeamMember(ctx, teamId, workerId)
+	return err
+}
+
+// SetTeamMember converts echo context to params.
+func (w *ServerInterfaceWrapper) SetTeamMember(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "team-id" -------------
+	var teamId TeamIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "team-id", runtime.ParamLocationPath, ctx.Param("team-id"), &teamId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team-id: %s", err))
+	}
+
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"teams:manage", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SetTeamMember(ctx, teamId, workerId)
+	return err
+}
+
+// GetTimeOffRequests converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTimeOffRequests(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{"time-off:manage"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTimeOffRequestsParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTimeOffRequests(ctx, params)
+	return err
+}
+
+// GetTimeOffRequest converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTimeOffRequest(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "time-off-id" -------------
+	var timeOffId TimeOffIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "time-off-id", runtime.ParamLocationPath, ctx.Param("time-off-id"), &timeOffId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter time-off-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"time-off:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTimeOffRequest(ctx, timeOffId)
+	return err
+}
+
+// DecideTimeOffRequest converts echo context to params.
+func (w *ServerInterfaceWrapper) DecideTimeOffRequest(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "time-off-id" -------------
+	var timeOffId TimeOffIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "time-off-id", runtime.ParamLocationPath, ctx.Param("time-off-id"), &timeOffId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter time-off-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"time-off:manage"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DecideTimeOffRequest(ctx, timeOffId)
 	return err
 }
 
@@ -413,10 +2394,19 @@ func (w *ServerInterfaceWrapper) CreateShiftAssignment(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) GetWorkers(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"workers:read", "team"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetWorkersParams
+	// ------------- Optional query parameter "team" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team", ctx.QueryParams(), &params.Team)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter team: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetWorkers(ctx)
+	err = w.Handler.GetWorkers(ctx, params)
 	return err
 }
 
@@ -424,7 +2414,7 @@ func (w *ServerInterfaceWrapper) GetWorkers(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) CreateWorker(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.CreateWorker(ctx)
@@ -435,7 +2425,7 @@ func (w *ServerInterfaceWrapper) CreateWorker(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) UpdateWorker(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.UpdateWorker(ctx)
@@ -453,7 +2443,7 @@ func (w *ServerInterfaceWrapper) DeleteWorker(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.DeleteWorker(ctx, workerId)
@@ -471,13 +2461,112 @@ func (w *ServerInterfaceWrapper) GetWorker(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"workers:read", "team"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetWorker(ctx, workerId)
 	return err
 }
 
+// CreateWorkerAbsence converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateWorkerAbsence(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"assignments:write", "team"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateWorkerAbsenceParams
+	// ------------- Optional query parameter "apply" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "apply", ctx.QueryParams(), &params.Apply)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apply: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateWorkerAbsence(ctx, workerId, params)
+	return err
+}
+
+// InviteWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) InviteWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.InviteWorker(ctx, workerId)
+	return err
+}
+
+// UnlockWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) UnlockWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UnlockWorker(ctx, workerId)
+	return err
+}
+
+// LogoutWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) LogoutWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"workers:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.LogoutWorker(ctx, workerId)
+	return err
+}
+
+// GetWorkerPreferences converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWorkerPreferences(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker-id" -------------
+	var workerId WorkerIdParam
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "worker-id", runtime.ParamLocationPath, ctx.Param("worker-id"), &workerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{"schedule:read", "team"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetWorkerPreferences(ctx, workerId)
+	return err
+}
+
 // GetWorkerSchedule converts echo context to params.
 func (w *ServerInterfaceWrapper) GetWorkerSchedule(ctx echo.Context) error {
 	var err error
@@ -489,7 +2578,7 @@ func (w *ServerInterfaceWrapper) GetWorkerSchedule(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker-id: %s", err))
 	}
 
-	ctx.Set(BearerAuthScopes, []string{"admin"})
+	ctx.Set(BearerAuthScopes, []string{"schedule:read", "team"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetWorkerScheduleParams
@@ -540,23 +2629,91 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/auth/2fa", wrapper.PostLoginTwoFactor)
+	router.POST(baseURL+"/auth/invitation", wrapper.AcceptInvitation)
 	router.POST(baseURL+"/auth/login", wrapper.PostLogin)
 	router.POST(baseURL+"/auth/logout", wrapper.PostLogout)
+	router.POST(baseURL+"/auth/password_reset", wrapper.RequestPasswordReset)
+	router.POST(baseURL+"/auth/password_reset/confirm", wrapper.ConfirmPasswordReset)
 	router.POST(baseURL+"/auth/refresh_token", wrapper.PostRefreshToken)
+	router.GET(baseURL+"/lockouts", wrapper.GetLockouts)
 	router.GET(baseURL+"/me", wrapper.GetMe)
+	router.DELETE(baseURL+"/me/2fa", wrapper.DeleteMeTwoFactor)
+	router.GET(baseURL+"/me/2fa", wrapper.GetMeTwoFactor)
+	router.POST(baseURL+"/me/2fa", wrapper.EnrolMeTwoFactor)
+	router.POST(baseURL+"/me/2fa/confirm", wrapper.ConfirmMeTwoFactor)
+	router.GET(baseURL+"/me/bids", wrapper.GetMeBids)
+	router.DELETE(baseURL+"/me/bids/:bid-id", wrapper.DeleteMeBid)
+	router.PUT(baseURL+"/me/password", wrapper.ChangeMePassword)
+	router.GET(baseURL+"/me/preferences", wrapper.GetMePreferences)
+	router.POST(baseURL+"/me/preferences", wrapper.CreateMePreference)
+	router.PUT(baseURL+"/me/preferences", wrapper.UpdateMePreference)
+	router.DELETE(baseURL+"/me/preferences/:preference-id", wrapper.DeleteMePreference)
+	router.GET(baseURL+"/me/preferences/:preference-id", wrapper.GetMePreference)
 	router.GET(baseURL+"/me/schedule", wrapper.GetMeSchedule)
+	router.GET(baseURL+"/me/swaps", wrapper.GetMeSwaps)
+	router.POST(baseURL+"/me/swaps", wrapper.CreateMeSwap)
+	router.DELETE(baseURL+"/me/swaps/:swap-id", wrapper.CancelMeSwap)
+	router.GET(baseURL+"/me/time-off", wrapper.GetMeTimeOff)
+	router.POST(baseURL+"/me/time-off", wrapper.CreateMeTimeOff)
+	router.DELETE(baseURL+"/me/time-off/:time-off-id", wrapper.DeleteMeTimeOff)
+	router.GET(baseURL+"/overrides", wrapper.GetRuleOverrides)
+	router.GET(baseURL+"/roles", wrapper.GetRoles)
+	router.POST(baseURL+"/roles", wrapper.CreateRole)
+	router.PUT(baseURL+"/roles", wrapper.UpdateRole)
+	router.DELETE(baseURL+"/roles/:role-id", wrapper.DeleteRole)
+	router.GET(baseURL+"/roles/:role-id", wrapper.GetRole)
+	router.GET(baseURL+"/rules", wrapper.GetRuleSets)
+	router.POST(baseURL+"/rules", wrapper.CreateRuleSet)
+	router.GET(baseURL+"/rules/current", wrapper.GetCurrentRuleSet)
+	router.DELETE(baseURL+"/rules/:rule-set-id", wrapper.DeleteRuleSet)
+	router.GET(baseURL+"/rules/:rule-set-id", wrapper.GetRuleSet)
+	router.GET(baseURL+"/schedule/feasibility", wrapper.GetScheduleFeasibility)
+	router.POST(baseURL+"/schedule/solve", wrapper.SolveSchedule)
 	router.GET(baseURL+"/shift", wrapper.GetShifts)
 	router.POST(baseURL+"/shift", wrapper.CreateShift)
 	router.PUT(baseURL+"/shift", wrapper.UpdateShift)
+	router.GET(baseURL+"/shift-templates", wrapper.GetShiftTemplates)
+	router.POST(baseURL+"/shift-templates", wrapper.CreateShiftTemplate)
+	router.DELETE(baseURL+"/shift-templates/:template-id", wrapper.DeleteShiftTemplate)
+	router.POST(baseURL+"/shift/generate", wrapper.GenerateShifts)
 	router.DELETE(baseURL+"/shift/:shift-id", wrapper.DeleteShift)
 	router.GET(baseURL+"/shift/:shift-id", wrapper.GetShift)
 	router.DELETE(baseURL+"/shift/:shift-id/assignment", wrapper.DeleteShiftAssignment)
 	router.POST(baseURL+"/shift/:shift-id/assignment", wrapper.CreateShiftAssignment)
+	router.DELETE(baseURL+"/shift/:shift-id/assignment/:worker-id", wrapper.DeleteWorkerShiftAssignment)
+	router.POST(baseURL+"/shift/:shift-id/assignment/:worker-id", wrapper.CreateWorkerShiftAssignment)
+	router.PUT(baseURL+"/shift/:shift-id/assignment/:worker-id/move", wrapper.MoveWorkerShiftAssignment)
+	router.GET(baseURL+"/shift/:shift-id/bids", wrapper.GetShiftBids)
+	router.POST(baseURL+"/shift/:shift-id/bids", wrapper.CreateShiftBid)
+	router.POST(baseURL+"/shift/:shift-id/bids/resolve", wrapper.ResolveShiftBids)
+	router.GET(baseURL+"/swaps", wrapper.GetOpenSwaps)
+	router.GET(baseURL+"/swaps/pending", wrapper.GetPendingSwaps)
+	router.PUT(baseURL+"/swaps/:swap-id/accept", wrapper.AcceptSwap)
+	router.PUT(baseURL+"/swaps/:swap-id/approve", wrapper.ApproveSwap)
+	router.PUT(baseURL+"/swaps/:swap-id/decline", wrapper.DeclineSwap)
+	router.POST(baseURL+"/swaps/:swap-id/proposal", wrapper.ProposeSwap)
+	router.PUT(baseURL+"/swaps/:swap-id/reject", wrapper.RejectSwap)
+	router.GET(baseURL+"/teams", wrapper.GetTeams)
+	router.POST(baseURL+"/teams", wrapper.CreateTeam)
+	router.DELETE(baseURL+"/teams/:team-id", wrapper.DeleteTeam)
+	router.GET(baseURL+"/teams/:team-id", wrapper.GetTeam)
+	router.GET(baseURL+"/teams/:team-id/members", wrapper.GetTeamMembers)
+	router.DELETE(baseURL+"/teams/:team-id/members/:worker-id", wrapper.DeleteTeamMember)
+	router.PUT(baseURL+"/teams/:team-id/members/:worker-id", wrapper.SetTeamMember)
+	router.GET(baseURL+"/time-off", wrapper.GetTimeOffRequests)
+	router.GET(baseURL+"/time-off/:time-off-id", wrapper.GetTimeOffRequest)
+	router.PUT(baseURL+"/time-off/:time-off-id", wrapper.DecideTimeOffRequest)
 	router.GET(baseURL+"/worker", wrapper.GetWorkers)
 	router.POST(baseURL+"/worker", wrapper.CreateWorker)
 	router.PUT(baseURL+"/worker", wrapper.UpdateWorker)
 	router.DELETE(baseURL+"/worker/:worker-id", wrapper.DeleteWorker)
 	router.GET(baseURL+"/worker/:worker-id", wrapper.GetWorker)
+	router.POST(baseURL+"/worker/:worker-id/absence", wrapper.CreateWorkerAbsence)
+	router.POST(baseURL+"/worker/:worker-id/invitation", wrapper.InviteWorker)
+	router.DELETE(baseURL+"/worker/:worker-id/lockout", wrapper.UnlockWorker)
+	router.POST(baseURL+"/worker/:worker-id/logout", wrapper.LogoutWorker)
+	router.GET(baseURL+"/worker/:worker-id/preferences", wrapper.GetWorkerPreferences)
 	router.GET(baseURL+"/worker/:worker-id/schedule", wrapper.GetWorkerSchedule)
 
 }
@@ -564,31 +2721,168 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RaS28bNxD+KwTbQwpsLDUxetAtj6ZwkQJGnSIH2zDo5UhisktuSG5UwdB/L4bc93Kl",
-	"lSUrBXwylq/55pvhzHDkBxqrNFMSpDV09kAzplkKFrT7ulqKub3glziI3xxMrEVmhZJ05mfJxXsaUYHf",
-	"GbNLGlHJUqAzanD2peA0ohq+5UIDpzOrc4ioiZeQMjzwZw1zOqM/TWoUEz9rJoVwutlE9Cpj8j2z0AeB",
-	"o0TIOMm5kAsiJFkBfE3WBI/heQLEKjIHGy/JCw5zlifW4JBVnK1/KaF/y0Gva+wcRTVxzpVOma1n7Dpz",
-	"Olot5KJC+BHkwi4DRGVMEjWvIb24oYjyhhKlyQ3lbH1DI9KEVy4YQmgyJlsIi8105jbSiILMUzq7Lj85",
-	"W9PbEPDPSn8FPWhkPz1o5ZWbPsTMpXy6QTTFKG56p4GDtIIl3jO1ykBbAe6LxTEYc2fVV5D43dELwcw1",
-	"mOXgik0T73X7vO7umjd1/wVii+c30P3tF/dB7olht9DftVa6LycFY9gCdksoF4bO/qgWQvbPhpSJJMhw",
-	"xoxZKc1b96MajHZA8ec2TglhciEgYHxjxEICv/Pe58aEhdSM97VKGNOarfE7ZhmLhV231BHSvn5V6yKk",
-	"hQVoXA6S31mRQi86vHSjUZ8vwUdHvIgay7TdS0CH38YBDbANNQf5vuBdCn47D1Lg2dzHZXZT0LSQMHeM",
-	"p6J5c+6VSoBJnPUB6Mn80h3fwBCiqwI7hi80KsS5FnZ9hcp6rt4C06Df5D5t3LuvD+VRf37+VMZ4p7yb",
-	"rY9eWpv5qCnkXDkuhE2gysyXCZMSk+Kbywsa0e+gjY/p07Nfz6aogMpAskzQGX19Nj2buutolw7YhOV2",
-	"OUmqoKCMu4hoZ4apAbWml8pYHzc8iWDsW8XdFYqVtCDdHpZliYjdrskXo9x543KDP3vTthEmFzdgMiWN",
-	"p/HVdHo0oc2840R3knnuUsU8T4hnZxPR8yOK9zE+IPhCfmeJ4MR7Gv5pAnh9OgBOKolbNNXOTWfXeFfY",
-	"wri8mtslrvJI6C2urFxL5Xanb+GanrHPAzVWyyy46zGgehl7GFuR9T9VFcPx3T9QY/xv74JjjOgS5o+7",
-	"EwEgJ7gbH5hIgGPlXkgmvqL0cPb1RZ/ZFhBwvT/A/gX0CU1eZPXt1tZgtYDvLMFnTW5AE84s82rmacr0",
-	"2kMlmJnQNEJJwu5Vbkmcaw3Sul205sGXcpX+k/KttJ2Iq3JV1Hq5XodVrJdMqgflJhq1tnjabW4PZH5U",
-	"merL3l6NuqdFKgb7Vqkeok3z4OUZME6xHuslbyBTFuZDpnEqmGdtlp5VjOckYA434QzAiCmaBWWVX5rA",
-	"Cb/F+jaYkt5pYBY8xKfJRoX6p01ADaGDrh+j6ujBJcfdcNsus69pUdGj19SG8AQSCavikCD3eYD6fzL+",
-	"XKnPneqHEe/pI0wS+FcYiw+WIQNUoWfyULYXN74UTMA3B9uWee/GS8vsGYqavc9AgDkfaoiaip5kTTwy",
-	"/ihiPPpRxETbA/HRlT+Zl4WDaCCGMmKEXCSwl+9MfCcpLbQY4UZv6g0nc6ga5Bbf2uk1jWOGMvuI3HJi",
-	"/Ssf6MX4pjptArqBfITi6Burqps1dJU+F93GU9QaZRX++BrQ60MSYR4Xlt2lShKyqrTulerb/aVQ4Wky",
-	"YvOVcrqUOOpt1HTVVbnhoHpkVTIZssBwRfJcDdAoSnxKOMAKgeJk2Bp1GJk8VD+MjShQKkPtF1Dbv9zt",
-	"E1EdTQ7T4X4ayDfb/HV7cD0+Bz+yMXK4+7Uqm30db1QPxWv16D5Kh//omTdeqsZK8ZY/igNsPTTkDVvl",
-	"OAHFnm50cL+3REUDnTDJO93U6mf/TtcSbRn6twHT/UeBwMqqU9T8v5HQurpgq9fWY5vbzX8BAAD///XR",
-	"2P7MIgAA",
+	"H4sIAAAAAAAC/+y9bXPcuK4g/FdY/TxVSao6diZn7laN76ck87K5NXOSTXI2HyYpH7qFdnMikTok5U7f",
+	"lP/7FkBSoiTqpd1229mz+RK3RJEgAAIgAILfFitVlEqCtGZx9m1Rcs0LsKDp10uRvc7e4iP8lYFZaVFa",
+	"oeTibPF+I9aWXYiMvf55sVwIfFZyu1ksF5IXsDhbXIjsqcgWy4WGf1VCQ7Y4s7qC5cKsNlBw7PL/17Be",
+	"nC3+v9MGilP31pzS4Ivr6+XizRVoLTIYgORFnqstM2ptGX6bVbmQl0xXORhmFbsAdqHVF5DscQZrXuWW",
+	"Hq95buBJgPxfFehdA7ryIy5iYP3XizP6dLmwu5KmqVQOXLYgfQfcKDkAr3vJ1kozPw7C2wP9cUAb2wq7",
+	"CU0HQdbUawtgD6CxWshLgu+thjVokCuYoGtZNxwkb9PkECrHEBGI71Q+DBy+HARIq/wgUNzIDogqh/dg",
+	"h+GocmAG7DAsVQ5PDdiD4AlAEEhElwmqDUFj8O0hoPjBG0A+QFHm3E6xkfXNBiELDQ4GrgHIAVly+TO3",
+	"0AcMnzIhV3lFy05ItgX4ku/CAgQSDmBXm7a8sCrju6HFl+FQMbxrpQtumzf9pYgQ/g7y0m4SyCu5ZGrd",
+	"gPT40wKh/LRgSrNPi4zvPi2WLAYvNBiC0JRcpqUZfbhYLkBWxeLsz/Az47vF5yTgW15OkN1seTnMjFte",
+	"HkRuGp9A+QC8+FXkFnQfkjcy33k6AxMWCsMuIFfyEqmO9NwIwyzwYgBh/tU8mBCQCKZB9ODLkbXAi0MQ",
+	"EwMhCnizXg/DIQpgar1mOBKYYclhRQFP1Xp9EFwBGALto9JfQA9C5l4PwrOl14dAE8ZfXCM0/il+9OLC",
+	"oBrCP0utStBWAL0AmZ1nSVnyOzeWZXyHa5W7z9lj4jkjrkhTT8iB5cJYru1A978K3et/us/rGDN/xgMs",
+	"m6k0K1td/AUri6B4BLwDQ3KhiwZelrmArA/mxw3YDWhmN8A0lDlfQYEoZ1vQwLgx4lJCtugbTAhooa4g",
+	"G5Ij7lvXmW9KZhOORPiwzDEEMgsu8Vmq4kXd6+K6BoprzXf4+4qvuFz5Oc/q9H/TF7t+Zx1KhMnGYyxr",
+	"tCYpIiVwNAnfEF5MnygrpfB9W6BfgirAarFaLDtofeWaR4qlrUPqL50iCSoh7jAXErhOqIZlAOZcJ7kZ",
+	"NTRobisNrKhyK3DempWgUTxrjs2IuvVozHfYhvLZyU8//UTQffUL7Q/+VRRVEeRA81zI+HmzblR1kcNi",
+	"uSjChz8sF0Vo/KyemayKC9A4MyGFFTw/t80cEjyLK420SzTTFug/PHv2ZJEEZHTwgB3TH/Pv1ArlQ9Oq",
+	"N2ZnVCHt3563Zv+M/kVg/FCDIaSFSweHgdRKfcdlpgrmwGWXIBEMpRm2bkPCGWmTC24gY1c8r6AL1v/4",
+	"cdEf+DqxMF6KLLEWNHAL2Tm3PQvsKY6cEr8im1rdfhu6XJTKCDfnLgreoshDSxLlEtnajwzbcmFzYSx7",
+	"jDwdfkGGW2azZCYwy1qrgv3wZJHEOPZ1LrJZYs1BaSy3lZkxqfeu4fXS69QZw0Sqsy3ZSB033USA1wAt",
+	"Y/J8TpP0ddYi3BBDUNu3KhernVPPTkitUVs+XSkitAEplBZ2hxwGWzD26UZVGuFo9q1JIdagJuq7BIm7",
+	"BZxlTcjFcsG3XGeQJft5pSEDiVIjIbb5agXGnFt0SyR26ojdFW74d+crlUFq3cOW2a16uuYrXGyhOaPm",
+	"S8bXFjQDqVVOAjSrsGOWq0shY13ZG7erDTWsNZjNIKQdRmjNq/t1iuoRlt65xn1k7QnD9KC/aK10f5wC",
+	"jOGXMD1CaJjq+1fgRlyIHFmvL6F4yVf+TUc5KsvzIETVmpEVZVCm8Dx3MsWkZHh/bRT86zkxg59Kexyv",
+	"Kvsj2Q23bMUlusvWIs8hmzdcJbE1v8gTg5FYarp+ZJvOaWbMayAW4I0Mvr0suvcbpe2a5/mkDVZToIOo",
+	"1kRSdP0NJFixGjbEtDIGe2ubYkrC01IJmk/HFAsfMFV6ldkxxupv28ZY3GUlBdIoKYC8Kp6yG6JmbQCe",
+	"zzMbpqyGorK8UZsBL2gJ91Dyh286iBH8qo0M3886F2USCWH0AZu0HrHU6oK7ZUsWacOIrOBWi68MpNW7",
+	"riX67D8G7Lm5hmWpyip3EBrx3zBBKFU4mQB8tYkI14bqP+aQLQLr+QO39X5Xqy+qsgm/f5UJS6pPZ4gg",
+	"0m7o5XGqbvUFMq8IrVKs4HLH1lzklUYNiUYZl4yvVqqSlj0m062iQZkwZMlBwUXOeJZpMIZVBrInDL9i",
+	"q1wgY7x+G15+kmRXxPIgjBRpkwi9Yq6l80XILDZEPLyL5WKA4d2897KB/SeVtCKf/5VHVtKKuCWLkibf",
+	"jLRskBrPszOBz0kWQrun79hBAicnUHJjtkq3qVQ/nHK5uH6jXlIwvfUvX224vEx4nVaV1iDt+Z6gLBcS",
+	"tueHwd8butPp2HTegQH7znkV52M8icCxYT6gcfcOMijK9J7sBSP7z22vuGRCXomgXTQLU2EawXUrfeni",
+	"bLjyJWxZNPn2HPYmyEy7NRjN42gGXQhjBqZc1m+dfGOXWlUlykZVBiV/gqECWnbmTAPPPi0Yl1n0cKuF",
+	"hU8LZ5SxKwFblKfYpuCSk/vcN/1P9snt9Oqe6BtTfwS0J7HAi0fGG7FL938dGTLUs9sTOzh8jy0wTDO2",
+	"e01tOz0hOJHHsNuDexWBj3oqAOW9q2aJoCBXqLwiZqFxgrfMqPxKyEuat382MHM/xKP6Wwdxs/dEFdRs",
+	"FdhqA6svrg2GDutoL00qOOHPCAmAoZ9PFEYxrSf4YfsJYr554vGrVdzMk7lGr+1EBmqC0XhLB50BfE69",
+	"O5CxyxPSgnUUKWKx2jngaRIcBPXbmOSLZZ+IPg5T43ux7OIEW0QIWSxb2MD2ESrwbYSEpB5txaXn6erm",
+	"k48gLjc21tvGaiUv8905v1Kk28L/EiqreV77JRbLpq1/kgLvnd/wvwruge6Wedx98F7IyxyeVgacxwCX",
+	"Av7YbsB5sXhlNyCtWJF9x8uSCYMbOH7Fhdskzfci9PbnLdhSUu5dE0V4xWUmQnikPUnn0OnN7X/iY8Zz",
+	"ZJRdHX4I7jmMbqJArF11Sfu9Z6cfbtHE7jEHeXLmKrWRfsEkLyCjjAO1jsQ8rs5qtWHcRCJJhzBxyXfo",
+	"ACKhhE97qmx6OiEnIgTgUrZSA83skEmkxqbYhcZtjzKEuNkrFTMr+vyUi0LYgVyPOi/qjPmNEyMiIjk0",
+	"GLusfRnuMe4hkdWWSImi53RZKWlgVVlxBRjfM9hKosww7LFUljYbdaTL8AKeYhQQRdoTkrIzODbQayrD",
+	"5O/YDu15tbapLKeO4FBr6/OTuAZWguS5QGAvdg5WlZMe1NyHBLlkINdKr5IRwBStk9QNgMYi1aPFhU+e",
+	"IhXcNvepS+ioHb74KEI4fpN67PCflrZVDiG1K+HMzQohvWjgef5mvTj7c66Q+NxF8AvsjG03uGHNwMni",
+	"Vrzy5lGOGQvDJ5GllvkNYhFXQuVDDqhfvpY5l94g9+K44S2XsJeayt0GK2pSLpt8umgWk0EMn7KV2Pis",
+	"17BCNjvHDclYqB8xEYws5oLDZiATaTK5YB+ejLLNekwZpbuxxySG5WUtni4qkdunQoZ0JEfCJ8RO+Nds",
+	"pYCjTFsP1OUI7mcrgfdeXb7VqlSG54mF3dih6QiM6WUpPIavwlBcL36KohLFuk9Hyp7cYr7CShWFsHYq",
+	"MaOkWUKWgJnSNJpuUnkaG2Gs0om4xUswlpmV0hBiTuiabOL5FPz0P69q9dCa/wxd1p0zDZiwasM+jdSS",
+	"3XnAHudqCxq9ehdgLegl+2/QihXApYnCK0SldZXnT+Yo2G7gK2KVAF9MmxTDBnAHvSU8pH9MsUkvT+R6",
+	"OTNrqUkE2S9t6dKFQ6YA60RNyMzIe0GSSw2Q7RZ9WwObMp5fKi3spgiblG7OCn3dSVgJPTYobGD+fEga",
+	"VkDYHeZh0aIfkkaQnfu99Gy52mjC/kq6EBkGts9XOQqHYRHi2zU2KUmRDcc1BdI5TK4gO2EvnMN9w+2y",
+	"9rNgWFFhOuZfaihJgjaeAh/hCnSuBNzAYRZnyN/pi6U4njojVolYJxPpFtNDInPHU3tAX7x1cVYNBnTI",
+	"YwsIIsdjybUVqyrnmpkvIs9RmaAwdY0obmoYt6xQxjIlwZtNxZMT9kLumCI6+WhuK5CLtjkGP1xHDrfz",
+	"tc+7ZlZJUUxsvR9WLfBiL3s5ZLb2DBN84eLLaitNw1onjNJ/C0BhbYKBieMSahATTNgT5iPUiH9VWcZd",
+	"E1QEqgSJ8sW5MBvUpZe1n2nNYBFrDq7wSK331voNjO3b9VDUAAyC/1sdfrxRxqxVIX4IQf/iiriT7Nn0",
+	"WHP6D77lc5GZdEaj82GnNBNaFuF7s5/FFx9mmLCH91QrDdWG0nz9DmfE4DV7zSUpNL6IskyN8Uuwnj2Z",
+	"Cm5XG/wd6Jeh5DsUgG64y8+4gWsQffM3F9i6cQinXHpuuT0y8VkrECTFXfDGkJPWq9rHYs3CokRT9lJc",
+	"gaR4tGusAaN2iKqSWwvanR9xWHxcoxE9Ixnf+fRD8lVhFxfKbp6cJKLY09KkfX7rpimL2p4jLEk9Xicu",
+	"PP9bMpkiwrqf3qQE9M3oi+CpnzdJ79m/HXnr+hpktljzplzC3RQuJZFpiFuGrQzujIsepV1aQRL/40nB",
+	"1NsUsalRT3R5QNzQg2hokrtuQ026NK+kfBtKiYvz1uYkxXWn2biX6sEHJxskf4rgzRKnLpdsw8sSKKKp",
+	"fE4QnhMJUQK/GGjXuwWQmC/aOK3RHsr47oS9XjNUG/gixHjIhG9W5TKy+HUlDQUnyVvKhLTKR8y/kq5N",
+	"CZEbWOkzhMGPU5w5ky3aunYwynF7QururV8TqUvKf3DGr+esRk7usYVsJGYh5Gv3yQ8TerUepYW9iMBz",
+	"rOSIPDM1bxBIhajPV/6QMO3wGGHqPE0lLejzG0gWkc09ubhcqDXqk/20xx0eB0DAmvMAlt+Ghz2aYuo8",
+	"QJLgDj8z6bzl5Rsc4xbUwpDEHgJy2GWc4p95q7wGprfM6Q1tYtCFimuZcNukmVBK6NcVJZKxx6oQ1BrJ",
+	"GEluVVmNMvtJOssyYoEoxqZKCPKc/ENLyvAvnbWMk8jB/a0BO3KPuVxBR8s1yw5lVh9t07QKQnBQRM8N",
+	"JfqO5jEZNv6DnAgJmM05hYvG/e6eQuh1cNkmtSeiTolyuVU0ipmOkdbDDk3OwWs2orw/mFtabuaZ5XiS",
+	"4evlxHTdyeK0A+K2PX3RMebxQOkNPGLzxLSH4DZPbqXdVyO4/hlWwiSdPqYnPXhZanXVlg+fZ/jJh/RD",
+	"B4RBD4aS61ysUk7Y+kRKzeTCNClCVoWDUgHyOh3uYJcHdnSu1uupDgJL91ZE+H4ZzW8ESfNFXIupkmfe",
+	"5tFxufiwVb/SebRXG57nIFMHkD6gQoKVkhkzFkq3Y6HM/RP2MWxUN4pt+BXgZsSdYQvZMM2JtyhBTSjJ",
+	"LirLispQ5gwTll0CenMxj/fDmw9vcUQNpBWpP9oKu3xTr8SQ6MgVBAmzlZYGu1EyuaEJ0xs5wEfjhK37",
+	"KMED0n6pv+g5qDrjJcle416lclRW/ulE4je2Gu39l3ha7SGULZEo55XO+2T3L89OT9k/3v3O1m0KKkox",
+	"NEtWmYrnWItlo7aSccM4+1/vKEUxKTWJqomgNDfwt+dPQeKHWcwB01G60CyezShGBs4WzOGRQJTOCkFw",
+	"8dWSBc9ec7ZzcgLdcZcziNqs/a4WxUzPLIK9VbohTuM8z2FtU2ddOuCFLtPfp6AM1RX6GA6ZoalgmzJG",
+	"XOTtehRLdgHGMjonPFeeJzNRk+dk63Z77+hm6pTUJsWZ2wENKfR9bFyideJcJX3anPJ/2AqM+2sLmQx/",
+	"202l/Z9rLdwfhttKp0sDhdIu+5yz2W//G1uvfYYcTlCNDmx02MS/oZMAYOvotg/31QrJRwjqOKGS4B2D",
+	"uaISUnT0CD/dMbdBah85caqmKjPuUoP8AK3+vgCUZJkIzfwBnPoMykk76XPssAkltqcLp1HQICg6D0Gc",
+	"Vjt3SdQpwYmwTp6nrC56jtKMoOuYX/+qeC7Wwqn42XaW8yj3YaA8/3ThpdbArhaUYVbNHbKzt5qKKoVD",
+	"YD5/eXQfUzP5HJPNKb5KC7vDDKLCkfslcA36ReXKiV3Qr19DV//18UMvu+a/Pn5g7ri+Oyl1wt6vVOnC",
+	"CM1pIYqFI9oiRmESAPXqYwPuXZPNzRyynizZdiPq3AVD5ljwRArPBmdM6UsuheHuoA1ix5Dd54PuzZAn",
+	"7E0DETk8eW6cjUgAuJMunxbM4BRC9kMV8pIppu+6DyuuPaUl2Y+UouJFgNDoU6VP/cEWmhmJHMJtQ5iN",
+	"taUrJiXkWpEEEjaH2nHzNueSnPQv3r5eLBeY/+Yo8Ozkh5NnSH5VguSlWJwt/nby7OQZnQGzGyLrKRoh",
+	"p8/XVNaqVC5LrCbP68zpOktGSK3PfW0sMPalynZ+S2S9aqKUUmc1n/7l97Az63i1TZ7rNsdbXQE9MKWS",
+	"xjHl82fPbm30uJ4GDd2RMRXx8rrKw27i9drVeKvdVPH2oTbPXXBj1XRe14xrhMUj0ymwQYkgy8WPtzg9",
+	"V5MiMbHX8orngoRjwW13G5Q7UiAsfzseLLWR6Q9ZKk14ITie/3T3cHyIz3NDFk5+16XB3CHpE4abzXdg",
+	"9e6pS0vbAM9AM4NRsY3aMlQBqPsxDS0k9wTZujj78/NyYaqi4HpHpbMcG4XtaginDu9Jg73OLw15Q1pv",
+	"F59xMLe8G0NheJW/ILPiddPybtb40PnaWYv9xxELy4BdMqO6/kWptt6Gus8F1RDAm29cruDoqwrF0tcS",
+	"ERwD5LZxY5z5ImlxGrDO2JTMVy+LjzRPM2Ve72vHtc4dMeL3oGOQQ54/e377CrbxnyWgqJdUCMU46yVa",
+	"WOQIC2pvREA9/ufbN+8/sNrG+OcTlypnHdOZ+9Vy96PanGxfxYzwoJQa6dpu/ZET9gtmf/jyGOFkA6k3",
+	"cLXn2AWslYZWTyh/rd4xfsmF36LWhVKwpAY6QF1y2XYjcjhj9mB1Okvs+GIvo3IH28zRP+0li19dX8ey",
+	"83d1iZHRJdNwpb6EzbHfFyFGfDUxvyLcJiZszytDtmGoqjY9uSCAzzUYaE2y7yQPcwvVaAwv6Hw2RemU",
+	"pvNE+Dc8Mm2HQr92jVe83HqDOKQ00QbJKrYWMkMseMe7iQrjuL1PmwT+vEqr4scdmyOtqiIH2yKE/VDg",
+	"ALJR5eoHZbxbM8RZvhc7Fjb6+5L/FOM4QhfDvP7KNTgmom/d7nOcfo9qpExAcj9GXcQ3EyxnoMVwlXFe",
+	"w6iDJUqdy44zD5cwuU62KBdmsWSvtuKw1PX1GT/UtR1vnwsT1SAfrAXo1r8OYN6jN6APyBH4+1dnpFhV",
+	"a8i2M5EMkpbybI6BBdWj5ArOPHcrCc4QcZoYTCge1FRxqrWgc/Dsb2DkrpIcMc0lJNj8N7C/hzYHMtks",
+	"l7IfLOFLHuM8DVYLuOK5K3pHXfgyeKaLkrZjuFOW5/N1S+78hlInqqln6qJ6YRSzxJB6HEQbxXcBY5j+",
+	"AxZ3uJB9LGoPTKI1xzJuecdERLQI6RYceQguEN+xDRjhweG3nn/w3GaAW7E+Gn6m539A7LqNr2D6s6tX",
+	"X/lh6yBxOkacutDCvxq+NaEbWP48R9F/GNxd2kpLXOTrtRNKR5COw8AIQwazDz9TZF0YVuPi6A5U8pa2",
+	"2OxDRWd21iP7dZT2U3y3HFlv7QDBHS28blbBHitweOYm9NVdmJ2t2GQPSYylN2H+qByYfiJRCG9lCujE",
+	"BKXauhIfPhRMR5m9gR9u8OJuwQavi5MOYRfwzyeprRal2xybcHEi0vUycQowwsTDWNqh2Jdf3h0+oXsZ",
+	"omLoMc+Mp5WNC/bZG7guAe8wNPfKCZaj2sztWnR70S7oCHnmb4+Jo2zuHLas08HIeaHkCo7ol/y7akKF",
+	"aOwGkj8AheGZKxnVjAXOkrCMjE8JjSM8feFPOQ9rkJfY4hiWMV7wcYBVbMLllCmd0bwkD19dg8If+xvR",
+	"ss0djW2knX5zd11ez7H1Xoqsb+WlcNE0OY0u4pxnljW3c5oaRfmO2CLTfHu8BdQAsuGNoKaiIeH2DALl",
+	"x7sH5R/yi0Q5YuKbS9vs8dHjh/GoFcYiAFNNeM0rEzwRZ5yVVUox0FmdP+BtE5G7S6+eG+5wZ547YpQ9",
+	"CHfeys/pSIL4VSclD82OrVbyssNBDtVduzR81XLbFcMOu65kjuoGjwvot1HDY8jpbp2Dg2V2PNMh0R21",
+	"mS2tl0MmGqV2xoi7o7XYw9RxjbTk8IOEoYRXX5qxi/Ojr/4+AO0VRxSkLVq35T7ckRLT/yiz/8ccPeag",
+	"PGb4HljD0Q/DmdAqLHMTFulL4tNvrSuwZxl/LU7azwbs3929jykYzbhlETqA78sIa18z3iaeQxnj/abz",
+	"V/UcRXk3hLivxTmqUh8glSno0C51dPPF6Z9NhB1C3c29CV9fKn69nNXWl5+4/nw0S+wQ+4vcYTUG+1QK",
+	"r1rBkH0JhHc0TFCHmhwFY1teHm6wuikNmar01tVrANpJ1nV4L3aDiKMep01Wgv+O7JG6uMaxLRGiyZ62",
+	"KSLM4fieDJAYgBYjEA5Dsc7OnjCqemgcJbsMEC+a02/+3vxRM+MV1d6oWWNP8RZd7L+PaUGzbxkVTQmQ",
+	"47qZCBKXVh6SHy+gC83R1R9B1VN8jlSMx42CnJiWDZ41wi044yI11BQ4hlANYx0gV3tXECWka6/NrTkC",
+	"YmTdQaAmoOe4YrU17CzJ2kXw0SVrH4DrVF5m3WxPSySsnNNv4a+5W7iGQfYTr3Vhjn0k7IcOFkbl7BEl",
+	"W5c6I/Kt13QfStX3oI1JuPh6FjOVQUOFoTVYSrcIH/kse2HqO6FSGTT07qmrYjZ/60ZVd46yBYjxcIgA",
+	"bt9AN5VS1r6ZLZFTFmWT0Sncbv8j5K9Psw+SnhocBbkqPwypKgdGsaMJfLavpuujE+tKa5W3EOd+Tyk3",
+	"msLd6DWHnSPnHdRjztJoWuVwCO4jP7N2iExgf9iL/O+H/MZPrP3aOaqKogX3+udDSJ7wHw+QvhZWp9/w",
+	"v3m2hGeJ/cwIV35jHxsCv3gYTt9bIEntFcZGS8y885ktfMt3Lk9cWBMKTyQX6ZguuW1yHHXBtb1TzqX6",
+	"vS69ll94bNFV+bRx+B6OlNDvBzvY+DJgJ+0uXU1gD2/+dMVG6orfjYFVj3OzfXKY6B0ptIDGI+u0eNh5",
+	"NkVA4rF3x83AN2cSb9C47GbPKTdglHoVnvpN3dhq9Gk0MfvcKzGTSy/lb0rjJOxj8x3VYKYM8Gk8fcP/",
+	"nhqwM82EGll7qqZwIeJexkKY2cMwGJqLJw/h9NpouDmXL6cUzB2Q54Eshu+T5i0D4jDpFu7/P41u7B8T",
+	"ciHA/GvU/HuJNY+RLp7POCdFiGKrDay+HOREeoU9UIWJolptaiJS3CTcIBHuTCp54772deDoTAFksyhM",
+	"1xgOH+igKymH8wc6KaN0++fU7autO8Pozu0ngwf4sL+W87F7WXevdvznO4oPd64uPXaUuHt17zg3BuqG",
+	"a1H8tfPHDRQHGFrxlJ+OeVDb8c8IL04t0qjp2VYLm5C5/pY93szXrUtNWdFqzVyl2rGlGEriDkrXcAfd",
+	"cQTqdGssDPqryC3oh5nqk05cGcxZMQlRGhOMBp/aH773YZQ7S5a9lxTZfTNTJrUe4duvJVe7dsTrHEJT",
+	"CUoMu53/LQnRyVC+BTIMZRInyFELsaf17Z+T4qy+SfR4hzY+RPeBHZYB10xzHp4Ha0/kea/HG8mdemp3",
+	"yPYN+u6B/duD75EpF18Cdw95cs3weyzICYHYdDprKZ5+C3/Oc750GWpPo6N9hd3+uXUB2IeUtF/DNL1B",
+	"HyNkJ79/DhlPw72Gw6Xz/A0/XAMreObMT7oas4Y63JVZX4pL12BGN4E3hYbcLblcZgykz0jipq8EaDR/",
+	"dzDV2gv7DHnZ9OUsYFfiKFNA9Uhw+FTZh2BH16bunYmx6Bbv+xBkveuoh1I7oyst70l2NXvHdkbazZj/",
+	"t+595FTgqr5PHD0Wo7um3sr4FpKT5sq0m8myG+UHJ0XXwSZZkB8zTLLluPV166g4mrWbtsgmj9jM5KTT",
+	"ZsM/k6leNB8cjb0aIEc4bZJrom5uFhG9j/nXPNC39KLpTByXnZ74BG+cfnPZFvOEjyvMdrvYmvbRhBtW",
+	"7oC97sUG65J3H6ddWpy+g0K5GheEKqeReF9ezF8RD4LS0x+EjNm9P3hHd4Duv2BnrNijnpqJOFwq2oar",
+	"7b0xttKBAad3F3NZ23Ffw9pWTTH2HhLvFJfNYKGXP9TVg1kHA7dK07KPqgi7G7ES8Sd6MVw+cuZN2w9q",
+	"PY5K+EJdhWXw77kWI2CU9s9ub1X+0Vc3SoawLi5R3CCPnw7pLdGpCmaEZF/E7D5N/mPXQLsX9nn98z4O",
+	"4TST/AZuCu1KbT5AdXPTJPDBA975EfX3dO9eiOweCrtFgoo9dren4bZqu8H7EURGP4Rhq1wZyJ7cMzPW",
+	"nPVSZDEfUUngv5SQLn08cJpYu1KuqBH2lkSnGnr5JJ3Kau6Obc/imryEX/ythRtgq40yIFmpcrHanbCX",
+	"IstAu4bxpeVNNoyQ1ANTOgPtrm1hwlLpvbUGCCUOcTHZTejEaT5uhVkLfztnJ0HK/CcFZ0geswsPBSEL",
+	"G4PMWjk5jxr8LdsQuSlgr+FWGw82jSo9i5zR5rRBCawrg45V+Er3XFnVDF3TiZit1SFSLH17CdHktjRB",
+	"z656S8SiCeLEa+q28nw+LaiA+tOVKuDTYijhx9F99mnDlyJzgx/rvOFMBYVYZn4pHN97ixLKI/L+JM+R",
+	"cn0I075Io1/9F0CLuMb+7dhur3Ch1sI9EqMkWlwvQdhYFUTGqACdqgzzpgT5PdaGcUUdUtk2qgTZb+gu",
+	"amrVqKaLuvgXGKgDQX+flk6VjKHQa5vvuMLOiCmJjUZSgaOu8KiYcHcj0nFkXpZaXfF8FL11+ZVTd+3e",
+	"4I7f3cZ4FwVY7r7KTsinrK8WPGad7TIMbpUf/77rtRxJaiM12EpVuTOlqWKN1gLoTraO3HDcFeUU87wl",
+	"fm9QRqbH37Qchl1aL9z775PDCdd+hhkpqxauj7V3Qiho21RLoiCDHhDT3UzYevbwdwiSFIl4cyYPZrDK",
+	"hRzmwZ/d++9cyvpZZu7uTVq1TIMqQd6f5A2Yf0ilsjy171TqhX5H7sCjFrfFc3dTs699IOJBle2rEXzs",
+	"DSDd7w25uBQXOXTAeCAM7lmLfDl4lRCXNTcH5xR1x3N/0PSr2+S5RdD3k4/wuYa/YDVsvb6j19+xbnfz",
+	"O2bcZkSX31yLOjrsp0Rxoz66hf5ADY5SBRB4ccjGD6cSFUvqpYlbP5OABvd7yutPUN1RXT+a8JGL+tVj",
+	"zqvo55qPsiThcbr+kXWI7GK/ZkJMsubFvHwgT5Q9a+kBL/YspIcc9SASeIi3p2NjY6SoU6ex0ZJxunid",
+	"UpeFDYVlk6tjTDLcNhGOyuip0jfWC6H7o246BXP+6jktoLgAPSnU//DN7pWCsxRDuJb1QNUQ8PI9rN3h",
+	"wDbuZ/xMcGb8BpyxX+5lwyuHscrd5Fu6b5imRMQspMTf1xqOE9GW0S+pKMy6tzbdK+tygBEGTnq+B3u/",
+	"lL0be8rP58hW1fDF0R8iybMRpT9lmt0/e94CH77Isk5+pDMtlPbxRIywU/jfbmD3SAfjw4VQhmTWjOrk",
+	"viryu1Dqe4/qvXXpb39oS5jmlt9k+d7wcq+a2fU1xp+/7/LpI+zhyTQSPUuXW8edWL/QYi+0O1xqex5T",
+	"3EGl7WNXVh8l0C3Qp7Etu33vd+HZz7ASGdwF+u+slD6CbO7h/GJn+JHziw0bZL4xU/IBFdjPGgwewoch",
+	"8KO0d8IxPpsdUUw4STImFj7WsmZ/C+eoBVoO3+V4TTynXrhrOplFmxbWiVvwh4/y3JHvLLa4HoKdN+A/",
+	"24YPZlFjqnbBNmA0RYkqkSpKF8uSe9l/+8i4MvC44JS+5FIYB6vLanHWDpMAmcvpjKvtshJ0IQyuehd8",
+	"XIXe7QaEbm2yN6Ksu4kMyRNGtlh8cGQDhYH8CgzZjUYVoCQ46ywefEkJTd6sxO+h4CJn8eW+tBv23W43",
+	"iu74DxNTmvJZqcdUgqcr1fLvyrFR6RtnEdwC2yaK3wyzbyPKb3IudG/ZfqODnbFGzuEWF3jidPHYQh9X",
+	"dLePiyPzYsopux+Sp3Rap9fZ7HjKLwzI1Uilx1jvvfCND6RHL1Xcn0tEEXgBxjINlDJL+fgrLjORUTGI",
+	"+RUikZ67B1EgMqDsyCLSDztsjH8MqrNfiNP7HY9uhwdWPLYbKXIfHb0UpddSt1CBcsitukIzAuXD6gtb",
+	"oe2LVs5ayCxeZaHUYU98DGxOWiJEyCthuZvakBR5jW2Oqdpe10Axc8yT5A5WMsx4jlJ7RyWceW3S3Sd7",
+	"H6DQfyHTtLZFuWQN2Vku5BfkZuNiOUIzIYUVPG9mPV8j5Wr1RVWjhVb+IbHREbnpxWqlKmlZRQPD90pE",
+	"h7d448T9xPjakr9bsYLLHVs7AZWrSyHjXTKv7Aak9ZMco+GlJ2FaHPxO749IQL8sW3kPubq8jPOdvzty",
+	"/q6aTTBOg8EV6N12AxqWTMOVokN3lCbkNrPqC9yInNFd/9NuqbdR43s23OcXAX3buhr+sGM6EbZCcu4+",
+	"Jn+7AvywzT9vsPlyd86d8b6Cx03vjd+7JM3/3RfNd0qD3w2XjI2RYo7RYWkA/01XxP6OymLJnOh3J6tR",
+	"3jANaw1mE23O2lKnvx1s3Nv+i4CSdO0W07mYNNWuMWObts2zgY79KbkLsFsAGTmNQw9bOo+3TKXYmSWe",
+	"O11tWJ3y7E6bb7grMiM0vSFPXtSlixr3u6TrPUOXl+IqkNBEDsyoG3cx2PXn6/8zANk4qSXXAgEA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// CoolingSchedule gives the annealing temperature for iteration i of
+// n, starting from an initial temperature t0.
+type CoolingSchedule func(t0 float64, i int, n int) float64
+
+// GeometricCooling multiplies the temperature by a constant rate
+// (which should be a little less than one) at each iteration.
+func GeometricCooling(rate float64) CoolingSchedule {
+	return func(t0 float64, i int, n int) float64 {
+		return t0 * math.Pow(rate, float64(i))
+	}
+}
+
+// LinearCooling reduces the temperature linearly from its initial
+// value to zero over the course of the run.
+func LinearCooling(t0 float64, i int, n int) float64 {
+	return t0 * (1 - float64(i)/float64(n))
+}
+
+// AnnealingSolver is a simulated annealing solver. It starts from the
+// greedy solution and explores the space of feasible schedules by
+// randomly reassigning places in shifts to different workers and
+// swapping workers between shifts, accepting worse schedules with a
+// probability that falls as the temperature is reduced.
+type AnnealingSolver struct {
+	// InitialTemperature is the starting temperature, in units of the
+	// penalty score.
+	InitialTemperature float64
+
+	// Cooling is the cooling schedule (defaults to geometric cooling
+	// with a rate of 0.999).
+	Cooling CoolingSchedule
+
+	// Iterations is the number of moves to try.
+	Iterations int
+
+	// Seed is the random number generator seed: runs with the same
+	// seed and problem give the same result.
+	Seed int64
+}
+
+// A place in a shift to be filled by the solver. Places for existing
+// assignments aren't included, since those can't be changed.
+type slot struct {
+	shift  *model.Shift
+	worker model.WorkerID
+}
+
+// Worker ID for an empty slot.
+const noWorker model.WorkerID = 0
+
+func (a AnnealingSolver) Solve(problem *Problem) (*Solution, error) {
+	cooling := a.Cooling
+	if cooling == nil {
+		cooling = GeometricCooling(0.999)
+	}
+	rng := rand.New(rand.NewSource(a.Seed))
+
+	// Start from the greedy solution.
+	initial, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		return nil, err
+	}
+	sched := newSchedule(problem)
+	slots := initialSlots(sched, initial.Assignments)
+	if len(slots) == 0 || len(problem.Workers) == 0 {
+		return initial, nil
+	}
+
+	current := sched.penalty()
+	bestScore := current
+	best := make([]slot, len(slots))
+	copy(best, slots)
+	history := make([]float64, a.Iterations)
+
+	for i := 0; i < a.Iterations; i++ {
+		t := cooling(a.InitialTemperature, i, a.Iterations)
+		undo := randomMove(rng, sched, slots)
+		if undo != nil {
+			score := sched.penalty()
+			delta := score - current
+			if delta <= 0 || t > 0 && rng.Float64() < math.Exp(-delta/t) {
+				current = score
+				if current < bestScore {
+					bestScore = current
+					copy(best, slots)
+				}
+			} else {
+				undo()
+			}
+		}
+		history[i] = bestScore
+	}
+
+	assignments := []model.ShiftAssignment{}
+	for _, sl := range best {
+		if sl.worker != noWorker {
+			assignments = append(assignments, model.ShiftAssignment{Worker: sl.worker, Shift: sl.shift.ID})
+		}
+	}
+	return &Solution{
+		Assignments: assignments,
+		Score:       bestScore,
+		History:     history,
+	}, nil
+}
+
+// Set up slots for all the free places in a problem's shifts, filling
+// them from an initial set of assignments, which are also added to
+// the schedule.
+func initialSlots(sched *schedule, assignments []model.ShiftAssignment) []slot {
+	slots := []slot{}
+	for _, a := range assignments {
+		shift := sched.shifts[a.Shift]
+		sched.add(a.Worker, shift)
+		slots = append(slots, slot{shift: shift, worker: a.Worker})
+	}
+	for _, s := range sched.problem.Shifts {
+		for n := sched.counts[s.ID]; n < s.Capacity; n++ {
+			slots = append(slots, slot{shift: s, worker: noWorker})
+		}
+	}
+	return slots
+}
+
+// Make a random change to the slots and schedule, returning a
+// function to undo it, or nil if the change would be infeasible.
+func randomMove(rng *rand.Rand, sched *schedule, slots []slot) func() {
+	if rng.Intn(2) == 0 {
+		return reassignMove(rng, sched, slots)
+	}
+	return swapMove(rng, sched, slots)
+}
+
+// Put a random worker (or nobody) into a random slot.
+func reassignMove(rng *rand.Rand, sched *schedule, slots []slot) func() {
+	i := rng.Intn(len(slots))
+	workers := sched.problem.Workers
+	worker := noWorker
+	if n := rng.Intn(len(workers) + 1); n < len(workers) {
+		worker = workers[n].ID
+	}
+	old := slots[i].worker
+	if !setSlot(sched, slots, i, worker) {
+		return nil
+	}
+	return func() { setSlot(sched, slots, i, old) }
+}
+
+// Swap the workers in two random slots.
+func swapMove(rng *rand.Rand, sched *schedule, slots []slot) func() {
+	i, j := rng.Intn(len(slots)), rng.Intn(len(slots))
+	wi, wj := slots[i].worker, slots[j].worker
+	if slots[i].shift.ID == slots[j].shift.ID || wi == wj {
+		return nil
+	}
+	if !setSlot(sched, slots, i, noWorker) {
+		return nil
+	}
+	if !setSlot(sched, slots, j, wi) {
+		setSlot(sched, slots, i, wi)
+		return nil
+	}
+	if !setSlot(sched, slots, i, wj) {
+		setSlot(sched, slots, j, wj)
+		setSlot(sched, slots, i, wi)
+		return nil
+	}
+	return func() {
+		setSlot(sched, slots, i, noWorker)
+		setSlot(sched, slots, j, wj)
+		setSlot(sched, slots, i, wi)
+	}
+}
+
+// Change the worker in a slot, keeping the schedule up to date. If
+// the new worker can't take the shift, nothing is changed and the
+// result is false.
+func setSlot(sched *schedule, slots []slot, i int, worker model.WorkerID) bool {
+	sl := &slots[i]
+	if sl.worker == worker {
+		return true
+	}
+	if sl.worker != noWorker {
+		sched.remove(sl.worker, sl.shift)
+	}
+	if worker != noWorker && !sched.canAdd(worker, sl.shift) {
+		if sl.worker != noWorker {
+			sched.add(sl.worker, sl.shift)
+		}
+		return false
+	}
+	if worker != noWorker {
+		sched.add(worker, sl.shift)
+	}
+	sl.worker = worker
+	return true
+}
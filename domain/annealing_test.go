@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestAnnealingSolver(t *testing.T) {
+	problem := testProblem(5, 7, 1)
+	// Worker 1 would rather not work nights.
+	problem.Preferences = map[model.WorkerID]map[model.ShiftID]int{1: {}}
+	for _, s := range problem.Shifts {
+		if IsNightShift(s) {
+			problem.Preferences[1][s.ID] = -2
+		}
+	}
+
+	greedy, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solver := AnnealingSolver{InitialTemperature: 10, Iterations: 2000, Seed: 1234}
+	solution, err := solver.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	if solution.Score > greedy.Score {
+		t.Errorf("annealing score %v worse than greedy score %v", solution.Score, greedy.Score)
+	}
+
+	// Best score history is non-increasing and ends at the final score.
+	if len(solution.History) != solver.Iterations {
+		t.Fatalf("expected %d history entries, got %d", solver.Iterations, len(solution.History))
+	}
+	for i := 1; i < len(solution.History); i++ {
+		if solution.History[i] > solution.History[i-1] {
+			t.Fatalf("best score increased at iteration %d", i)
+		}
+	}
+	if solution.History[len(solution.History)-1] != solution.Score {
+		t.Errorf("final history entry doesn't match score")
+	}
+
+	// Same seed gives the same result.
+	again, _ := solver.Solve(problem)
+	if again.Score != solution.Score || len(again.Assignments) != len(solution.Assignments) {
+		t.Fatalf("results differ for same seed")
+	}
+	for i := range again.Assignments {
+		if again.Assignments[i] != solution.Assignments[i] {
+			t.Fatalf("results differ for same seed")
+		}
+	}
+}
+
+func TestCoolingSchedules(t *testing.T) {
+	if LinearCooling(100, 50, 100) != 50 {
+		t.Errorf("bad linear cooling temperature")
+	}
+	geometric := GeometricCooling(0.5)
+	if geometric(100, 2, 100) != 25 {
+		t.Errorf("bad geometric cooling temperature")
+	}
+}
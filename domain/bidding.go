@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// BidPolicy decides the order in which workers bidding for a shift
+// are offered places on it.
+type BidPolicy string
+
+const (
+	// BidFirstCome ranks bids by the time they were made.
+	BidFirstCome BidPolicy = "first-come"
+
+	// BidSeniority ranks bids by how long the bidders have been
+	// registered, which is the order of their worker IDs.
+	BidSeniority BidPolicy = "seniority"
+
+	// BidFewestHours ranks bids by the hours the bidders are already
+	// assigned to in the shift's week, fewest first.
+	BidFewestHours BidPolicy = "fewest-hours"
+
+	// BidPreference ranks bids by the bidders' preferences for the
+	// shift, strongest first.
+	BidPreference BidPolicy = "preference"
+)
+
+var ErrUnknownBidPolicy = errors.New("unknown bid policy")
+
+// RankBids orders bids for a shift under a policy, best first, using
+// the problem's assignments and preferences. Bids that the policy
+// can't separate are ranked first come, first served.
+func RankBids(problem *Problem, shift *model.Shift, bids []*model.Bid, policy BidPolicy) ([]*model.Bid, error) {
+	sched := newSchedule(problem)
+
+	var key func(b *model.Bid) float64
+	switch policy {
+	case BidFirstCome:
+		key = func(b *model.Bid) float64 { return 0 }
+	case BidSeniority:
+		key = func(b *model.Bid) float64 { return float64(b.Worker) }
+	case BidFewestHours:
+		key = func(b *model.Bid) float64 { return sched.weekHours(b.Worker, shift) }
+	case BidPreference:
+		key = func(b *model.Bid) float64 { return -float64(problem.Preferences[b.Worker][shift.ID]) }
+	default:
+		return nil, ErrUnknownBidPolicy
+	}
+
+	keys := make(map[model.BidID]float64, len(bids))
+	for _, b := range bids {
+		keys[b.ID] = key(b)
+	}
+	ranked := append([]*model.Bid{}, bids...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		bi, bj := ranked[i], ranked[j]
+		if keys[bi.ID] != keys[bj.ID] {
+			return keys[bi.ID] < keys[bj.ID]
+		}
+		if !bi.CreatedAt.Equal(bj.CreatedAt) {
+			return bi.CreatedAt.Before(bj.CreatedAt)
+		}
+		return bi.ID < bj.ID
+	})
+	return ranked, nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestRankBids(t *testing.T) {
+	// Three bids for shift 5, made by workers 3, 1 and 2 in that order.
+	// Worker 3 has another shift that week, and worker 2 strongly
+	// prefers the shift.
+	problem := testProblem(3, 2, 1)
+	problem.Assignments = []model.ShiftAssignment{{Worker: 3, Shift: 1}}
+	problem.Preferences = map[model.WorkerID]map[model.ShiftID]int{2: {5: 2}, 3: {5: 1}}
+	shift := problem.Shifts[4]
+	now := time.Now()
+	bids := []*model.Bid{
+		{ID: 1, Worker: 3, Shift: shift.ID, CreatedAt: now},
+		{ID: 2, Worker: 1, Shift: shift.ID, CreatedAt: now.Add(time.Minute)},
+		{ID: 3, Worker: 2, Shift: shift.ID, CreatedAt: now.Add(2 * time.Minute)},
+	}
+
+	tests := []struct {
+		policy BidPolicy
+		expect []model.WorkerID
+	}{
+		{BidFirstCome, []model.WorkerID{3, 1, 2}},
+		{BidSeniority, []model.WorkerID{1, 2, 3}},
+		{BidFewestHours, []model.WorkerID{1, 2, 3}},
+		{BidPreference, []model.WorkerID{2, 3, 1}},
+	}
+	for _, test := range tests {
+		ranked, err := RankBids(problem, shift, bids, test.policy)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.policy, err)
+		}
+		for i, b := range ranked {
+			if b.Worker != test.expect[i] {
+				t.Errorf("%s: expected workers %v, got bid %d from worker %d at %d",
+					test.policy, test.expect, b.ID, b.Worker, i)
+			}
+		}
+	}
+
+	if _, err := RankBids(problem, shift, bids, "lottery"); err != ErrUnknownBidPolicy {
+		t.Errorf("expected unknown policy error, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Feasibility is the result of a coverage feasibility check.
+// Capacity is the total number of places in all shifts, and
+// MaxCoverage is the maximum number of those places that can be
+// filled at the same time. Shortfalls lists the shifts left with
+// unfilled places in one maximum coverage assignment.
+type Feasibility struct {
+	Capacity    int
+	MaxCoverage int
+	Shortfalls  []Shortfall
+}
+
+// Shortfall is the number of unfilled places in a shift.
+type Shortfall struct {
+	Shift    model.ShiftID
+	Unfilled int
+}
+
+// CheckFeasibility determines how much of the problem's shift capacity
+// can be covered at all, by computing the maximum flow through a
+// network with edges:
+//
+//	source → worker → worker-day → shift → sink
+//
+// Each worker-day node has a single unit of capacity coming in,
+// enforcing the one shift per day rule, and shift to sink edges have
+// the capacity of the shift. There are no edges to shifts during a
+// worker's time off, belonging to teams they're not in, or breaking a
+// hard rule given the context shifts they're assigned to. The
+// problem's other existing assignments are ignored, since they could
+// be changed to give better coverage, and so are shifts' skill
+// requirements and the rules between shifts in the problem other than
+// the one shift per day rule, so MaxCoverage is an upper bound.
+func CheckFeasibility(problem *Problem) *Feasibility {
+	net := newFlowNetwork(2)
+	source, sink := 0, 1
+
+	// Shift nodes, with edges to the sink.
+	shiftNode := make(map[model.ShiftID]int, len(problem.Shifts))
+	shiftEdge := make(map[model.ShiftID]int, len(problem.Shifts))
+	capacity := 0
+	for _, s := range problem.Shifts {
+		n := net.addNode()
+		shiftNode[s.ID] = n
+		shiftEdge[s.ID] = net.addEdge(n, sink, s.Capacity)
+		capacity += s.Capacity
+	}
+
+	// Group shifts by day, in date order so that results are
+	// repeatable.
+	byDay := map[time.Time][]*model.Shift{}
+	for _, s := range problem.Shifts {
+		y, m, d := s.StartTime.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, s.StartTime.Location())
+		byDay[day] = append(byDay[day], s)
+	}
+	days := make([][]*model.Shift, 0, len(byDay))
+	for _, shifts := range byDay {
+		days = append(days, shifts)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i][0].StartTime.Before(days[j][0].StartTime)
+	})
+
+	// Worker and worker-day nodes.
+	fixed := problem.contextShifts()
+	for _, w := range problem.Workers {
+		wn := net.addNode()
+		net.addEdge(source, wn, len(days))
+		for _, shifts := range days {
+			dn := net.addNode()
+			net.addEdge(wn, dn, 1)
+			for _, s := range shifts {
+				if !w.InTeam(s.Team) || problem.Unavailable(w.ID, s) ||
+					problem.Rules.Check(fixed[w.ID], s) != nil {
+					continue
+				}
+				net.addEdge(dn, shiftNode[s.ID], 1)
+			}
+		}
+	}
+
+	result := &Feasibility{
+		Capacity:    capacity,
+		MaxCoverage: net.maxFlow(source, sink),
+		Shortfalls:  []Shortfall{},
+	}
+	for _, s := range problem.Shifts {
+		if f := net.flow(shiftEdge[s.ID]); f < s.Capacity {
+			result.Shortfalls = append(result.Shortfalls, Shortfall{Shift: s.ID, Unfilled: s.Capacity - f})
+		}
+	}
+	sort.Slice(result.Shortfalls, func(i, j int) bool {
+		return result.Shortfalls[i].Shift < result.Shortfalls[j].Shift
+	})
+	return result
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestCheckFeasibilityFull(t *testing.T) {
+	// Six workers can cover three shifts of capacity two every day.
+	f := CheckFeasibility(testProblem(6, 7, 2))
+	if f.Capacity != 42 || f.MaxCoverage != 42 || len(f.Shortfalls) != 0 {
+		t.Errorf("unexpected feasibility result: %+v", f)
+	}
+}
+
+func TestCheckFeasibilityShortStaffed(t *testing.T) {
+	// Two workers can only cover two of the three shifts each day.
+	problem := testProblem(2, 3, 1)
+	f := CheckFeasibility(problem)
+	if f.Capacity != 9 || f.MaxCoverage != 6 {
+		t.Errorf("unexpected feasibility result: %+v", f)
+	}
+	unfilled := 0
+	for _, s := range f.Shortfalls {
+		unfilled += s.Unfilled
+	}
+	if unfilled != 3 {
+		t.Errorf("expected 3 unfilled places, got %d", unfilled)
+	}
+}
+
+func TestCheckFeasibilityBigShift(t *testing.T) {
+	// One shift needing more workers than there are.
+	problem := testProblem(3, 1, 1)
+	problem.Shifts[1].Capacity = 5
+	f := CheckFeasibility(problem)
+	if f.MaxCoverage != 3 {
+		t.Errorf("expected coverage of 3, got %d", f.MaxCoverage)
+	}
+	found := false
+	for _, s := range f.Shortfalls {
+		if s.Shift == model.ShiftID(2) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected shortfall for shift 2: %+v", f.Shortfalls)
+	}
+}
+
+func TestCheckFeasibilityTimeOff(t *testing.T) {
+	// Three workers can cover one day, but not if one of them has the
+	// afternoon off.
+	problem := testProblem(3, 1, 1)
+	day := problem.Shifts[0].StartTime
+	problem.TimeOff = []*model.TimeOff{{
+		Worker:    1,
+		StartTime: day.Add(12 * time.Hour),
+		EndTime:   day.Add(24 * time.Hour),
+		Status:    model.TimeOffApproved,
+	}}
+	f := CheckFeasibility(problem)
+	if f.MaxCoverage != 3 {
+		t.Errorf("expected coverage of 3, got %d", f.MaxCoverage)
+	}
+	problem.TimeOff[0].StartTime = day
+	f = CheckFeasibility(problem)
+	if f.MaxCoverage != 2 {
+		t.Errorf("expected coverage of 2, got %d", f.MaxCoverage)
+	}
+}
+
+func TestCheckFeasibilityContext(t *testing.T) {
+	// Three workers can cover one day, but not if one of them worked
+	// on each of the five days before and can't work more than five
+	// days in a row.
+	problem := testProblem(3, 1, 1)
+	problem.Rules = append(RuleSet{MaxConsecutiveDaysRule{Days: 5}}, DefaultRules...)
+	day := problem.Shifts[0].StartTime
+	for d := 1; d <= 5; d++ {
+		st := day.AddDate(0, 0, -d).Add(8 * time.Hour)
+		shift := &model.Shift{ID: model.ShiftID(10 + d), StartTime: st, EndTime: st.Add(8 * time.Hour), Capacity: 1}
+		problem.Context = append(problem.Context, shift)
+	}
+	f := CheckFeasibility(problem)
+	if f.MaxCoverage != 3 {
+		t.Errorf("expected coverage of 3, got %d", f.MaxCoverage)
+	}
+	for _, s := range problem.Context {
+		problem.Assignments = append(problem.Assignments, model.ShiftAssignment{Worker: 1, Shift: s.ID})
+	}
+	f = CheckFeasibility(problem)
+	if f.MaxCoverage != 2 {
+		t.Errorf("expected coverage of 2, got %d", f.MaxCoverage)
+	}
+}
@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Genome is an assignment matrix for the genetic solver: Genome[i][j]
+// is true if the i'th worker in a problem is assigned to the j'th
+// shift. Existing assignments from the problem are always included.
+type Genome [][]bool
+
+// Crossover is a genetic solver crossover operator: it combines two
+// parent genomes to produce a child. The result may be infeasible:
+// it's repaired before evaluation.
+type Crossover func(rng *rand.Rand, a Genome, b Genome) Genome
+
+// Mutation is a genetic solver mutation operator: it makes random
+// changes to a genome in place. The result may be infeasible: it's
+// repaired before evaluation.
+type Mutation func(rng *rand.Rand, g Genome)
+
+// UniformCrossover takes each worker's assignments for each shift
+// from one or other parent at random.
+func UniformCrossover(rng *rand.Rand, a Genome, b Genome) Genome {
+	child := a.clone()
+	for i := range child {
+		for j := range child[i] {
+			if rng.Intn(2) == 0 {
+				child[i][j] = b[i][j]
+			}
+		}
+	}
+	return child
+}
+
+// OnePointCrossover takes the assignments for shifts before a random
+// cut point from the first parent and the rest from the second.
+// Shifts are ordered as in the problem.
+func OnePointCrossover(rng *rand.Rand, a Genome, b Genome) Genome {
+	child := a.clone()
+	if len(child) == 0 {
+		return child
+	}
+	cut := rng.Intn(len(child[0]) + 1)
+	for i := range child {
+		copy(child[i][cut:], b[i][cut:])
+	}
+	return child
+}
+
+// FlipMutation flips each entry of the assignment matrix with a given
+// probability.
+func FlipMutation(rate float64) Mutation {
+	return func(rng *rand.Rand, g Genome) {
+		for i := range g {
+			for j := range g[i] {
+				if rng.Float64() < rate {
+					g[i][j] = !g[i][j]
+				}
+			}
+		}
+	}
+}
+
+// MoveMutation moves each assignment to a different worker on the
+// same shift with a given probability.
+func MoveMutation(rate float64) Mutation {
+	return func(rng *rand.Rand, g Genome) {
+		for i := range g {
+			for j := range g[i] {
+				if g[i][j] && rng.Float64() < rate {
+					k := rng.Intn(len(g))
+					g[i][j], g[k][j] = g[k][j], g[i][j]
+				}
+			}
+		}
+	}
+}
+
+func (g Genome) clone() Genome {
+	c := make(Genome, len(g))
+	for i := range g {
+		c[i] = make([]bool, len(g[i]))
+		copy(c[i], g[i])
+	}
+	return c
+}
+
+// GeneticSolver is an evolutionary solver working on assignment
+// matrices. Each generation is produced from the previous one by
+// tournament selection, crossover and mutation, with the best genome
+// always carried over. Offspring are repaired to satisfy the problem's
+// rules and shift capacities, and their fitness is evaluated
+// concurrently.
+type GeneticSolver struct {
+	// PopulationSize is the number of genomes in each generation.
+	PopulationSize int
+
+	// Generations is the number of generations to run.
+	Generations int
+
+	// Seed is the random number generator seed: runs with the same
+	// seed and problem give the same result.
+	Seed int64
+
+	// Crossover is the crossover operator (defaults to
+	// OnePointCrossover).
+	Crossover Crossover
+
+	// Mutation is the mutation operator (defaults to MoveMutation with
+	// a rate of 0.05).
+	Mutation Mutation
+
+	// Parallelism is the number of goroutines used for fitness
+	// evaluation (defaults to the number of CPUs).
+	Parallelism int
+}
+
+// A genome together with its penalty score.
+type individual struct {
+	genome Genome
+	score  float64
+}
+
+func (gs GeneticSolver) Solve(problem *Problem) (*Solution, error) {
+	crossover := gs.Crossover
+	if crossover == nil {
+		crossover = OnePointCrossover
+	}
+	mutation := gs.Mutation
+	if mutation == nil {
+		mutation = MoveMutation(0.05)
+	}
+	size := gs.PopulationSize
+	if size < 2 {
+		size = 2
+	}
+	rng := rand.New(rand.NewSource(gs.Seed))
+
+	// The initial population is the greedy solution plus random
+	// genomes.
+	greedy, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		return nil, err
+	}
+	population := make([]individual, size)
+	population[0].genome = toGenome(problem, greedy.Assignments)
+	for i := 1; i < size; i++ {
+		population[i].genome = randomGenome(rng, problem)
+	}
+	gs.evaluate(problem, population)
+	best := bestIndividual(population)
+
+	history := make([]float64, gs.Generations)
+	for gen := 0; gen < gs.Generations; gen++ {
+		next := make([]individual, size)
+		next[0] = best
+		for i := 1; i < size; i++ {
+			a := tournament(rng, population)
+			b := tournament(rng, population)
+			child := crossover(rng, a.genome, b.genome)
+			mutation(rng, child)
+			repair(rng, problem, child)
+			next[i].genome = child
+		}
+		gs.evaluate(problem, next[1:])
+		population = next
+		best = bestIndividual(population)
+		history[gen] = best.score
+	}
+
+	return &Solution{
+		Assignments: fromGenome(problem, best.genome),
+		Score:       best.score,
+		History:     history,
+	}, nil
+}
+
+// Evaluate the penalty score for each individual in a population,
+// using a pool of goroutines.
+func (gs GeneticSolver) evaluate(problem *Problem, population []individual) {
+	workers := gs.Parallelism
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				population[i].score = Evaluate(problem, fromGenome(problem, population[i].genome))
+			}
+		}()
+	}
+	for i := range population {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// Pick the better of two random individuals.
+func tournament(rng *rand.Rand, population []individual) individual {
+	a := population[rng.Intn(len(population))]
+	b := population[rng.Intn(len(population))]
+	if b.score < a.score {
+		return b
+	}
+	return a
+}
+
+func bestIndividual(population []individual) individual {
+	best := population[0]
+	for _, ind := range population[1:] {
+		if ind.score < best.score {
+			best = ind
+		}
+	}
+	return best
+}
+
+// Convert a list of new assignments into a genome, including the
+// problem's existing assignments.
+func toGenome(problem *Problem, assignments []model.ShiftAssignment) Genome {
+	workerIdx := make(map[model.WorkerID]int, len(problem.Workers))
+	for i, w := range problem.Workers {
+		workerIdx[w.ID] = i
+	}
+	shiftIdx := make(map[model.ShiftID]int, len(problem.Shifts))
+	for j, s := range problem.Shifts {
+		shiftIdx[s.ID] = j
+	}
+
+	g := make(Genome, len(problem.Workers))
+	for i := range g {
+		g[i] = make([]bool, len(problem.Shifts))
+	}
+	for _, as := range [][]model.ShiftAssignment{problem.Assignments, assignments} {
+		for _, a := range as {
+			i, iok := workerIdx[a.Worker]
+			j, jok := shiftIdx[a.Shift]
+			if iok && jok {
+				g[i][j] = true
+			}
+		}
+	}
+	return g
+}
+
+// Extract the new assignments (i.e. not including the problem's
+// existing assignments) from a genome.
+func fromGenome(problem *Problem, g Genome) []model.ShiftAssignment {
+	existing := make(map[model.ShiftAssignment]bool, len(problem.Assignments))
+	for _, a := range problem.Assignments {
+		existing[a] = true
+	}
+
+	assignments := []model.ShiftAssignment{}
+	for i, w := range problem.Workers {
+		for j, s := range problem.Shifts {
+			a := model.ShiftAssignment{Worker: w.ID, Shift: s.ID}
+			if g[i][j] && !existing[a] {
+				assignments = append(assignments, a)
+			}
+		}
+	}
+	return assignments
+}
+
+// Generate a random feasible genome, with each shift filled about as
+// much as its capacity allows.
+func randomGenome(rng *rand.Rand, problem *Problem) Genome {
+	g := toGenome(problem, nil)
+	n := len(problem.Workers)
+	if n == 0 {
+		return g
+	}
+	for j, s := range problem.Shifts {
+		for k := 0; k < s.Capacity; k++ {
+			g[rng.Intn(n)][j] = true
+		}
+	}
+	repair(rng, problem, g)
+	return g
+}
+
+// Repair a genome in place so that it includes all the problem's
+// existing assignments and satisfies the problem's rules and shift
+// capacities. New assignments are considered in a random order and
+// dropped if they can't be added to the schedule: removing
+// assignments never breaks the rules, so this always gives a feasible
+// result.
+func repair(rng *rand.Rand, problem *Problem, g Genome) {
+	sched := newSchedule(problem)
+	existing := toGenome(problem, nil)
+
+	type cell struct{ i, j int }
+	cells := []cell{}
+	for i := range g {
+		for j := range g[i] {
+			if existing[i][j] {
+				g[i][j] = true
+			} else if g[i][j] {
+				cells = append(cells, cell{i, j})
+			}
+		}
+	}
+
+	// Shuffle, then stable sort by shift start time so that each
+	// worker's schedule is built in time order.
+	rng.Shuffle(len(cells), func(a, b int) { cells[a], cells[b] = cells[b], cells[a] })
+	sort.SliceStable(cells, func(a, b int) bool {
+		return problem.Shifts[cells[a].j].StartTime.Before(problem.Shifts[cells[b].j].StartTime)
+	})
+
+	for _, c := range cells {
+		worker := problem.Workers[c.i].ID
+		shift := problem.Shifts[c.j]
+		if sched.canAdd(worker, shift) {
+			sched.add(worker, shift)
+		} else {
+			g[c.i][c.j] = false
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"math/rand"
+	"testing"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestGeneticSolver(t *testing.T) {
+	problem := testProblem(5, 7, 1)
+	problem.Assignments = []model.ShiftAssignment{{Worker: 2, Shift: 1}}
+
+	greedy, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range []struct {
+		name      string
+		crossover Crossover
+		mutation  Mutation
+	}{
+		{"one-point/move", OnePointCrossover, MoveMutation(0.05)},
+		{"uniform/flip", UniformCrossover, FlipMutation(0.02)},
+	} {
+		t.Run(op.name, func(t *testing.T) {
+			solver := GeneticSolver{
+				PopulationSize: 20,
+				Generations:    30,
+				Seed:           42,
+				Crossover:      op.crossover,
+				Mutation:       op.mutation,
+				Parallelism:    4,
+			}
+			solution, err := solver.Solve(problem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSolution(t, problem, solution)
+			if solution.Score > greedy.Score {
+				t.Errorf("genetic score %v worse than greedy score %v", solution.Score, greedy.Score)
+			}
+			if len(solution.History) != solver.Generations {
+				t.Errorf("expected %d history entries, got %d", solver.Generations, len(solution.History))
+			}
+
+			again, _ := solver.Solve(problem)
+			if again.Score != solution.Score {
+				t.Errorf("results differ for same seed")
+			}
+		})
+	}
+}
+
+func TestRepair(t *testing.T) {
+	problem := testProblem(3, 2, 1)
+	problem.Assignments = []model.ShiftAssignment{{Worker: 1, Shift: 1}}
+
+	// Everyone on every shift: way over capacity and breaking the
+	// same-day rule.
+	g := toGenome(problem, nil)
+	for i := range g {
+		for j := range g[i] {
+			g[i][j] = true
+		}
+	}
+	repair(rand.New(rand.NewSource(1)), problem, g)
+
+	if !g[0][0] {
+		t.Errorf("existing assignment removed by repair")
+	}
+	checkSolution(t, problem, &Solution{
+		Assignments: fromGenome(problem, g),
+		Score:       Evaluate(problem, fromGenome(problem, g)),
+	})
+}
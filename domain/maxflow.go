@@ -0,0 +1,105 @@
+package domain
+
+// flowNetwork is a directed graph with integer edge capacities, used
+// for computing maximum flows with Dinic's algorithm.
+type flowNetwork struct {
+	edges [][]int
+	to    []int
+	cap   []int
+	level []int
+	next  []int
+}
+
+func newFlowNetwork(nodes int) *flowNetwork {
+	return &flowNetwork{edges: make([][]int, nodes)}
+}
+
+// Add a node to the network, returning its index.
+func (net *flowNetwork) addNode() int {
+	net.edges = append(net.edges, nil)
+	return len(net.edges) - 1
+}
+
+// Add an edge (plus its residual reverse edge), returning the edge
+// index, which can be passed to flow after the maximum flow has been
+// calculated.
+func (net *flowNetwork) addEdge(from int, to int, capacity int) int {
+	e := len(net.to)
+	net.edges[from] = append(net.edges[from], e)
+	net.to = append(net.to, to)
+	net.cap = append(net.cap, capacity)
+	net.edges[to] = append(net.edges[to], e+1)
+	net.to = append(net.to, from)
+	net.cap = append(net.cap, 0)
+	return e
+}
+
+// Flow along an edge, which is the residual capacity of its reverse
+// edge.
+func (net *flowNetwork) flow(e int) int {
+	return net.cap[e^1]
+}
+
+// Calculate the maximum flow from source to sink.
+func (net *flowNetwork) maxFlow(source int, sink int) int {
+	total := 0
+	for net.bfs(source, sink) {
+		net.next = make([]int, len(net.edges))
+		for {
+			f := net.dfs(source, sink, int(^uint(0)>>1))
+			if f == 0 {
+				break
+			}
+			total += f
+		}
+	}
+	return total
+}
+
+// Build the level graph, returning false if the sink can't be
+// reached.
+func (net *flowNetwork) bfs(source int, sink int) bool {
+	net.level = make([]int, len(net.edges))
+	for i := range net.level {
+		net.level[i] = -1
+	}
+	net.level[source] = 0
+	queue := []int{source}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, e := range net.edges[n] {
+			if net.cap[e] > 0 && net.level[net.to[e]] < 0 {
+				net.level[net.to[e]] = net.level[n] + 1
+				queue = append(queue, net.to[e])
+			}
+		}
+	}
+	return net.level[sink] >= 0
+}
+
+// Find an augmenting path in the level graph, returning the flow
+// pushed along it.
+func (net *flowNetwork) dfs(n int, sink int, limit int) int {
+	if n == sink {
+		return limit
+	}
+	for ; net.next[n] < len(net.edges[n]); net.next[n]++ {
+		e := net.edges[n][net.next[n]]
+		to := net.to[e]
+		if net.cap[e] <= 0 || net.level[to] != net.level[n]+1 {
+			continue
+		}
+		push := limit
+		if net.cap[e] < push {
+			push = net.cap[e]
+		}
+		f := net.dfs(to, sink, push)
+		if f > 0 {
+			net.cap[e] -= f
+			net.cap[e^1] += f
+			return f
+		}
+	}
+	return 0
+}
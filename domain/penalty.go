@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Weights are the multipliers for the terms of the schedule penalty
+// score that solvers try to minimise.
+type Weights struct {
+	// Unfilled is the penalty for each unfilled place in a shift.
+	Unfilled float64
+
+	// Preference is the penalty for each unit of preference weight
+	// that isn't satisfied: a worker assigned to a shift they want to
+	// avoid, or not assigned to a shift they would like.
+	Preference float64
+
+	// UnevenHours is the penalty for each hour of standard deviation
+	// in the total hours assigned to workers.
+	UnevenHours float64
+
+	// NightShifts is the penalty for each pair of night shifts worked
+	// on consecutive days by the same worker.
+	NightShifts float64
+
+	// SoftRules is the penalty for each of a worker's shifts that breaks
+	// a soft rule, given the rest of their schedule.
+	SoftRules float64
+}
+
+// DefaultWeights makes filling shifts much more important than any
+// of the soft constraints.
+var DefaultWeights = Weights{
+	Unfilled:    100,
+	Preference:  1,
+	UnevenHours: 1,
+	NightShifts: 5,
+	SoftRules:   10,
+}
+
+// IsNightShift determines whether a shift is a night shift, i.e.
+// whether any part of it falls between midnight and 6 a.m.
+func IsNightShift(shift *model.Shift) bool {
+	y, m, d := shift.StartTime.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, shift.StartTime.Location())
+	for _, wStart := range []time.Time{day, day.AddDate(0, 0, 1)} {
+		wEnd := wStart.Add(6 * time.Hour)
+		if shift.StartTime.Before(wEnd) && shift.EndTime.After(wStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate calculates the penalty score for a set of new assignments
+// added to a problem's existing assignments.
+func Evaluate(problem *Problem, assignments []model.ShiftAssignment) float64 {
+	sched := newSchedule(problem)
+	for _, a := range assignments {
+		if shift, ok := sched.shifts[a.Shift]; ok {
+			sched.add(a.Worker, shift)
+		}
+	}
+	return sched.penalty()
+}
+
+// Calculate the weighted penalty score for the current state of a
+// schedule.
+func (sched *schedule) penalty() float64 {
+	w := sched.problem.Weights
+	total := w.Unfilled * float64(sched.unfilled())
+	if w.Preference != 0 {
+		total += w.Preference * sched.preferencePenalty()
+	}
+	if w.UnevenHours != 0 {
+		total += w.UnevenHours * sched.hoursDeviation()
+	}
+	if w.NightShifts != 0 {
+		total += w.NightShifts * float64(sched.backToBackNights())
+	}
+	if w.SoftRules != 0 {
+		total += w.SoftRules * float64(sched.softViolations())
+	}
+	return total
+}
+
+// Total preference weight violated: negative preferences for shifts a
+// worker is assigned to and positive preferences for shifts they
+// aren't assigned to.
+func (sched *schedule) preferencePenalty() float64 {
+	total := 0
+	for worker, prefs := range sched.problem.Preferences {
+		assigned := map[model.ShiftID]bool{}
+		for _, s := range sched.byWorker[worker] {
+			assigned[s.ID] = true
+		}
+		for shiftID, pref := range prefs {
+			if _, ok := sched.shifts[shiftID]; !ok {
+				continue
+			}
+			if assigned[shiftID] && pref < 0 {
+				total -= pref
+			}
+			if !assigned[shiftID] && pref > 0 {
+				total += pref
+			}
+		}
+	}
+	return float64(total)
+}
+
+// Standard deviation of total assigned hours across all workers.
+func (sched *schedule) hoursDeviation() float64 {
+	n := len(sched.problem.Workers)
+	if n == 0 {
+		return 0
+	}
+	hours := make([]float64, n)
+	mean := 0.0
+	for i, w := range sched.problem.Workers {
+		hours[i] = sched.hours(w.ID)
+		mean += hours[i]
+	}
+	mean /= float64(n)
+	variance := 0.0
+	for _, h := range hours {
+		variance += (h - mean) * (h - mean)
+	}
+	return math.Sqrt(variance / float64(n))
+}
+
+// Number of pairs of night shifts on consecutive days worked by the
+// same worker.
+func (sched *schedule) backToBackNights() int {
+	count := 0
+	for _, shifts := range sched.byWorker {
+		nights := []time.Time{}
+		for _, s := range shifts {
+			if IsNightShift(s) {
+				nights = append(nights, s.StartTime)
+			}
+		}
+		sort.Slice(nights, func(i, j int) bool { return nights[i].Before(nights[j]) })
+		for i := 1; i < len(nights); i++ {
+			y, m, d := nights[i-1].AddDate(0, 0, 1).Date()
+			ny, nm, nd := nights[i].Date()
+			if y == ny && m == nm && d == nd {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Number of soft rule violations: each of a worker's shifts is checked
+// against each soft rule, given the rest of the worker's shifts,
+// including their context shifts.
+func (sched *schedule) softViolations() int {
+	soft := sched.problem.Rules.Soft()
+	if len(soft) == 0 {
+		return 0
+	}
+	count := 0
+	others := []*model.Shift{}
+	for worker, shifts := range sched.byWorker {
+		for i, s := range shifts {
+			others = append(append(append(others[:0], sched.fixed[worker]...), shifts[:i]...), shifts[i+1:]...)
+			for _, r := range soft {
+				if r.Check(others, s) != nil {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// PreferenceMatrix converts workers' shift preferences into the form
+// used in a Problem, giving the weight of the most specific preference
+// that matches each shift for each worker.
+func PreferenceMatrix(prefs []*model.ShiftPreference, shifts []*model.Shift) map[model.WorkerID]map[model.ShiftID]int {
+	matrix := map[model.WorkerID]map[model.ShiftID]int{}
+	chosen := map[model.ShiftAssignment]*model.ShiftPreference{}
+	for _, p := range prefs {
+		for _, s := range shifts {
+			if !p.Matches(s) {
+				continue
+			}
+			key := model.ShiftAssignment{Worker: p.Worker, Shift: s.ID}
+			if c, ok := chosen[key]; ok && c.Specificity() >= p.Specificity() {
+				continue
+			}
+			chosen[key] = p
+			if matrix[p.Worker] == nil {
+				matrix[p.Worker] = map[model.ShiftID]int{}
+			}
+			matrix[p.Worker][s.ID] = int(p.Weight)
+		}
+	}
+	return matrix
+}
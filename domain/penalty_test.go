@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestPreferenceMatrix(t *testing.T) {
+	// Shifts 1-3 are on Monday 2023-05-01, 4-6 on Tuesday.
+	problem := testProblem(2, 2, 1)
+	monday := time.Monday
+	hour := 0
+	shift := model.ShiftID(1)
+	prefs := []*model.ShiftPreference{
+		{ID: 1, Worker: 1, Weekday: &monday, Weight: model.Prefer},
+		{ID: 2, Worker: 1, StartHour: &hour, Weight: model.StronglyAvoid},
+		{ID: 3, Worker: 2, Shift: &shift, Weight: model.StronglyPrefer},
+		{ID: 4, Worker: 2, Weekday: &monday, StartHour: &hour, Weight: model.Avoid},
+	}
+	m := PreferenceMatrix(prefs, problem.Shifts)
+
+	expected := map[model.WorkerID]map[model.ShiftID]int{
+		// Weekday and start hour preferences are equally specific, so
+		// the first matching one wins.
+		1: {1: 1, 2: 1, 3: 1, 4: -2},
+		// Single shift preferences beat recurring ones.
+		2: {1: 2},
+	}
+	for w, prefs := range expected {
+		if len(m[w]) != len(prefs) {
+			t.Errorf("worker %d: expected %v, got %v", w, prefs, m[w])
+		}
+		for s, weight := range prefs {
+			if m[w][s] != weight {
+				t.Errorf("worker %d, shift %d: expected %d, got %d", w, s, weight, m[w][s])
+			}
+		}
+	}
+}
+
+func TestIsNightShift(t *testing.T) {
+	problem := testProblem(1, 1, 1)
+	expected := []bool{true, false, false}
+	for i, s := range problem.Shifts {
+		if IsNightShift(s) != expected[i] {
+			t.Errorf("shift %d: expected night shift = %v", s.ID, expected[i])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Candidate is a possible replacement worker for a place on a shift,
+// along with the hours they're already assigned to in the week of the
+// shift.
+type Candidate struct {
+	Worker model.WorkerID
+	Hours  float64
+}
+
+// Replacement is the worker chosen to fill a vacated place on a shift.
+type Replacement struct {
+	Shift      *model.Shift
+	Candidates []Candidate
+	Worker     *model.WorkerID
+}
+
+// ReplacementCandidates ranks the workers who could take a place on a
+// shift given the problem's existing assignments: workers who can't be
+// added to the shift (because they're already working that day, have
+// time off, or would break another of the problem's rules) are left
+// out, and the rest are ordered by the hours they're working in the
+// shift's week, fewest first.
+func ReplacementCandidates(problem *Problem, shift *model.Shift) []Candidate {
+	return newSchedule(problem).candidates(shift)
+}
+
+// FindReplacements ranks candidates for a single place on each of a
+// list of vacated shifts, and chooses the best candidate for each
+// place where there is one. Shifts are filled in order of start time,
+// with each choice taken into account when ranking candidates for
+// later shifts, so the chosen replacements can all be applied
+// together. The results are in the same order as the shifts.
+func FindReplacements(problem *Problem, shifts []*model.Shift) []Replacement {
+	sched := newSchedule(problem)
+
+	result := make([]Replacement, len(shifts))
+	order := make([]int, len(shifts))
+	for i, s := range shifts {
+		result[i] = Replacement{Shift: s, Candidates: sched.candidates(s)}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return shifts[order[i]].StartTime.Before(shifts[order[j]].StartTime)
+	})
+
+	for _, i := range order {
+		shift := shifts[i]
+		candidates := sched.candidates(shift)
+		if len(candidates) == 0 {
+			continue
+		}
+		worker := candidates[0].Worker
+		sched.add(worker, shift)
+		result[i].Worker = &worker
+	}
+	return result
+}
+
+// Rank the workers who can be added to a shift by their hours in the
+// shift's week.
+func (sched *schedule) candidates(shift *model.Shift) []Candidate {
+	candidates := []Candidate{}
+	for _, w := range sched.problem.Workers {
+		if !sched.canAdd(w.ID, shift) {
+			continue
+		}
+		candidates = append(candidates, Candidate{Worker: w.ID, Hours: sched.weekHours(w.ID, shift)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Hours != candidates[j].Hours {
+			return candidates[i].Hours < candidates[j].Hours
+		}
+		return candidates[i].Worker < candidates[j].Worker
+	})
+	return candidates
+}
+
+// Total length of a worker's shifts in the week of a shift.
+func (sched *schedule) weekHours(worker model.WorkerID, shift *model.Shift) float64 {
+	weekStart := startOfWeek(shift.StartTime)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	hours := 0.0
+	for _, s := range sched.byWorker[worker] {
+		if s.StartTime.Before(weekEnd) && !s.StartTime.Before(weekStart) {
+			hours += s.EndTime.Sub(s.StartTime).Hours()
+		}
+	}
+	return hours
+}
+
+// Start of the week (Monday) containing a time, in the time's
+// location.
+func startOfWeek(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestReplacementCandidates(t *testing.T) {
+	// Worker 1 is already on shift 1, so can't take shift 2 on the same
+	// day. Worker 2 has time off, and worker 3 has more hours than
+	// worker 4.
+	problem := testProblem(4, 2, 1)
+	problem.Assignments = []model.ShiftAssignment{{Worker: 1, Shift: 1}, {Worker: 3, Shift: 4}}
+	problem.TimeOff = []*model.TimeOff{{
+		Worker:    2,
+		StartTime: problem.Shifts[0].StartTime,
+		EndTime:   problem.Shifts[0].StartTime.Add(24 * time.Hour),
+	}}
+
+	candidates := ReplacementCandidates(problem, problem.Shifts[1])
+	if len(candidates) != 2 || candidates[0].Worker != 4 || candidates[1].Worker != 3 {
+		t.Errorf("unexpected candidates: %+v", candidates)
+	}
+	if candidates[1].Hours != 8 {
+		t.Errorf("expected 8 hours for worker 3, got %v", candidates[1].Hours)
+	}
+}
+
+func TestFindReplacements(t *testing.T) {
+	// Two workers and two shifts on the same day: each can only take
+	// one of them.
+	problem := testProblem(2, 1, 1)
+	replacements := FindReplacements(problem, problem.Shifts[:2])
+	if len(replacements) != 2 {
+		t.Fatalf("expected 2 replacements, got %d", len(replacements))
+	}
+	r0, r1 := replacements[0].Worker, replacements[1].Worker
+	if r0 == nil || r1 == nil || *r0 == *r1 {
+		t.Errorf("expected different replacements for each shift: %+v", replacements)
+	}
+	if len(replacements[1].Candidates) != 2 {
+		t.Errorf("expected candidates ranked before any replacements are chosen")
+	}
+}
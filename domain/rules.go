@@ -1,18 +1,332 @@
 package domain
 
-import "skybluetrades.net/work-planning-demo/model"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
-// NewShiftAssignmentOK checks the business rule:
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Rule is a business rule for assigning workers to shifts. Rules are
+// checked by evaluating a candidate shift against a worker's existing
+// shifts. Hard rules must never be broken: the stores refuse
+// assignments that break them and solvers never propose them. Soft
+// rules can be broken by solvers, which add a penalty for each
+// violation, and by admins deliberately overriding them, but the
+// stores refuse ordinary assignments that break them. Window is how
+// far before or after a candidate shift a worker's other shifts can
+// affect the result of Check, assuming no shift is longer than a day.
+type Rule interface {
+	Name() string
+	Hard() bool
+	Window() time.Duration
+	Check(shifts []*model.Shift, shift *model.Shift) *Violation
+}
+
+// Violation explains why assigning a worker to a shift breaks a rule.
+type Violation struct {
+	Rule    string
+	Hard    bool
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("rule %s: %s", v.Rule, v.Message)
+}
+
+// RuleSet is a list of rules that every worker's schedule should
+// satisfy.
+type RuleSet []Rule
+
+// Check evaluates a candidate assignment against the hard rules in a
+// set, returning the first violation, or nil if there isn't one.
+func (rs RuleSet) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
+	for _, r := range rs {
+		if !r.Hard() {
+			continue
+		}
+		if v := r.Check(shifts, shift); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// Violations evaluates a candidate assignment against all the rules in
+// a set, returning every violation.
+func (rs RuleSet) Violations(shifts []*model.Shift, shift *model.Shift) []*Violation {
+	violations := []*Violation{}
+	for _, r := range rs {
+		if v := r.Check(shifts, shift); v != nil {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Window returns the longest window of the rules in a set: the margin
+// either side of a range of shifts that has to be taken into account
+// when scheduling them.
+func (rs RuleSet) Window() time.Duration {
+	var window time.Duration
+	for _, r := range rs {
+		if w := r.Window(); w > window {
+			window = w
+		}
+	}
+	return window
+}
+
+// Soft returns the soft rules in a set.
+func (rs RuleSet) Soft() RuleSet {
+	soft := RuleSet{}
+	for _, r := range rs {
+		if !r.Hard() {
+			soft = append(soft, r)
+		}
+	}
+	return soft
+}
+
+func violation(r Rule, format string, args ...interface{}) *Violation {
+	return &Violation{Rule: r.Name(), Hard: r.Hard(), Message: fmt.Sprintf(format, args...)}
+}
+
+// Names of built-in rules.
+const (
+	RuleSameDay              = "same-day"
+	RuleMinRest              = "min-rest"
+	RuleMaxWeeklyHours       = "max-weekly-hours"
+	RuleMaxConsecutiveDays   = "max-consecutive-days"
+	RuleMaxConsecutiveNights = "max-consecutive-nights"
+)
+
+// RuleFactory builds one of the built-in rules from its limit (the
+// meaning of which depends on the rule) and whether it's soft.
+type RuleFactory func(limit float64, soft bool) (Rule, error)
+
+var ErrUnknownRule = errors.New("unknown rule")
+var ErrBadRuleLimit = errors.New("rule limit must be positive")
+
+var builtinRules = map[string]RuleFactory{
+	RuleSameDay: func(limit float64, soft bool) (Rule, error) {
+		return SameDayRule{Soft: soft}, nil
+	},
+	RuleMinRest: func(limit float64, soft bool) (Rule, error) {
+		if limit <= 0 {
+			return nil, ErrBadRuleLimit
+		}
+		return MinRestRule{Hours: limit, Soft: soft}, nil
+	},
+	RuleMaxWeeklyHours: func(limit float64, soft bool) (Rule, error) {
+		if limit <= 0 {
+			return nil, ErrBadRuleLimit
+		}
+		return MaxWeeklyHoursRule{Hours: limit, Soft: soft}, nil
+	},
+	RuleMaxConsecutiveDays: func(limit float64, soft bool) (Rule, error) {
+		if limit < 1 {
+			return nil, ErrBadRuleLimit
+		}
+		return MaxConsecutiveDaysRule{Days: int(limit), Soft: soft}, nil
+	},
+	RuleMaxConsecutiveNights: func(limit float64, soft bool) (Rule, error) {
+		if limit < 1 {
+			return nil, ErrBadRuleLimit
+		}
+		return MaxConsecutiveNightsRule{Nights: int(limit), Soft: soft}, nil
+	},
+}
+
+// NewRule builds a built-in rule by name.
+func NewRule(name string, limit float64, soft bool) (Rule, error) {
+	factory, ok := builtinRules[name]
+	if !ok {
+		return nil, ErrUnknownRule
+	}
+	return factory(limit, soft)
+}
+
+// RuleNames lists the names of the built-in rules.
+func RuleNames() []string {
+	names := make([]string, 0, len(builtinRules))
+	for name := range builtinRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRuleSet builds a rule set from rule configuration.
+func NewRuleSet(configs []model.RuleConfig) (RuleSet, error) {
+	rs := RuleSet{}
+	for _, cfg := range configs {
+		r, err := NewRule(cfg.Name, cfg.Limit, cfg.Soft)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// DefaultRuleConfigs is the configuration for the standard rule set,
+// used by the stores and solvers when no rule set has been configured.
+// It's just the hard same-day rule, which is all there was before
+// rules could be configured: the other rules have to be turned on in
+// a rule set.
+var DefaultRuleConfigs = []model.RuleConfig{
+	{Name: RuleSameDay},
+}
+
+// DefaultRules is the standard rule set.
+var DefaultRules, _ = NewRuleSet(DefaultRuleConfigs)
+
+// SameDayRule checks the business rule:
 //
 //	A **worker** never has two **shifts** on the same day.
-func NewShiftAssignmentOK(shifts []*model.Shift, shift *model.Shift) bool {
+type SameDayRule struct {
+	Soft bool
+}
+
+func (r SameDayRule) Name() string          { return RuleSameDay }
+func (r SameDayRule) Hard() bool            { return !r.Soft }
+func (r SameDayRule) Window() time.Duration { return 24 * time.Hour }
+
+func (r SameDayRule) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
 	// Check date of new shift against date of existing shifts.
-	y, m, d := shift.StartTime.Date()
+	day := dayOf(shift.StartTime)
+	for _, s := range shifts {
+		if dayOf(s.StartTime).Equal(day) {
+			return violation(r, "worker already has shift %d on %s", s.ID, day.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// MinRestRule requires a minimum number of hours between the end of
+// one of a worker's shifts and the start of the next.
+type MinRestRule struct {
+	Hours float64
+	Soft  bool
+}
+
+func (r MinRestRule) Name() string { return RuleMinRest }
+func (r MinRestRule) Hard() bool   { return !r.Soft }
+func (r MinRestRule) Window() time.Duration {
+	return 24*time.Hour + time.Duration(r.Hours*float64(time.Hour))
+}
+
+func (r MinRestRule) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
+	for _, s := range shifts {
+		var rest time.Duration
+		if s.StartTime.Before(shift.StartTime) {
+			rest = shift.StartTime.Sub(s.EndTime)
+		} else {
+			rest = s.StartTime.Sub(shift.EndTime)
+		}
+		if rest.Hours() < r.Hours {
+			return violation(r, "only %.1f hours rest between shifts %d and %d (minimum %.1f)",
+				rest.Hours(), s.ID, shift.ID, r.Hours)
+		}
+	}
+	return nil
+}
+
+// MaxWeeklyHoursRule limits the total length of the shifts a worker
+// works in a week (Monday to Sunday, by shift start time).
+type MaxWeeklyHoursRule struct {
+	Hours float64
+	Soft  bool
+}
+
+func (r MaxWeeklyHoursRule) Name() string          { return RuleMaxWeeklyHours }
+func (r MaxWeeklyHoursRule) Hard() bool            { return !r.Soft }
+func (r MaxWeeklyHoursRule) Window() time.Duration { return 7 * 24 * time.Hour }
+
+func (r MaxWeeklyHoursRule) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
+	week := startOfWeek(shift.StartTime)
+	hours := shift.EndTime.Sub(shift.StartTime).Hours()
 	for _, s := range shifts {
-		cy, cm, cd := s.StartTime.Date()
-		if y == cy && m == cm && d == cd {
-			return false
+		if startOfWeek(s.StartTime).Equal(week) {
+			hours += s.EndTime.Sub(s.StartTime).Hours()
 		}
 	}
-	return true
+	if hours > r.Hours {
+		return violation(r, "%.1f hours in week starting %s (maximum %.1f)",
+			hours, week.Format("2006-01-02"), r.Hours)
+	}
+	return nil
+}
+
+// MaxConsecutiveDaysRule limits the number of consecutive days on
+// which a worker has shifts.
+type MaxConsecutiveDaysRule struct {
+	Days int
+	Soft bool
+}
+
+func (r MaxConsecutiveDaysRule) Name() string { return RuleMaxConsecutiveDays }
+func (r MaxConsecutiveDaysRule) Hard() bool   { return !r.Soft }
+func (r MaxConsecutiveDaysRule) Window() time.Duration {
+	return time.Duration(r.Days+1) * 24 * time.Hour
+}
+
+func (r MaxConsecutiveDaysRule) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
+	if n := consecutiveDays(shifts, shift, func(*model.Shift) bool { return true }); n > r.Days {
+		return violation(r, "%d consecutive working days (maximum %d)", n, r.Days)
+	}
+	return nil
+}
+
+// MaxConsecutiveNightsRule limits the number of night shifts a worker
+// works on consecutive days.
+type MaxConsecutiveNightsRule struct {
+	Nights int
+	Soft   bool
+}
+
+func (r MaxConsecutiveNightsRule) Name() string { return RuleMaxConsecutiveNights }
+func (r MaxConsecutiveNightsRule) Hard() bool   { return !r.Soft }
+func (r MaxConsecutiveNightsRule) Window() time.Duration {
+	return time.Duration(r.Nights+1) * 24 * time.Hour
+}
+
+func (r MaxConsecutiveNightsRule) Check(shifts []*model.Shift, shift *model.Shift) *Violation {
+	if !IsNightShift(shift) {
+		return nil
+	}
+	if n := consecutiveDays(shifts, shift, IsNightShift); n > r.Nights {
+		return violation(r, "%d consecutive night shifts (maximum %d)", n, r.Nights)
+	}
+	return nil
+}
+
+// Length of the run of consecutive days including a new shift's day
+// on which a worker has shifts matching a condition.
+func consecutiveDays(shifts []*model.Shift, shift *model.Shift, match func(*model.Shift) bool) int {
+	days := map[time.Time]bool{}
+	for _, s := range shifts {
+		if match(s) {
+			days[dayOf(s.StartTime)] = true
+		}
+	}
+	day := dayOf(shift.StartTime)
+	n := 1
+	for d := day.AddDate(0, 0, -1); days[d]; d = d.AddDate(0, 0, -1) {
+		n++
+	}
+	for d := day.AddDate(0, 0, 1); days[d]; d = d.AddDate(0, 0, 1) {
+		n++
+	}
+	return n
+}
+
+// The calendar date of a time (in its own location), as midnight UTC
+// so that dates can be compared and used as map keys.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Make a test shift starting at an hour offset from midnight on Monday
+// 2023-05-01.
+func testShift(id int, hour int, length int) *model.Shift {
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hour) * time.Hour)
+	return &model.Shift{
+		ID:        model.ShiftID(id),
+		StartTime: start,
+		EndTime:   start.Add(time.Duration(length) * time.Hour),
+		Capacity:  1,
+	}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	// Night shifts (midnight to 8 a.m.) on Monday to Thursday.
+	nights := []*model.Shift{testShift(1, 0, 8), testShift(2, 24, 8), testShift(3, 48, 8), testShift(4, 72, 8)}
+
+	tests := []struct {
+		name   string
+		rule   Rule
+		shifts []*model.Shift
+		shift  *model.Shift
+		ok     bool
+	}{
+		{"same day", SameDayRule{}, nights[:1], testShift(5, 16, 8), false},
+		{"different day", SameDayRule{}, nights[:1], testShift(5, 32, 8), true},
+		{"short rest", MinRestRule{Hours: 11}, nights[:1], testShift(5, 16, 8), false},
+		{"enough rest", MinRestRule{Hours: 8}, nights[:1], testShift(5, 16, 8), true},
+		{"short rest before", MinRestRule{Hours: 8}, nights[1:2], testShift(5, 16, 4), false},
+		{"weekly hours", MaxWeeklyHoursRule{Hours: 36}, nights, testShift(5, 96, 8), false},
+		{"weekly hours next week", MaxWeeklyHoursRule{Hours: 36}, nights, testShift(5, 168, 8), true},
+		{"consecutive days", MaxConsecutiveDaysRule{Days: 4}, nights, testShift(5, 104, 8), false},
+		{"gap in days", MaxConsecutiveDaysRule{Days: 4}, nights, testShift(5, 128, 8), true},
+		{"consecutive nights", MaxConsecutiveNightsRule{Nights: 4}, nights, testShift(5, 96, 8), false},
+		{"day after nights", MaxConsecutiveNightsRule{Nights: 4}, nights, testShift(5, 104, 8), true},
+	}
+	for _, test := range tests {
+		v := test.rule.Check(test.shifts, test.shift)
+		if (v == nil) != test.ok {
+			t.Errorf("%s: unexpected result %v", test.name, v)
+		}
+		if v != nil && v.Rule != test.rule.Name() {
+			t.Errorf("%s: violation for wrong rule %s", test.name, v.Rule)
+		}
+	}
+}
+
+func TestRuleSetCheck(t *testing.T) {
+	rules := RuleSet{MinRestRule{Hours: 11, Soft: true}, SameDayRule{}}
+	shifts := []*model.Shift{testShift(1, 0, 8)}
+
+	// Soft rules don't block assignments.
+	if v := rules.Check(shifts, testShift(2, 24, 8)); v != nil {
+		t.Errorf("unexpected violation %v", v)
+	}
+	v := rules.Check(shifts, testShift(2, 16, 8))
+	if v == nil || v.Rule != RuleSameDay || !v.Hard {
+		t.Errorf("expected same-day violation, got %v", v)
+	}
+
+	// The set's window is its longest rule window.
+	if w := rules.Window(); w != 35*time.Hour {
+		t.Errorf("expected 35 hour window, got %v", w)
+	}
+
+	if _, err := NewRule("no-such-rule", 1, false); err != ErrUnknownRule {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+	if _, err := NewRule(RuleMinRest, 0, false); err != ErrBadRuleLimit {
+		t.Errorf("expected bad limit error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Unstaffed counts the places a shift reserves for skilled workers
+// that a group of workers assigned to it can't fill, with each worker
+// filling at most one place. Workers with more than one skill make
+// this a matching problem, so it's solved as a maximum flow through a
+// network with edges:
+//
+//	source → worker → requirement → sink
+//
+// where worker to requirement edges join workers to the requirements
+// for skills they have, and requirement to sink edges have the
+// requirement's count as their capacity.
+func Unstaffed(shift *model.Shift, workers []*model.Worker) int {
+	required := 0
+	for _, r := range shift.Requirements {
+		required += r.Count
+	}
+	if required == 0 {
+		return 0
+	}
+
+	net := newFlowNetwork(2)
+	source, sink := 0, 1
+	reqNodes := make([]int, len(shift.Requirements))
+	for i, r := range shift.Requirements {
+		reqNodes[i] = net.addNode()
+		net.addEdge(reqNodes[i], sink, r.Count)
+	}
+	for _, w := range workers {
+		wn := net.addNode()
+		net.addEdge(source, wn, 1)
+		for i, r := range shift.Requirements {
+			if w.HasSkill(r.Skill) {
+				net.addEdge(wn, reqNodes[i], 1)
+			}
+		}
+	}
+
+	return required - net.maxFlow(source, sink)
+}
+
+// Staffable checks whether a shift's skill requirements can still be
+// met with a group of workers assigned to it, using the places left.
+func Staffable(shift *model.Shift, workers []*model.Worker) bool {
+	return Unstaffed(shift, workers) <= shift.Capacity-len(workers)
+}
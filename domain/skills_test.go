@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestStaffable(t *testing.T) {
+	// Four places, two for nurses and one for a supervisor.
+	shift := &model.Shift{ID: 1, Capacity: 4, Requirements: []model.Requirement{
+		{Skill: "nurse", Count: 2},
+		{Skill: "supervisor", Count: 1},
+	}}
+	general := &model.Worker{ID: 1}
+	nurse := &model.Worker{ID: 2, Skills: []string{"nurse"}}
+	both := &model.Worker{ID: 3, Skills: []string{"nurse", "supervisor"}}
+
+	tests := []struct {
+		workers   []*model.Worker
+		unstaffed int
+		staffable bool
+	}{
+		{[]*model.Worker{}, 3, true},
+		{[]*model.Worker{general}, 3, true},
+		{[]*model.Worker{general, general}, 3, false},
+		{[]*model.Worker{nurse, both}, 1, true},
+		{[]*model.Worker{nurse, both, general}, 1, true},
+		{[]*model.Worker{nurse, general, general}, 2, false},
+		// The worker with both skills must be the supervisor.
+		{[]*model.Worker{both, nurse, nurse}, 0, true},
+	}
+	for i, test := range tests {
+		if n := Unstaffed(shift, test.workers); n != test.unstaffed {
+			t.Errorf("%d: expected %d unstaffed places, got %d", i, test.unstaffed, n)
+		}
+		if ok := Staffable(shift, test.workers); ok != test.staffable {
+			t.Errorf("%d: expected staffable = %v", i, test.staffable)
+		}
+	}
+}
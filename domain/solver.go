@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"sort"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Problem is the input to a schedule solver: the workers available,
+// the shifts to fill, any assignments that already exist (which
+// solvers must keep), the rules for every worker's schedule (hard
+// rules must be satisfied, soft rules are penalised), and approved
+// time off, during which workers can't be assigned to shifts at all.
+// Places that shifts reserve for skilled workers can only go to
+// workers with the skills, and shifts belonging to a team can only go
+// to the team's members. Weights and Preferences determine the
+// penalty score that solvers try to minimise. Preferences may be nil:
+// otherwise it holds preference weights for workers and shifts,
+// positive for shifts a worker would like to work and negative for
+// shifts they would rather avoid. Context holds shifts either side of
+// the ones being scheduled: solvers don't fill them, but existing
+// assignments to them count when checking workers' rules.
+type Problem struct {
+	Workers     []*model.Worker
+	Shifts      []*model.Shift
+	Context     []*model.Shift
+	Assignments []model.ShiftAssignment
+	Rules       RuleSet
+	TimeOff     []*model.TimeOff
+	Weights     Weights
+	Preferences map[model.WorkerID]map[model.ShiftID]int
+}
+
+// Unavailable checks whether a worker has time off overlapping a
+// shift.
+func (problem *Problem) Unavailable(worker model.WorkerID, shift *model.Shift) bool {
+	for _, t := range problem.TimeOff {
+		if t.Worker == worker && t.Overlaps(shift) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect the context shifts each worker is assigned to.
+func (problem *Problem) contextShifts() map[model.WorkerID][]*model.Shift {
+	context := make(map[model.ShiftID]*model.Shift, len(problem.Context))
+	for _, s := range problem.Context {
+		context[s.ID] = s
+	}
+	fixed := map[model.WorkerID][]*model.Shift{}
+	for _, a := range problem.Assignments {
+		if shift, ok := context[a.Shift]; ok {
+			fixed[a.Worker] = append(fixed[a.Worker], shift)
+		}
+	}
+	return fixed
+}
+
+// Solution is the output of a schedule solver. Assignments holds only
+// the new assignments proposed by the solver (not the existing
+// assignments from the problem). Score is the weighted penalty for
+// the whole schedule: lower is better. Iterative solvers also record
+// the best score after each iteration in History.
+type Solution struct {
+	Assignments []model.ShiftAssignment
+	Score       float64
+	History     []float64
+}
+
+// Solver is the interface implemented by schedule generation
+// algorithms.
+type Solver interface {
+	Solve(problem *Problem) (*Solution, error)
+}
+
+// schedule tracks the shifts assigned to each worker and the workers
+// assigned to each shift while a solver is working, along with the
+// context shifts each worker is assigned to, which never change.
+type schedule struct {
+	problem  *Problem
+	shifts   map[model.ShiftID]*model.Shift
+	workers  map[model.WorkerID]*model.Worker
+	byWorker map[model.WorkerID][]*model.Shift
+	fixed    map[model.WorkerID][]*model.Shift
+	byShift  map[model.ShiftID][]*model.Worker
+	counts   map[model.ShiftID]int
+}
+
+// Set up a schedule containing the existing assignments from a
+// problem. Assignments to shifts that aren't part of the problem or
+// its context are ignored.
+func newSchedule(problem *Problem) *schedule {
+	sched := &schedule{
+		problem:  problem,
+		shifts:   make(map[model.ShiftID]*model.Shift, len(problem.Shifts)),
+		workers:  make(map[model.WorkerID]*model.Worker, len(problem.Workers)),
+		byWorker: make(map[model.WorkerID][]*model.Shift, len(problem.Workers)),
+		fixed:    problem.contextShifts(),
+		byShift:  make(map[model.ShiftID][]*model.Worker, len(problem.Shifts)),
+		counts:   make(map[model.ShiftID]int, len(problem.Shifts)),
+	}
+	for _, s := range problem.Shifts {
+		sched.shifts[s.ID] = s
+	}
+	for _, w := range problem.Workers {
+		sched.workers[w.ID] = w
+	}
+	for _, a := range problem.Assignments {
+		if shift, ok := sched.shifts[a.Shift]; ok {
+			sched.add(a.Worker, shift)
+		}
+	}
+	return sched
+}
+
+// Check whether a worker can be assigned to a shift: the shift must
+// have space, which mustn't be needed for skilled workers the worker
+// can't stand in for, the worker must be in the shift's team, must
+// not already be on it or have time off during it, and the problem's
+// hard rules must all be satisfied, taking the worker's context shifts
+// into account.
+func (sched *schedule) canAdd(worker model.WorkerID, shift *model.Shift) bool {
+	if sched.counts[shift.ID] >= shift.Capacity {
+		return false
+	}
+	existing := sched.byWorker[worker]
+	for _, s := range existing {
+		if s.ID == shift.ID {
+			return false
+		}
+	}
+	if !sched.worker(worker).InTeam(shift.Team) {
+		return false
+	}
+	if len(shift.Requirements) > 0 {
+		assigned := sched.byShift[shift.ID]
+		if !Staffable(shift, append(assigned[:len(assigned):len(assigned)], sched.worker(worker))) {
+			return false
+		}
+	}
+	if sched.problem.Unavailable(worker, shift) {
+		return false
+	}
+	return sched.problem.Rules.Check(sched.withContext(worker, existing), shift) == nil
+}
+
+// Add a worker's context shifts to a list of their shifts.
+func (sched *schedule) withContext(worker model.WorkerID, shifts []*model.Shift) []*model.Shift {
+	fixed := sched.fixed[worker]
+	if len(fixed) == 0 {
+		return shifts
+	}
+	return append(fixed[:len(fixed):len(fixed)], shifts...)
+}
+
+func (sched *schedule) add(worker model.WorkerID, shift *model.Shift) {
+	sched.byWorker[worker] = append(sched.byWorker[worker], shift)
+	sched.byShift[shift.ID] = append(sched.byShift[shift.ID], sched.worker(worker))
+	sched.counts[shift.ID]++
+}
+
+func (sched *schedule) remove(worker model.WorkerID, shift *model.Shift) {
+	shifts := sched.byWorker[worker]
+	for i, s := range shifts {
+		if s.ID == shift.ID {
+			sched.byWorker[worker] = append(shifts[:i:i], shifts[i+1:]...)
+			sched.counts[shift.ID]--
+			break
+		}
+	}
+	workers := sched.byShift[shift.ID]
+	for i, w := range workers {
+		if w.ID == worker {
+			sched.byShift[shift.ID] = append(workers[:i:i], workers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Look up a worker, treating workers who aren't part of the problem
+// (but may have existing assignments) as having no skills.
+func (sched *schedule) worker(id model.WorkerID) *model.Worker {
+	if w, ok := sched.workers[id]; ok {
+		return w
+	}
+	return &model.Worker{ID: id}
+}
+
+// Total number of unfilled places across all shifts.
+func (sched *schedule) unfilled() int {
+	total := 0
+	for _, s := range sched.problem.Shifts {
+		if sched.counts[s.ID] < s.Capacity {
+			total += s.Capacity - sched.counts[s.ID]
+		}
+	}
+	return total
+}
+
+// Total number of hours a worker is assigned to.
+func (sched *schedule) hours(worker model.WorkerID) float64 {
+	total := 0.0
+	for _, s := range sched.byWorker[worker] {
+		total += s.EndTime.Sub(s.StartTime).Hours()
+	}
+	return total
+}
+
+// GreedySolver fills shifts in order of start time, assigning to each
+// place the worker with the fewest hours so far who can take it.
+type GreedySolver struct{}
+
+func (GreedySolver) Solve(problem *Problem) (*Solution, error) {
+	sched := newSchedule(problem)
+
+	shifts := make([]*model.Shift, len(problem.Shifts))
+	copy(shifts, problem.Shifts)
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return shifts[i].StartTime.Before(shifts[j].StartTime)
+	})
+
+	assignments := []model.ShiftAssignment{}
+	for _, shift := range shifts {
+		for sched.counts[shift.ID] < shift.Capacity {
+			var best *model.Worker
+			for _, w := range problem.Workers {
+				if !sched.canAdd(w.ID, shift) {
+					continue
+				}
+				if best == nil || sched.hours(w.ID) < sched.hours(best.ID) {
+					best = w
+				}
+			}
+			if best == nil {
+				break
+			}
+			sched.add(best.ID, shift)
+			assignments = append(assignments, model.ShiftAssignment{Worker: best.ID, Shift: shift.ID})
+		}
+	}
+
+	return &Solution{
+		Assignments: assignments,
+		Score:       sched.penalty(),
+	}, nil
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Build a test problem with a number of workers and three 8-hour
+// shifts per day for a number of days, all with the same capacity.
+func testProblem(nworkers int, ndays int, capacity int) *Problem {
+	workers := []*model.Worker{}
+	for i := 1; i <= nworkers; i++ {
+		workers = append(workers, &model.Worker{ID: model.WorkerID(i)})
+	}
+	shifts := []*model.Shift{}
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	for d := 0; d < ndays; d++ {
+		for h := 0; h < 24; h += 8 {
+			st := start.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour)
+			shifts = append(shifts, &model.Shift{
+				ID:        model.ShiftID(len(shifts) + 1),
+				StartTime: st,
+				EndTime:   st.Add(8 * time.Hour),
+				Capacity:  capacity,
+			})
+		}
+	}
+	return &Problem{Workers: workers, Shifts: shifts, Rules: DefaultRules, Weights: DefaultWeights}
+}
+
+// Check that a solution doesn't break capacity limits or the same-day
+// rule.
+func checkSolution(t *testing.T, problem *Problem, solution *Solution) {
+	t.Helper()
+	sched := newSchedule(problem)
+	for _, a := range solution.Assignments {
+		shift := sched.shifts[a.Shift]
+		if !sched.canAdd(a.Worker, shift) {
+			t.Fatalf("invalid assignment of worker %d to shift %d", a.Worker, a.Shift)
+		}
+		sched.add(a.Worker, shift)
+	}
+	if math.Abs(sched.penalty()-solution.Score) > 1e-9 {
+		t.Errorf("score %v doesn't match schedule penalty %v", solution.Score, sched.penalty())
+	}
+}
+
+// Count unfilled places for a solution.
+func unfilled(problem *Problem, solution *Solution) int {
+	sched := newSchedule(problem)
+	for _, a := range solution.Assignments {
+		sched.add(a.Worker, sched.shifts[a.Shift])
+	}
+	return sched.unfilled()
+}
+
+func TestGreedySolverFull(t *testing.T) {
+	problem := testProblem(6, 7, 2)
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	if n := unfilled(problem, solution); n != 0 {
+		t.Errorf("expected all shifts to be filled, %d unfilled", n)
+	}
+}
+
+func TestGreedySolverShortStaffed(t *testing.T) {
+	// Two workers can cover at most two of the three shifts each day.
+	problem := testProblem(2, 3, 1)
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	if n := unfilled(problem, solution); n != 3 {
+		t.Errorf("expected three unfilled places, %d unfilled", n)
+	}
+}
+
+func TestGreedySolverKeepsExisting(t *testing.T) {
+	problem := testProblem(3, 1, 1)
+	problem.Assignments = []model.ShiftAssignment{{Worker: 1, Shift: 2}}
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	for _, a := range solution.Assignments {
+		if a.Worker == 1 || a.Shift == 2 {
+			t.Errorf("unexpected assignment of worker %d to shift %d", a.Worker, a.Shift)
+		}
+	}
+}
+
+func TestGreedySolverRequirements(t *testing.T) {
+	// One of the two places on every shift is for a supervisor, and
+	// only worker 6 is one, so only one shift a day can be filled: the
+	// others only get a worker in their general place.
+	problem := testProblem(6, 2, 2)
+	problem.Workers[5].Skills = []string{"supervisor"}
+	for _, s := range problem.Shifts {
+		s.Requirements = []model.Requirement{{Skill: "supervisor", Count: 1}}
+	}
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	if n := unfilled(problem, solution); n != 4 {
+		t.Errorf("expected 4 unfilled places, got %d", n)
+	}
+}
+
+func TestGreedySolverTeams(t *testing.T) {
+	// Every shift belongs to team 1, which only has three of the six
+	// workers, so only three of each day's six places can be filled.
+	problem := testProblem(6, 2, 2)
+	team := model.TeamID(1)
+	for _, w := range problem.Workers[:3] {
+		w.Teams = []model.Membership{{Team: team}}
+	}
+	for _, s := range problem.Shifts {
+		s.Team = &team
+	}
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	if n := unfilled(problem, solution); n != 6 {
+		t.Errorf("expected 6 unfilled places, got %d", n)
+	}
+}
+
+func TestGreedySolverContext(t *testing.T) {
+	// Worker 1 finished a shift at midnight on the day before the
+	// problem starts, so with an 11 hour minimum rest they can't take
+	// the first morning shift, and worker 2 gets it.
+	problem := testProblem(2, 1, 1)
+	problem.Rules = append(RuleSet{MinRestRule{Hours: 11}}, DefaultRules...)
+	before := problem.Shifts[0].StartTime
+	problem.Context = []*model.Shift{{ID: 10, StartTime: before.Add(-8 * time.Hour), EndTime: before, Capacity: 1}}
+	problem.Assignments = []model.ShiftAssignment{{Worker: 1, Shift: 10}}
+	solution, err := GreedySolver{}.Solve(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, problem, solution)
+	for _, a := range solution.Assignments {
+		if a.Shift == 10 || (a.Shift == 1 && a.Worker == 1) {
+			t.Errorf("unexpected assignment of worker %d to shift %d", a.Worker, a.Shift)
+		}
+	}
+	if n := unfilled(problem, solution); n != 1 {
+		t.Errorf("expected one unfilled place, got %d", n)
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// GenerateShifts makes the shifts for a list of templates on each day
+// from start up to (but not including) end, in order of start time.
+// Days run from midnight in start's location.
+func GenerateShifts(templates []*model.ShiftTemplate, start time.Time, end time.Time) []*model.Shift {
+	y, m, d := start.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+
+	shifts := []*model.Shift{}
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, t := range templates {
+			if t.Weekdays.Has(day.Weekday()) {
+				shifts = append(shifts, t.Shift(day))
+			}
+		}
+	}
+	sort.SliceStable(shifts, func(i, j int) bool {
+		if !shifts[i].StartTime.Equal(shifts[j].StartTime) {
+			return shifts[i].StartTime.Before(shifts[j].StartTime)
+		}
+		return shifts[i].EndTime.Before(shifts[j].EndTime)
+	})
+	return shifts
+}
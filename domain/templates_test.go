@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestGenerateShifts(t *testing.T) {
+	weekdays := model.NewWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	weekends := model.NewWeekdays(time.Saturday, time.Sunday)
+	templates := []*model.ShiftTemplate{
+		{Weekdays: weekdays, StartHour: 16, EndHour: 24, Capacity: 2},
+		{Weekdays: weekdays, StartHour: 0, EndHour: 8, Capacity: 1},
+		{Weekdays: weekends, StartHour: 22, EndHour: 6, Capacity: 1},
+	}
+
+	// Friday 5 May to Monday 8 May 2023.
+	start := time.Date(2023, 5, 5, 0, 0, 0, 0, time.UTC)
+	shifts := GenerateShifts(templates, start, start.AddDate(0, 0, 4))
+	if len(shifts) != 6 {
+		t.Fatalf("expected 6 shifts, got %d", len(shifts))
+	}
+	for i := 1; i < len(shifts); i++ {
+		if shifts[i].StartTime.Before(shifts[i-1].StartTime) {
+			t.Errorf("shifts out of order at %d", i)
+		}
+	}
+
+	// Saturday's night shift runs into Sunday.
+	sat := shifts[2]
+	if sat.StartTime != start.AddDate(0, 0, 1).Add(22*time.Hour) ||
+		sat.EndTime != start.AddDate(0, 0, 2).Add(6*time.Hour) || sat.Capacity != 1 {
+		t.Errorf("unexpected overnight shift: %+v", sat)
+	}
+	if last := shifts[5]; last.StartTime != start.AddDate(0, 0, 3).Add(16*time.Hour) || last.Capacity != 2 {
+		t.Errorf("unexpected last shift: %+v", last)
+	}
+}
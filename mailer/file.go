@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileMailer writes each message it sends to a new file in a
+// directory, named for the time it was sent and the recipient.
+type FileMailer struct {
+	sync.Mutex
+	dir   string
+	count int
+}
+
+func NewFileMailer(dir string) (*FileMailer, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir}, nil
+}
+
+func (m *FileMailer) Send(msg *Message) error {
+	m.Lock()
+	defer m.Unlock()
+
+	// The counter keeps file names unique for messages sent in the same
+	// second.
+	m.count++
+	name := fmt.Sprintf("%s-%04d-%s.txt",
+		time.Now().UTC().Format("20060102T150405Z"), m.count, msg.To)
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+	return os.WriteFile(filepath.Join(m.dir, filepath.Base(name)), []byte(content), 0o644)
+}
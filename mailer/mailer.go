@@ -0,0 +1,46 @@
+// Package mailer sends email to workers, for things like invitations
+// and password resets. Mail is delivered through an SMTP server, and
+// there are implementations for testing and local development that
+// keep or log messages instead.
+package mailer
+
+import (
+	"errors"
+	"strings"
+)
+
+// Message is a plain text email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer is the interface for sending email.
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewMailer creates a mailer from a URL: "smtp://..." sends messages
+// through an SMTP server (see NewSMTPMailer), "memory" keeps messages
+// in memory (and logs them), and "file:<dir>" writes each message to a
+// file in a directory. Messages hold secrets like password reset
+// links, so the mailers that don't deliver them can only be used in
+// development mode, where "memory" is the default.
+func NewMailer(url string, dev bool) (Mailer, error) {
+	if strings.HasPrefix(url, "smtp:") {
+		return NewSMTPMailer(url)
+	}
+	if !dev {
+		return nil, errors.New("an smtp:// mailer URL is needed outside development mode")
+	}
+	if url == "" || url == "memory" {
+		m := NewMemoryMailer()
+		m.LogSecrets = true
+		return m, nil
+	}
+	if dir, ok := strings.CutPrefix(url, "file:"); ok && dir != "" {
+		return NewFileMailer(dir)
+	}
+	return nil, errors.New("unknown mailer URL: " + url)
+}
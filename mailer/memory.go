@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"log"
+	"regexp"
+	"sync"
+)
+
+// MemoryMailer keeps the messages it sends in memory, so that tests
+// can look at them. It also logs them, so that they can be seen when
+// running the server locally. The tokens in links are left out of the
+// log, unless LogSecrets is set.
+type MemoryMailer struct {
+	sync.Mutex
+	messages   []Message
+	LogSecrets bool
+}
+
+var tokenRe = regexp.MustCompile(`token=[^\s&]+`)
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{messages: []Message{}}
+}
+
+func (m *MemoryMailer) Send(msg *Message) error {
+	m.Lock()
+	defer m.Unlock()
+
+	body := msg.Body
+	if !m.LogSecrets {
+		body = tokenRe.ReplaceAllString(body, "token=[redacted]")
+	}
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, body)
+	m.messages = append(m.messages, *msg)
+	return nil
+}
+
+// Messages returns the messages sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.Lock()
+	defer m.Unlock()
+
+	return append([]Message{}, m.messages...)
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dotenv-org/godotenvvault"
 	"github.com/joeshaw/envdecode"
+	"skybluetrades.net/work-planning-demo/mailer"
 	"skybluetrades.net/work-planning-demo/server"
 	"skybluetrades.net/work-planning-demo/store"
 )
@@ -27,12 +35,17 @@ func main() {
 	}
 
 	// Create a store for the server: options are a simple in-memory
-	// store for testing, or Postgres (not implemented yet) determined
-	// by the STORE_URL environment variable.
+	// store for testing (persisted to disk with a URL like
+	// "memory?persist=/var/lib/planning"), SQLite (with a "sqlite://"
+	// URL) or Postgres, determined by the STORE_URL environment
+	// variable.
 	var db store.Store
-	if cfg.StoreURL == "memory" {
-		db, err = store.NewMemoryStore()
-	} else {
+	switch {
+	case cfg.StoreURL == "memory" || strings.HasPrefix(cfg.StoreURL, "memory?"):
+		db, err = store.NewMemoryStoreFromURL(cfg.StoreURL)
+	case strings.HasPrefix(cfg.StoreURL, "sqlite://"):
+		db, err = store.NewSQLiteStore(cfg.StoreURL)
+	default:
 		db, err = store.NewPostgresStore(cfg.StoreURL)
 	}
 	if err != nil {
@@ -40,9 +53,36 @@ func main() {
 	}
 	db.Migrate()
 
+	// Create a mailer for invitations and password resets.
+	mail, err := mailer.NewMailer(cfg.MailerURL, cfg.DevMode)
+	if err != nil {
+		log.Fatalln("Error creating mailer: ", err)
+	}
+
 	// Set up Echo server.
-	e := server.NewServer(&cfg, db, &staticFiles)
+	e := server.NewServer(&cfg, db, mail, &staticFiles)
 
 	// Off we go...
-	e.Logger.Fatal(e.Start(fmt.Sprintf("0.0.0.0:%d", cfg.Port)))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		err := e.Start(fmt.Sprintf("0.0.0.0:%d", cfg.Port))
+		if err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// Shut down gracefully on SIGINT or SIGTERM, letting requests in
+	// progress finish before closing the store.
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = e.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Println("Error shutting down server: ", err)
+	}
+	err = db.Close()
+	if err != nil {
+		log.Fatalln("Error closing store: ", err)
+	}
 }
@@ -1,8 +1,10 @@
-// Code generated by mockery v2.26.1. DO NOT EDIT.
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
 
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	model "skybluetrades.net/work-planning-demo/model"
 
@@ -16,25 +18,25 @@ type Store struct {
 	mock.Mock
 }
 
-// Authenticate provides a mock function with given fields: email, password
-func (_m *Store) Authenticate(email string, password string) (*model.Worker, error) {
-	ret := _m.Called(email, password)
+// Authenticate provides a mock function with given fields: ctx, email, password
+func (_m *Store) Authenticate(ctx context.Context, email string, password string) (*model.Worker, error) {
+	ret := _m.Called(ctx, email, password)
 
 	var r0 *model.Worker
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (*model.Worker, error)); ok {
-		return rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.Worker, error)); ok {
+		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *model.Worker); ok {
-		r0 = rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.Worker); ok {
+		r0 = rf(ctx, email, password)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Worker)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,13 +44,13 @@ func (_m *Store) Authenticate(email string, password string) (*model.Worker, err
 	return r0, r1
 }
 
-// CreateShift provides a mock function with given fields: shift
-func (_m *Store) CreateShift(shift *model.Shift) error {
-	ret := _m.Called(shift)
+// CheckSwap provides a mock function with given fields: ctx, swap
+func (_m *Store) CheckSwap(ctx context.Context, swap *model.Swap) error {
+	ret := _m.Called(ctx, swap)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Shift) error); ok {
-		r0 = rf(shift)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Swap) error); ok {
+		r0 = rf(ctx, swap)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,13 +58,13 @@ func (_m *Store) CreateShift(shift *model.Shift) error {
 	return r0
 }
 
-// CreateShiftAssignment provides a mock function with given fields: workerId, shiftId
-func (_m *Store) CreateShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error {
-	ret := _m.Called(workerId, shiftId)
+// ClearLoginAttempts provides a mock function with given fields: ctx, key
+func (_m *Store) ClearLoginAttempts(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.WorkerID, model.ShiftID) error); ok {
-		r0 = rf(workerId, shiftId)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,13 +72,13 @@ func (_m *Store) CreateShiftAssignment(workerId model.WorkerID, shiftId model.Sh
 	return r0
 }
 
-// CreateWorker provides a mock function with given fields: worker
-func (_m *Store) CreateWorker(worker *model.Worker) error {
-	ret := _m.Called(worker)
+// Close provides a mock function with given fields:
+func (_m *Store) Close() error {
+	ret := _m.Called()
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Worker) error); ok {
-		r0 = rf(worker)
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -84,13 +86,13 @@ func (_m *Store) CreateWorker(worker *model.Worker) error {
 	return r0
 }
 
-// DeleteShiftAssignment provides a mock function with given fields: workerId, shiftId
-func (_m *Store) DeleteShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error {
-	ret := _m.Called(workerId, shiftId)
+// CreateBid provides a mock function with given fields: ctx, bid
+func (_m *Store) CreateBid(ctx context.Context, bid *model.Bid) error {
+	ret := _m.Called(ctx, bid)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.WorkerID, model.ShiftID) error); ok {
-		r0 = rf(workerId, shiftId)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Bid) error); ok {
+		r0 = rf(ctx, bid)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -98,13 +100,13 @@ func (_m *Store) DeleteShiftAssignment(workerId model.WorkerID, shiftId model.Sh
 	return r0
 }
 
-// DeleteShiftById provides a mock function with given fields: id
-func (_m *Store) DeleteShiftById(id model.ShiftID) error {
-	ret := _m.Called(id)
+// CreateLockout provides a mock function with given fields: ctx, lockout
+func (_m *Store) CreateLockout(ctx context.Context, lockout *model.Lockout) error {
+	ret := _m.Called(ctx, lockout)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.ShiftID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Lockout) error); ok {
+		r0 = rf(ctx, lockout)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -112,13 +114,13 @@ func (_m *Store) DeleteShiftById(id model.ShiftID) error {
 	return r0
 }
 
-// DeleteWorkerById provides a mock function with given fields: id
-func (_m *Store) DeleteWorkerById(id model.WorkerID) error {
-	ret := _m.Called(id)
+// CreatePasswordToken provides a mock function with given fields: ctx, token
+func (_m *Store) CreatePasswordToken(ctx context.Context, token *model.PasswordToken) error {
+	ret := _m.Called(ctx, token)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.WorkerID) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PasswordToken) error); ok {
+		r0 = rf(ctx, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -126,25 +128,137 @@ func (_m *Store) DeleteWorkerById(id model.WorkerID) error {
 	return r0
 }
 
-// GetShiftById provides a mock function with given fields: id
-func (_m *Store) GetShiftById(id model.ShiftID) (*model.Shift, error) {
-	ret := _m.Called(id)
+// CreateRefreshToken provides a mock function with given fields: ctx, token
+func (_m *Store) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	ret := _m.Called(ctx, token)
 
-	var r0 *model.Shift
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *Store) CreateRole(ctx context.Context, role *model.Role) error {
+	ret := _m.Called(ctx, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Role) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRuleSet provides a mock function with given fields: ctx, ruleSet
+func (_m *Store) CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) error {
+	ret := _m.Called(ctx, ruleSet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RuleSet) error); ok {
+		r0 = rf(ctx, ruleSet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShift provides a mock function with given fields: ctx, shift
+func (_m *Store) CreateShift(ctx context.Context, shift *model.Shift) error {
+	ret := _m.Called(ctx, shift)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Shift) error); ok {
+		r0 = rf(ctx, shift)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShiftAssignment provides a mock function with given fields: ctx, workerId, shiftId, override
+func (_m *Store) CreateShiftAssignment(ctx context.Context, workerId model.WorkerID, shiftId model.ShiftID, override *model.RuleOverride) error {
+	ret := _m.Called(ctx, workerId, shiftId, override)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, model.ShiftID, *model.RuleOverride) error); ok {
+		r0 = rf(ctx, workerId, shiftId, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShiftAssignments provides a mock function with given fields: ctx, assignments
+func (_m *Store) CreateShiftAssignments(ctx context.Context, assignments []model.ShiftAssignment) error {
+	ret := _m.Called(ctx, assignments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.ShiftAssignment) error); ok {
+		r0 = rf(ctx, assignments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShiftPreference provides a mock function with given fields: ctx, pref
+func (_m *Store) CreateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error {
+	ret := _m.Called(ctx, pref)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShiftPreference) error); ok {
+		r0 = rf(ctx, pref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShiftTemplate provides a mock function with given fields: ctx, template
+func (_m *Store) CreateShiftTemplate(ctx context.Context, template *model.ShiftTemplate) error {
+	ret := _m.Called(ctx, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShiftTemplate) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateShifts provides a mock function with given fields: ctx, shifts
+func (_m *Store) CreateShifts(ctx context.Context, shifts []*model.Shift) ([]*model.Shift, error) {
+	ret := _m.Called(ctx, shifts)
+
+	var r0 []*model.Shift
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.ShiftID) (*model.Shift, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Shift) ([]*model.Shift, error)); ok {
+		return rf(ctx, shifts)
 	}
-	if rf, ok := ret.Get(0).(func(model.ShiftID) *model.Shift); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Shift) []*model.Shift); ok {
+		r0 = rf(ctx, shifts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Shift)
+			r0 = ret.Get(0).([]*model.Shift)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(model.ShiftID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Shift) error); ok {
+		r1 = rf(ctx, shifts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -152,25 +266,263 @@ func (_m *Store) GetShiftById(id model.ShiftID) (*model.Shift, error) {
 	return r0, r1
 }
 
-// GetShifts provides a mock function with given fields: date, span, workerId
-func (_m *Store) GetShifts(date *time.Time, span store.TimeSpan, workerId *model.WorkerID) ([]*model.Shift, error) {
-	ret := _m.Called(date, span, workerId)
+// CreateSwap provides a mock function with given fields: ctx, swap
+func (_m *Store) CreateSwap(ctx context.Context, swap *model.Swap) error {
+	ret := _m.Called(ctx, swap)
 
-	var r0 []*model.Shift
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Swap) error); ok {
+		r0 = rf(ctx, swap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTeam provides a mock function with given fields: ctx, team
+func (_m *Store) CreateTeam(ctx context.Context, team *model.Team) error {
+	ret := _m.Called(ctx, team)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Team) error); ok {
+		r0 = rf(ctx, team)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTimeOff provides a mock function with given fields: ctx, timeOff
+func (_m *Store) CreateTimeOff(ctx context.Context, timeOff *model.TimeOff) error {
+	ret := _m.Called(ctx, timeOff)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TimeOff) error); ok {
+		r0 = rf(ctx, timeOff)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWorker provides a mock function with given fields: ctx, worker
+func (_m *Store) CreateWorker(ctx context.Context, worker *model.Worker) error {
+	ret := _m.Called(ctx, worker)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Worker) error); ok {
+		r0 = rf(ctx, worker)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBidById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteBidById(ctx context.Context, id model.BidID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.BidID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRoleById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteRoleById(ctx context.Context, id model.RoleID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.RoleID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRuleSetById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteRuleSetById(ctx context.Context, id model.RuleSetID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.RuleSetID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShiftAssignment provides a mock function with given fields: ctx, workerId, shiftId
+func (_m *Store) DeleteShiftAssignment(ctx context.Context, workerId model.WorkerID, shiftId model.ShiftID) error {
+	ret := _m.Called(ctx, workerId, shiftId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, model.ShiftID) error); ok {
+		r0 = rf(ctx, workerId, shiftId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShiftById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteShiftById(ctx context.Context, id model.ShiftID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShiftPreferenceById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftPreferenceID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShiftTemplateById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftTemplateID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTeamById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteTeamById(ctx context.Context, id model.TeamID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TeamID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTeamMember provides a mock function with given fields: ctx, teamId, workerId
+func (_m *Store) DeleteTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID) error {
+	ret := _m.Called(ctx, teamId, workerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TeamID, model.WorkerID) error); ok {
+		r0 = rf(ctx, teamId, workerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTimeOffById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteTimeOffById(ctx context.Context, id model.TimeOffID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeOffID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTwoFactor provides a mock function with given fields: ctx, workerId
+func (_m *Store) DeleteTwoFactor(ctx context.Context, workerId model.WorkerID) error {
+	ret := _m.Called(ctx, workerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) error); ok {
+		r0 = rf(ctx, workerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWorkerById provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteWorkerById(ctx context.Context, id model.WorkerID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExecuteSwap provides a mock function with given fields: ctx, id
+func (_m *Store) ExecuteSwap(ctx context.Context, id model.SwapID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.SwapID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBidById provides a mock function with given fields: ctx, id
+func (_m *Store) GetBidById(ctx context.Context, id model.BidID) (*model.Bid, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Bid
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*time.Time, store.TimeSpan, *model.WorkerID) ([]*model.Shift, error)); ok {
-		return rf(date, span, workerId)
+	if rf, ok := ret.Get(0).(func(context.Context, model.BidID) (*model.Bid, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(*time.Time, store.TimeSpan, *model.WorkerID) []*model.Shift); ok {
-		r0 = rf(date, span, workerId)
+	if rf, ok := ret.Get(0).(func(context.Context, model.BidID) *model.Bid); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Shift)
+			r0 = ret.Get(0).(*model.Bid)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*time.Time, store.TimeSpan, *model.WorkerID) error); ok {
-		r1 = rf(date, span, workerId)
+	if rf, ok := ret.Get(1).(func(context.Context, model.BidID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -178,25 +530,25 @@ func (_m *Store) GetShifts(date *time.Time, span store.TimeSpan, workerId *model
 	return r0, r1
 }
 
-// GetWorkerById provides a mock function with given fields: id
-func (_m *Store) GetWorkerById(id model.WorkerID) (*model.Worker, error) {
-	ret := _m.Called(id)
+// GetBids provides a mock function with given fields: ctx, workerId, shiftId
+func (_m *Store) GetBids(ctx context.Context, workerId *model.WorkerID, shiftId *model.ShiftID) ([]*model.Bid, error) {
+	ret := _m.Called(ctx, workerId, shiftId)
 
-	var r0 *model.Worker
+	var r0 []*model.Bid
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.WorkerID) (*model.Worker, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.ShiftID) ([]*model.Bid, error)); ok {
+		return rf(ctx, workerId, shiftId)
 	}
-	if rf, ok := ret.Get(0).(func(model.WorkerID) *model.Worker); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.ShiftID) []*model.Bid); ok {
+		r0 = rf(ctx, workerId, shiftId)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Worker)
+			r0 = ret.Get(0).([]*model.Bid)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(model.WorkerID) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WorkerID, *model.ShiftID) error); ok {
+		r1 = rf(ctx, workerId, shiftId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -204,25 +556,25 @@ func (_m *Store) GetWorkerById(id model.WorkerID) (*model.Worker, error) {
 	return r0, r1
 }
 
-// GetWorkers provides a mock function with given fields:
-func (_m *Store) GetWorkers() ([]*model.Worker, error) {
-	ret := _m.Called()
+// GetLockouts provides a mock function with given fields: ctx
+func (_m *Store) GetLockouts(ctx context.Context) ([]*model.Lockout, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []*model.Worker
+	var r0 []*model.Lockout
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*model.Worker, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Lockout, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []*model.Worker); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Lockout); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Worker)
+			r0 = ret.Get(0).([]*model.Lockout)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -230,32 +582,1008 @@ func (_m *Store) GetWorkers() ([]*model.Worker, error) {
 	return r0, r1
 }
 
-// Migrate provides a mock function with given fields:
-func (_m *Store) Migrate() {
-	_m.Called()
-}
+// GetLoginAttempts provides a mock function with given fields: ctx, key
+func (_m *Store) GetLoginAttempts(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	ret := _m.Called(ctx, key)
 
-// UpdateShift provides a mock function with given fields: shift
-func (_m *Store) UpdateShift(shift *model.Shift) error {
-	ret := _m.Called(shift)
+	var r0 *model.LoginAttempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.LoginAttempts, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.LoginAttempts); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginAttempts)
+		}
+	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Shift) error); ok {
-		r0 = rf(shift)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
+}
+
+// GetRoleById provides a mock function with given fields: ctx, id
+func (_m *Store) GetRoleById(ctx context.Context, id model.RoleID) (*model.Role, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.RoleID) (*model.Role, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.RoleID) *model.Role); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.RoleID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoles provides a mock function with given fields: ctx
+func (_m *Store) GetRoles(ctx context.Context) ([]*model.Role, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuleOverrides provides a mock function with given fields: ctx, shiftId
+func (_m *Store) GetRuleOverrides(ctx context.Context, shiftId *model.ShiftID) ([]*model.RuleOverride, error) {
+	ret := _m.Called(ctx, shiftId)
+
+	var r0 []*model.RuleOverride
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShiftID) ([]*model.RuleOverride, error)); ok {
+		return rf(ctx, shiftId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShiftID) []*model.RuleOverride); ok {
+		r0 = rf(ctx, shiftId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.RuleOverride)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.ShiftID) error); ok {
+		r1 = rf(ctx, shiftId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuleSetAt provides a mock function with given fields: ctx, t
+func (_m *Store) GetRuleSetAt(ctx context.Context, t time.Time) (*model.RuleSet, error) {
+	ret := _m.Called(ctx, t)
+
+	var r0 *model.RuleSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (*model.RuleSet, error)); ok {
+		return rf(ctx, t)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) *model.RuleSet); ok {
+		r0 = rf(ctx, t)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RuleSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuleSetById provides a mock function with given fields: ctx, id
+func (_m *Store) GetRuleSetById(ctx context.Context, id model.RuleSetID) (*model.RuleSet, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.RuleSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.RuleSetID) (*model.RuleSet, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.RuleSetID) *model.RuleSet); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RuleSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.RuleSetID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRuleSets provides a mock function with given fields: ctx
+func (_m *Store) GetRuleSets(ctx context.Context) ([]*model.RuleSet, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.RuleSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.RuleSet, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.RuleSet); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.RuleSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftAssignmentsInRange provides a mock function with given fields: ctx, start, end
+func (_m *Store) GetShiftAssignmentsInRange(ctx context.Context, start time.Time, end time.Time) ([]model.ShiftAssignment, error) {
+	ret := _m.Called(ctx, start, end)
+
+	var r0 []model.ShiftAssignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]model.ShiftAssignment, error)); ok {
+		return rf(ctx, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []model.ShiftAssignment); ok {
+		r0 = rf(ctx, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ShiftAssignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftById provides a mock function with given fields: ctx, id
+func (_m *Store) GetShiftById(ctx context.Context, id model.ShiftID) (*model.Shift, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Shift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftID) (*model.Shift, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftID) *model.Shift); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Shift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ShiftID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftPreferenceById provides a mock function with given fields: ctx, id
+func (_m *Store) GetShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) (*model.ShiftPreference, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ShiftPreference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftPreferenceID) (*model.ShiftPreference, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftPreferenceID) *model.ShiftPreference); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ShiftPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ShiftPreferenceID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftPreferences provides a mock function with given fields: ctx, workerId
+func (_m *Store) GetShiftPreferences(ctx context.Context, workerId *model.WorkerID) ([]*model.ShiftPreference, error) {
+	ret := _m.Called(ctx, workerId)
+
+	var r0 []*model.ShiftPreference
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID) ([]*model.ShiftPreference, error)); ok {
+		return rf(ctx, workerId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID) []*model.ShiftPreference); ok {
+		r0 = rf(ctx, workerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ShiftPreference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WorkerID) error); ok {
+		r1 = rf(ctx, workerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftTemplateById provides a mock function with given fields: ctx, id
+func (_m *Store) GetShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) (*model.ShiftTemplate, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.ShiftTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftTemplateID) (*model.ShiftTemplate, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftTemplateID) *model.ShiftTemplate); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ShiftTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.ShiftTemplateID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftTemplates provides a mock function with given fields: ctx
+func (_m *Store) GetShiftTemplates(ctx context.Context) ([]*model.ShiftTemplate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.ShiftTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.ShiftTemplate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.ShiftTemplate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ShiftTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShifts provides a mock function with given fields: ctx, date, span, workerId, teamId
+func (_m *Store) GetShifts(ctx context.Context, date *time.Time, span store.TimeSpan, workerId *model.WorkerID, teamId *model.TeamID) ([]*model.Shift, error) {
+	ret := _m.Called(ctx, date, span, workerId, teamId)
+
+	var r0 []*model.Shift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, store.TimeSpan, *model.WorkerID, *model.TeamID) ([]*model.Shift, error)); ok {
+		return rf(ctx, date, span, workerId, teamId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, store.TimeSpan, *model.WorkerID, *model.TeamID) []*model.Shift); ok {
+		r0 = rf(ctx, date, span, workerId, teamId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Shift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, store.TimeSpan, *model.WorkerID, *model.TeamID) error); ok {
+		r1 = rf(ctx, date, span, workerId, teamId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShiftsInRange provides a mock function with given fields: ctx, start, end
+func (_m *Store) GetShiftsInRange(ctx context.Context, start time.Time, end time.Time) ([]*model.Shift, error) {
+	ret := _m.Called(ctx, start, end)
+
+	var r0 []*model.Shift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*model.Shift, error)); ok {
+		return rf(ctx, start, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*model.Shift); ok {
+		r0 = rf(ctx, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Shift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSwapById provides a mock function with given fields: ctx, id
+func (_m *Store) GetSwapById(ctx context.Context, id model.SwapID) (*model.Swap, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Swap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.SwapID) (*model.Swap, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.SwapID) *model.Swap); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Swap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.SwapID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSwaps provides a mock function with given fields: ctx, workerId, status
+func (_m *Store) GetSwaps(ctx context.Context, workerId *model.WorkerID, status *model.SwapStatus) ([]*model.Swap, error) {
+	ret := _m.Called(ctx, workerId, status)
+
+	var r0 []*model.Swap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.SwapStatus) ([]*model.Swap, error)); ok {
+		return rf(ctx, workerId, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.SwapStatus) []*model.Swap); ok {
+		r0 = rf(ctx, workerId, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Swap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WorkerID, *model.SwapStatus) error); ok {
+		r1 = rf(ctx, workerId, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamById provides a mock function with given fields: ctx, id
+func (_m *Store) GetTeamById(ctx context.Context, id model.TeamID) (*model.Team, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TeamID) (*model.Team, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TeamID) *model.Team); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TeamID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeams provides a mock function with given fields: ctx
+func (_m *Store) GetTeams(ctx context.Context) ([]*model.Team, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.Team
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Team, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Team); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Team)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimeOff provides a mock function with given fields: ctx, workerId, status
+func (_m *Store) GetTimeOff(ctx context.Context, workerId *model.WorkerID, status *model.TimeOffStatus) ([]*model.TimeOff, error) {
+	ret := _m.Called(ctx, workerId, status)
+
+	var r0 []*model.TimeOff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.TimeOffStatus) ([]*model.TimeOff, error)); ok {
+		return rf(ctx, workerId, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WorkerID, *model.TimeOffStatus) []*model.TimeOff); ok {
+		r0 = rf(ctx, workerId, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TimeOff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WorkerID, *model.TimeOffStatus) error); ok {
+		r1 = rf(ctx, workerId, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimeOffById provides a mock function with given fields: ctx, id
+func (_m *Store) GetTimeOffById(ctx context.Context, id model.TimeOffID) (*model.TimeOff, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.TimeOff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeOffID) (*model.TimeOff, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeOffID) *model.TimeOff); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TimeOff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TimeOffID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactor provides a mock function with given fields: ctx, workerId
+func (_m *Store) GetTwoFactor(ctx context.Context, workerId model.WorkerID) (*model.TwoFactor, error) {
+	ret := _m.Called(ctx, workerId)
+
+	var r0 *model.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) (*model.TwoFactor, error)); ok {
+		return rf(ctx, workerId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) *model.TwoFactor); ok {
+		r0 = rf(ctx, workerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TwoFactor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.WorkerID) error); ok {
+		r1 = rf(ctx, workerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkerByEmail provides a mock function with given fields: ctx, email
+func (_m *Store) GetWorkerByEmail(ctx context.Context, email string) (*model.Worker, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 *model.Worker
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Worker, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Worker); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Worker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkerById provides a mock function with given fields: ctx, id
+func (_m *Store) GetWorkerById(ctx context.Context, id model.WorkerID) (*model.Worker, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Worker
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) (*model.Worker, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) *model.Worker); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Worker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.WorkerID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkers provides a mock function with given fields: ctx, teamId
+func (_m *Store) GetWorkers(ctx context.Context, teamId *model.TeamID) ([]*model.Worker, error) {
+	ret := _m.Called(ctx, teamId)
+
+	var r0 []*model.Worker
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TeamID) ([]*model.Worker, error)); ok {
+		return rf(ctx, teamId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TeamID) []*model.Worker); ok {
+		r0 = rf(ctx, teamId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Worker)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.TeamID) error); ok {
+		r1 = rf(ctx, teamId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockLogin provides a mock function with given fields: ctx, key, until
+func (_m *Store) LockLogin(ctx context.Context, key string, until time.Time) error {
+	ret := _m.Called(ctx, key, until)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, key, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Migrate provides a mock function with given fields:
+func (_m *Store) Migrate() {
+	_m.Called()
+}
+
+// MoveShiftAssignment provides a mock function with given fields: ctx, workerId, from, to, override
+func (_m *Store) MoveShiftAssignment(ctx context.Context, workerId model.WorkerID, from model.ShiftID, to model.ShiftID, override *model.RuleOverride) error {
+	ret := _m.Called(ctx, workerId, from, to, override)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, model.ShiftID, model.ShiftID, *model.RuleOverride) error); ok {
+		r0 = rf(ctx, workerId, from, to, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordLoginFailure provides a mock function with given fields: ctx, key, window
+func (_m *Store) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*model.LoginAttempts, error) {
+	ret := _m.Called(ctx, key, window)
+
+	var r0 *model.LoginAttempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (*model.LoginAttempts, error)); ok {
+		return rf(ctx, key, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) *model.LoginAttempts); ok {
+		r0 = rf(ctx, key, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginAttempts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordTOTPStep provides a mock function with given fields: ctx, workerId, step
+func (_m *Store) RecordTOTPStep(ctx context.Context, workerId model.WorkerID, step int64) error {
+	ret := _m.Called(ctx, workerId, step)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, int64) error); ok {
+		r0 = rf(ctx, workerId, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenFamilyRevoked provides a mock function with given fields: ctx, family
+func (_m *Store) RefreshTokenFamilyRevoked(ctx context.Context, family string) (bool, error) {
+	ret := _m.Called(ctx, family)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, family)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, family)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, family)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceShiftAssignments provides a mock function with given fields: ctx, remove, add
+func (_m *Store) ReplaceShiftAssignments(ctx context.Context, remove []model.ShiftAssignment, add []model.ShiftAssignment) error {
+	ret := _m.Called(ctx, remove, add)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.ShiftAssignment, []model.ShiftAssignment) error); ok {
+		r0 = rf(ctx, remove, add)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveBids provides a mock function with given fields: ctx, shiftId, order
+func (_m *Store) ResolveBids(ctx context.Context, shiftId model.ShiftID, order []model.BidID) error {
+	ret := _m.Called(ctx, shiftId, order)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ShiftID, []model.BidID) error); ok {
+		r0 = rf(ctx, shiftId, order)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, family
+func (_m *Store) RevokeRefreshTokenFamily(ctx context.Context, family string) error {
+	ret := _m.Called(ctx, family)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, family)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeWorkerRefreshTokens provides a mock function with given fields: ctx, workerId
+func (_m *Store) RevokeWorkerRefreshTokens(ctx context.Context, workerId model.WorkerID) error {
+	ret := _m.Called(ctx, workerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID) error); ok {
+		r0 = rf(ctx, workerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTeamMember provides a mock function with given fields: ctx, teamId, workerId, isAdmin
+func (_m *Store) SetTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID, isAdmin bool) error {
+	ret := _m.Called(ctx, teamId, workerId, isAdmin)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TeamID, model.WorkerID, bool) error); ok {
+		r0 = rf(ctx, teamId, workerId, isAdmin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTwoFactor provides a mock function with given fields: ctx, tf
+func (_m *Store) SetTwoFactor(ctx context.Context, tf *model.TwoFactor) error {
+	ret := _m.Called(ctx, tf)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TwoFactor) error); ok {
+		r0 = rf(ctx, tf)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkerPassword provides a mock function with given fields: ctx, id, password
+func (_m *Store) SetWorkerPassword(ctx context.Context, id model.WorkerID, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, role
+func (_m *Store) UpdateRole(ctx context.Context, role *model.Role) error {
+	ret := _m.Called(ctx, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Role) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateShift provides a mock function with given fields: ctx, shift
+func (_m *Store) UpdateShift(ctx context.Context, shift *model.Shift) error {
+	ret := _m.Called(ctx, shift)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Shift) error); ok {
+		r0 = rf(ctx, shift)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateShiftPreference provides a mock function with given fields: ctx, pref
+func (_m *Store) UpdateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error {
+	ret := _m.Called(ctx, pref)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.ShiftPreference) error); ok {
+		r0 = rf(ctx, pref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSwap provides a mock function with given fields: ctx, swap
+func (_m *Store) UpdateSwap(ctx context.Context, swap *model.Swap) error {
+	ret := _m.Called(ctx, swap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Swap) error); ok {
+		r0 = rf(ctx, swap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTimeOffStatus provides a mock function with given fields: ctx, id, status
+func (_m *Store) UpdateTimeOffStatus(ctx context.Context, id model.TimeOffID, status model.TimeOffStatus) error {
+	ret := _m.Called(ctx, id, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TimeOffID, model.TimeOffStatus) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWorker provides a mock function with given fields: ctx, worker
+func (_m *Store) UpdateWorker(ctx context.Context, worker *model.Worker) error {
+	ret := _m.Called(ctx, worker)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Worker) error); ok {
+		r0 = rf(ctx, worker)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UsePasswordToken provides a mock function with given fields: ctx, id, purpose
+func (_m *Store) UsePasswordToken(ctx context.Context, id string, purpose model.PasswordTokenPurpose) (*model.PasswordToken, error) {
+	ret := _m.Called(ctx, id, purpose)
+
+	var r0 *model.PasswordToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PasswordTokenPurpose) (*model.PasswordToken, error)); ok {
+		return rf(ctx, id, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PasswordTokenPurpose) *model.PasswordToken); ok {
+		r0 = rf(ctx, id, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PasswordToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.PasswordTokenPurpose) error); ok {
+		r1 = rf(ctx, id, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, workerId, hash
+func (_m *Store) UseRecoveryCode(ctx context.Context, workerId model.WorkerID, hash string) error {
+	ret := _m.Called(ctx, workerId, hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WorkerID, string) error); ok {
+		r0 = rf(ctx, workerId, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRefreshToken provides a mock function with given fields: ctx, id
+func (_m *Store) UseRefreshToken(ctx context.Context, id string) (*model.RefreshToken, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.RefreshToken, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.RefreshToken); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpdateWorker provides a mock function with given fields: worker
-func (_m *Store) UpdateWorker(worker *model.Worker) error {
-	ret := _m.Called(worker)
+// WithTx provides a mock function with given fields: ctx, fn
+func (_m *Store) WithTx(ctx context.Context, fn func(store.Store) error) error {
+	ret := _m.Called(ctx, fn)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Worker) error); ok {
-		r0 = rf(worker)
+	if rf, ok := ret.Get(0).(func(context.Context, func(store.Store) error) error); ok {
+		r0 = rf(ctx, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
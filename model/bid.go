@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type BidID int64
+
+// BidStatus is the state of a worker's bid for a shift. Bids for
+// shifts with free places are pending until an admin resolves them;
+// bids for full shifts, and bids that lose out when bidding is
+// resolved, are waitlisted; bids that get the worker a place on the
+// shift are awarded.
+type BidStatus string
+
+const (
+	BidPending    BidStatus = "pending"
+	BidWaitlisted BidStatus = "waitlisted"
+	BidAwarded    BidStatus = "awarded"
+)
+
+// Bid is a worker's request for a place on a shift. Position is the
+// bid's place in the shift's waitlist (counting from 1), and is only
+// set for waitlisted bids.
+type Bid struct {
+	ID        BidID     `db:"id"`
+	Worker    WorkerID  `db:"worker_id"`
+	Shift     ShiftID   `db:"shift_id"`
+	Status    BidStatus `db:"status"`
+	Position  int       `db:"position"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func BidToAPI(b *Bid) *api.Bid {
+	bid := &api.Bid{
+		Id:        int64(b.ID),
+		WorkerId:  int64(b.Worker),
+		ShiftId:   int64(b.Shift),
+		Status:    api.BidStatus(b.Status),
+		CreatedAt: b.CreatedAt,
+	}
+	if b.Status == BidWaitlisted {
+		position := b.Position
+		bid.Position = &position
+	}
+	return bid
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+// LoginAttempts tracks the recent failed logins for an account or a
+// client IP address, identified by a key, and whether logins for it
+// are locked.
+type LoginAttempts struct {
+	Key         string     `db:"key"`
+	Failures    int        `db:"failures"`
+	LastFailure time.Time  `db:"last_failure"`
+	LockedUntil *time.Time `db:"locked_until"`
+}
+
+// Locked checks whether logins are locked at a given time.
+func (a *LoginAttempts) Locked(now time.Time) bool {
+	return a.LockedUntil != nil && a.LockedUntil.After(now)
+}
+
+type LockoutID int64
+
+// LockoutKind is what was locked out after too many failed logins: an
+// account (identified by email address) or a client IP address.
+type LockoutKind string
+
+const (
+	LockoutAccount LockoutKind = "account"
+	LockoutIP      LockoutKind = "ip"
+)
+
+// Lockout is an audit record of logins being locked after too many
+// failures. Worker is set for account lockouts where the email address
+// belongs to a worker.
+type Lockout struct {
+	ID          LockoutID   `db:"id"`
+	Kind        LockoutKind `db:"kind"`
+	Subject     string      `db:"subject"`
+	Worker      *WorkerID   `db:"worker_id"`
+	Failures    int         `db:"failures"`
+	LockedAt    time.Time   `db:"locked_at"`
+	LockedUntil time.Time   `db:"locked_until"`
+}
+
+func LockoutToAPI(l *Lockout) *api.Lockout {
+	lockout := &api.Lockout{
+		Id:          int64(l.ID),
+		Kind:        api.LockoutKind(l.Kind),
+		Subject:     l.Subject,
+		Failures:    l.Failures,
+		LockedAt:    l.LockedAt,
+		LockedUntil: l.LockedUntil,
+	}
+	if l.Worker != nil {
+		id := int64(*l.Worker)
+		lockout.WorkerId = &id
+	}
+	return lockout
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// PasswordTokenPurpose is what a password token can be used for:
+// accepting an invitation to set an initial password, or resetting a
+// forgotten one.
+type PasswordTokenPurpose string
+
+const (
+	PasswordInvitation PasswordTokenPurpose = "invitation"
+	PasswordReset      PasswordTokenPurpose = "reset"
+)
+
+// PasswordToken is a single-use, time-limited token that lets a worker
+// set their password without knowing the old one. Tokens are mailed to
+// workers, and only a hash of each token is stored, in the ID field.
+type PasswordToken struct {
+	ID        string               `db:"id"`
+	Worker    WorkerID             `db:"worker_id"`
+	Purpose   PasswordTokenPurpose `db:"purpose"`
+	ExpiresAt time.Time            `db:"expires_at"`
+	Used      bool                 `db:"used"`
+}
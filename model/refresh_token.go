@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// RefreshToken records a refresh token that has been issued, so that
+// each one can only be used once and so that tokens can be revoked.
+// Refreshing tokens gives a new refresh token in the same family as
+// the old one, so every token that comes from a single login is in the
+// same family.
+type RefreshToken struct {
+	ID        string    `db:"id"`
+	Family    string    `db:"family"`
+	Worker    WorkerID  `db:"worker_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+	Used      bool      `db:"used"`
+	Revoked   bool      `db:"revoked"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type RoleID int64
+
+// Permission allows a group of operations, and is used as a security
+// scope in the OpenAPI spec. Organisation admins have every
+// permission; other workers have the permissions of their roles.
+type Permission string
+
+const (
+	PermWorkersRead      Permission = "workers:read"
+	PermWorkersWrite     Permission = "workers:write"
+	PermShiftsRead       Permission = "shifts:read"
+	PermShiftsWrite      Permission = "shifts:write"
+	PermAssignmentsWrite Permission = "assignments:write"
+	PermScheduleRead     Permission = "schedule:read"
+	PermTimeOffManage    Permission = "time-off:manage"
+	PermSwapsManage      Permission = "swaps:manage"
+	PermRulesManage      Permission = "rules:manage"
+	PermTeamsManage      Permission = "teams:manage"
+	PermRolesManage      Permission = "roles:manage"
+)
+
+// AllPermissions lists every permission that roles can grant.
+var AllPermissions = []Permission{
+	PermWorkersRead, PermWorkersWrite, PermShiftsRead, PermShiftsWrite,
+	PermAssignmentsWrite, PermScheduleRead, PermTimeOffManage,
+	PermSwapsManage, PermRulesManage, PermTeamsManage, PermRolesManage,
+}
+
+// Role is a named set of permissions, like "scheduler" or "payroll
+// viewer", that can be given to workers.
+type Role struct {
+	ID          RoleID       `db:"id"`
+	Name        string       `db:"name"`
+	Permissions []Permission `db:"-"`
+}
+
+func RoleFromAPI(r *api.Role) *Role {
+	var id int64
+	if r.Id != nil {
+		id = *r.Id
+	}
+	role := &Role{
+		ID:          RoleID(id),
+		Name:        strings.TrimSpace(r.Name),
+		Permissions: []Permission{},
+	}
+	for _, p := range r.Permissions {
+		if !slices.Contains(role.Permissions, Permission(p)) {
+			role.Permissions = append(role.Permissions, Permission(p))
+		}
+	}
+	return role
+}
+
+func RoleToAPI(r *Role) *api.Role {
+	id := int64(r.ID)
+	perms := []api.Permission{}
+	for _, p := range r.Permissions {
+		perms = append(perms, api.Permission(p))
+	}
+	return &api.Role{Id: &id, Name: r.Name, Permissions: perms}
+}
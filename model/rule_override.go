@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type RuleOverrideID int64
+
+// RuleOverride records an admin's decision to assign a worker to a
+// shift even though it breaks soft scheduling rules, along with the
+// reason given and the rule violations that were overridden.
+type RuleOverride struct {
+	ID         RuleOverrideID `db:"id"`
+	Worker     WorkerID       `db:"worker_id"`
+	Shift      ShiftID        `db:"shift_id"`
+	Admin      WorkerID       `db:"admin_id"`
+	Reason     string         `db:"reason"`
+	Violations string         `db:"violations"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func RuleOverrideToAPI(o *RuleOverride) *api.RuleOverride {
+	return &api.RuleOverride{
+		Id:         int64(o.ID),
+		WorkerId:   int64(o.Worker),
+		ShiftId:    int64(o.Shift),
+		AdminId:    int64(o.Admin),
+		Reason:     o.Reason,
+		Violations: o.Violations,
+		CreatedAt:  o.CreatedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type RuleSetID int64
+
+// RuleConfig selects one of the built-in scheduling rules and sets its
+// parameters.
+type RuleConfig struct {
+	Name  string  `db:"name"`
+	Limit float64 `db:"limit_value"`
+	Soft  bool    `db:"soft"`
+}
+
+// RuleSet is a version of the scheduling rule configuration. The
+// version in effect at any time is the one with the latest
+// EffectiveFrom date not after that time (with later versions winning
+// ties).
+type RuleSet struct {
+	ID            RuleSetID    `db:"id"`
+	EffectiveFrom time.Time    `db:"effective_from"`
+	Rules         []RuleConfig `db:"-"`
+}
+
+func RuleSetFromAPI(rs *api.RuleSet) *RuleSet {
+	ruleSet := &RuleSet{Rules: []RuleConfig{}}
+	if rs.Id != nil {
+		ruleSet.ID = RuleSetID(*rs.Id)
+	}
+	if rs.EffectiveFrom != nil {
+		ruleSet.EffectiveFrom = rs.EffectiveFrom.Time
+	}
+	for _, r := range rs.Rules {
+		cfg := RuleConfig{Name: string(r.Name)}
+		if r.Limit != nil {
+			cfg.Limit = *r.Limit
+		}
+		if r.Soft != nil {
+			cfg.Soft = *r.Soft
+		}
+		ruleSet.Rules = append(ruleSet.Rules, cfg)
+	}
+	return ruleSet
+}
+
+// RuleSetToAPI converts a rule set for the API. Rule sets with a zero
+// ID are the built-in defaults, which have no ID or effective date.
+func RuleSetToAPI(rs *RuleSet) *api.RuleSet {
+	ruleSet := &api.RuleSet{Rules: []api.Rule{}}
+	if rs.ID != 0 {
+		id := int64(rs.ID)
+		ruleSet.Id = &id
+		ruleSet.EffectiveFrom = &openapi_types.Date{Time: rs.EffectiveFrom}
+	}
+	for _, r := range rs.Rules {
+		limit := r.Limit
+		soft := r.Soft
+		ruleSet.Rules = append(ruleSet.Rules, api.Rule{
+			Name:  api.RuleName(r.Name),
+			Limit: &limit,
+			Soft:  &soft,
+		})
+	}
+	return ruleSet
+}
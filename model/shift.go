@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"skybluetrades.net/work-planning-demo/api"
@@ -8,11 +9,27 @@ import (
 
 type ShiftID int64
 
+// Shift is a period of work with places for Capacity workers. Some
+// of the places may be reserved for workers with particular skills by
+// Requirements. Shifts belonging to a Team can only be worked by the
+// team's members; shifts without one are open to every worker.
+// BiddingClosed is set once an admin has resolved the bids for the
+// shift.
 type Shift struct {
-	ID        ShiftID   `db:"id"`
-	StartTime time.Time `db:"start_time"`
-	EndTime   time.Time `db:"end_time"`
-	Capacity  int       `db:"capacity"`
+	ID            ShiftID       `db:"id"`
+	StartTime     time.Time     `db:"start_time"`
+	EndTime       time.Time     `db:"end_time"`
+	Capacity      int           `db:"capacity"`
+	Team          *TeamID       `db:"team_id"`
+	BiddingClosed bool          `db:"bidding_closed"`
+	Requirements  []Requirement `db:"-"`
+}
+
+// Requirement reserves a number of places on a shift for workers with
+// a skill.
+type Requirement struct {
+	Skill string `db:"skill"`
+	Count int    `db:"count"`
 }
 
 func ShiftFromAPI(s *api.Shift) *Shift {
@@ -20,20 +37,37 @@ func ShiftFromAPI(s *api.Shift) *Shift {
 	if s.Id != nil {
 		id = *s.Id
 	}
-	return &Shift{
-		ID:        ShiftID(id),
-		StartTime: s.StartTime,
-		EndTime:   s.EndTime,
-		Capacity:  int(s.Capacity),
+	shift := &Shift{
+		ID:           ShiftID(id),
+		StartTime:    s.StartTime,
+		EndTime:      s.EndTime,
+		Capacity:     int(s.Capacity),
+		Team:         teamFromAPI(s.TeamId),
+		Requirements: []Requirement{},
 	}
+	if s.Requirements != nil {
+		for _, r := range *s.Requirements {
+			shift.Requirements = append(shift.Requirements,
+				Requirement{Skill: strings.TrimSpace(r.Skill), Count: int(r.Count)})
+		}
+	}
+	return shift
 }
 
 func ShiftToAPI(s *Shift) *api.Shift {
 	id := int64(s.ID)
+	reqs := []api.ShiftRequirement{}
+	for _, r := range s.Requirements {
+		reqs = append(reqs, api.ShiftRequirement{Skill: r.Skill, Count: int32(r.Count)})
+	}
+	closed := s.BiddingClosed
 	return &api.Shift{
-		Id:        &id,
-		StartTime: s.StartTime,
-		EndTime:   s.EndTime,
-		Capacity:  int32(s.Capacity),
+		Id:            &id,
+		StartTime:     s.StartTime,
+		EndTime:       s.EndTime,
+		Capacity:      int32(s.Capacity),
+		TeamId:        teamToAPI(s.Team),
+		Requirements:  &reqs,
+		BiddingClosed: &closed,
 	}
 }
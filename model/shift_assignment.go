@@ -1,6 +1,22 @@
 package model
 
+import "skybluetrades.net/work-planning-demo/api"
+
 type ShiftAssignment struct {
 	Worker WorkerID `db:"worker_id"`
 	Shift  ShiftID  `db:"shift_id"`
 }
+
+func ShiftAssignmentFromAPI(a *api.ShiftAssignment) *ShiftAssignment {
+	return &ShiftAssignment{
+		Worker: WorkerID(a.WorkerId),
+		Shift:  ShiftID(a.ShiftId),
+	}
+}
+
+func ShiftAssignmentToAPI(a *ShiftAssignment) *api.ShiftAssignment {
+	return &api.ShiftAssignment{
+		WorkerId: int64(a.Worker),
+		ShiftId:  int64(a.Shift),
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type ShiftPreferenceID int64
+
+// PreferenceWeight measures how much a worker wants to work a shift,
+// from "strongly avoid" (negative) to "strongly prefer" (positive).
+type PreferenceWeight int
+
+const (
+	StronglyAvoid  PreferenceWeight = -2
+	Avoid          PreferenceWeight = -1
+	Neutral        PreferenceWeight = 0
+	Prefer         PreferenceWeight = 1
+	StronglyPrefer PreferenceWeight = 2
+)
+
+// ShiftPreference is a worker's preference either for a single shift
+// (if Shift is set) or for a recurring pattern of shifts matching a
+// weekday, a start hour, or both.
+type ShiftPreference struct {
+	ID        ShiftPreferenceID `db:"id"`
+	Worker    WorkerID          `db:"worker_id"`
+	Shift     *ShiftID          `db:"shift_id"`
+	Weekday   *time.Weekday     `db:"weekday"`
+	StartHour *int              `db:"start_hour"`
+	Weight    PreferenceWeight  `db:"weight"`
+}
+
+// Matches checks whether a preference applies to a shift.
+func (p *ShiftPreference) Matches(s *Shift) bool {
+	if p.Shift != nil {
+		return *p.Shift == s.ID
+	}
+	if p.Weekday == nil && p.StartHour == nil {
+		return false
+	}
+	if p.Weekday != nil && *p.Weekday != s.StartTime.Weekday() {
+		return false
+	}
+	if p.StartHour != nil && *p.StartHour != s.StartTime.Hour() {
+		return false
+	}
+	return true
+}
+
+// Specificity ranks preferences for choosing between several that
+// match the same shift: single shift preferences beat recurring
+// preferences, and recurring preferences matching both weekday and
+// start hour beat those matching only one of them.
+func (p *ShiftPreference) Specificity() int {
+	if p.Shift != nil {
+		return 3
+	}
+	n := 0
+	if p.Weekday != nil {
+		n++
+	}
+	if p.StartHour != nil {
+		n++
+	}
+	return n
+}
+
+var weightsFromAPI = map[api.PreferenceWeight]PreferenceWeight{
+	api.StronglyAvoid:  StronglyAvoid,
+	api.Avoid:          Avoid,
+	api.Neutral:        Neutral,
+	api.Prefer:         Prefer,
+	api.StronglyPrefer: StronglyPrefer,
+}
+
+var weekdaysFromAPI = map[api.Weekday]time.Weekday{
+	api.Sunday:    time.Sunday,
+	api.Monday:    time.Monday,
+	api.Tuesday:   time.Tuesday,
+	api.Wednesday: time.Wednesday,
+	api.Thursday:  time.Thursday,
+	api.Friday:    time.Friday,
+	api.Saturday:  time.Saturday,
+}
+
+func WeekdayFromAPI(d api.Weekday) time.Weekday {
+	return weekdaysFromAPI[d]
+}
+
+func WeekdayToAPI(d time.Weekday) api.Weekday {
+	for k, v := range weekdaysFromAPI {
+		if v == d {
+			return k
+		}
+	}
+	return ""
+}
+
+func ShiftPreferenceFromAPI(p *api.ShiftPreference) *ShiftPreference {
+	pref := &ShiftPreference{Weight: weightsFromAPI[p.Weight]}
+	if p.Id != nil {
+		pref.ID = ShiftPreferenceID(*p.Id)
+	}
+	if p.WorkerId != nil {
+		pref.Worker = WorkerID(*p.WorkerId)
+	}
+	if p.ShiftId != nil {
+		shift := ShiftID(*p.ShiftId)
+		pref.Shift = &shift
+	}
+	if p.Weekday != nil {
+		weekday := WeekdayFromAPI(*p.Weekday)
+		pref.Weekday = &weekday
+	}
+	if p.StartHour != nil {
+		hour := int(*p.StartHour)
+		pref.StartHour = &hour
+	}
+	return pref
+}
+
+func ShiftPreferenceToAPI(p *ShiftPreference) *api.ShiftPreference {
+	id := int64(p.ID)
+	worker := int64(p.Worker)
+	pref := &api.ShiftPreference{
+		Id:       &id,
+		WorkerId: &worker,
+	}
+	for k, v := range weightsFromAPI {
+		if v == p.Weight {
+			pref.Weight = k
+		}
+	}
+	if p.Shift != nil {
+		shift := int64(*p.Shift)
+		pref.ShiftId = &shift
+	}
+	if p.Weekday != nil {
+		weekday := WeekdayToAPI(*p.Weekday)
+		pref.Weekday = &weekday
+	}
+	if p.StartHour != nil {
+		hour := int32(*p.StartHour)
+		pref.StartHour = &hour
+	}
+	return pref
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type ShiftTemplateID int64
+
+// Weekdays is a set of days of the week, stored as a bit mask with
+// bit 0 for Sunday, following time.Weekday.
+type Weekdays uint8
+
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, d := range days {
+		w |= 1 << d
+	}
+	return w
+}
+
+// Has checks whether a day is in a set of weekdays.
+func (w Weekdays) Has(d time.Weekday) bool {
+	return w&(1<<d) != 0
+}
+
+// Days lists the days in a set of weekdays, starting from Sunday.
+func (w Weekdays) Days() []time.Weekday {
+	days := []time.Weekday{}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if w.Has(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// ShiftTemplate describes a recurring shift, which happens on each of
+// a set of weekdays between two hours of the day. If EndHour isn't
+// after StartHour, the shift runs overnight into the next day. The
+// shifts belong to the template's Team, if it has one.
+type ShiftTemplate struct {
+	ID        ShiftTemplateID `db:"id"`
+	Name      string          `db:"name"`
+	Weekdays  Weekdays        `db:"weekdays"`
+	StartHour int             `db:"start_hour"`
+	EndHour   int             `db:"end_hour"`
+	Capacity  int             `db:"capacity"`
+	Team      *TeamID         `db:"team_id"`
+}
+
+// Shift makes the shift for a template starting on a given day, which
+// should be a midnight. The day's weekday isn't checked.
+func (t *ShiftTemplate) Shift(day time.Time) *Shift {
+	start := day.Add(time.Duration(t.StartHour) * time.Hour)
+	end := day.Add(time.Duration(t.EndHour) * time.Hour)
+	if t.EndHour <= t.StartHour {
+		end = end.AddDate(0, 0, 1)
+	}
+	return &Shift{StartTime: start, EndTime: end, Capacity: t.Capacity, Team: t.Team}
+}
+
+func ShiftTemplateFromAPI(t *api.ShiftTemplate) *ShiftTemplate {
+	template := &ShiftTemplate{
+		StartHour: int(t.StartHour),
+		EndHour:   int(t.EndHour),
+		Capacity:  int(t.Capacity),
+		Team:      teamFromAPI(t.TeamId),
+	}
+	if t.Id != nil {
+		template.ID = ShiftTemplateID(*t.Id)
+	}
+	if t.Name != nil {
+		template.Name = *t.Name
+	}
+	for _, d := range t.Weekdays {
+		template.Weekdays |= NewWeekdays(WeekdayFromAPI(d))
+	}
+	return template
+}
+
+func ShiftTemplateToAPI(t *ShiftTemplate) *api.ShiftTemplate {
+	id := int64(t.ID)
+	name := t.Name
+	template := &api.ShiftTemplate{
+		Id:        &id,
+		Name:      &name,
+		Weekdays:  []api.Weekday{},
+		StartHour: int32(t.StartHour),
+		EndHour:   int32(t.EndHour),
+		Capacity:  int32(t.Capacity),
+		TeamId:    teamToAPI(t.Team),
+	}
+	for _, d := range t.Weekdays.Days() {
+		template.Weekdays = append(template.Weekdays, WeekdayToAPI(d))
+	}
+	return template
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type SwapID int64
+
+// SwapStatus is the state of a shift swap. Offers start off open; a
+// taker's proposal moves them to proposed; the offering worker can
+// then accept, which either completes the swap straight away or waits
+// for admin approval, depending on configuration.
+type SwapStatus string
+
+const (
+	SwapOpen      SwapStatus = "open"
+	SwapProposed  SwapStatus = "proposed"
+	SwapAccepted  SwapStatus = "accepted"
+	SwapCompleted SwapStatus = "completed"
+	SwapRejected  SwapStatus = "rejected"
+	SwapCancelled SwapStatus = "cancelled"
+)
+
+// Swap is an offer by a worker to give up one of their shifts. Another
+// worker (the taker) can propose to take it, either outright or in
+// exchange for one of their own shifts (the counter shift).
+type Swap struct {
+	ID           SwapID     `db:"id"`
+	Offerer      WorkerID   `db:"offerer_id"`
+	Shift        ShiftID    `db:"shift_id"`
+	Taker        *WorkerID  `db:"taker_id"`
+	CounterShift *ShiftID   `db:"counter_shift_id"`
+	Status       SwapStatus `db:"status"`
+}
+
+func SwapToAPI(s *Swap) *api.Swap {
+	swap := &api.Swap{
+		Id:        int64(s.ID),
+		OffererId: int64(s.Offerer),
+		ShiftId:   int64(s.Shift),
+		Status:    api.SwapStatus(s.Status),
+	}
+	if s.Taker != nil {
+		taker := int64(*s.Taker)
+		swap.TakerId = &taker
+	}
+	if s.CounterShift != nil {
+		counter := int64(*s.CounterShift)
+		swap.CounterShiftId = &counter
+	}
+	return swap
+}
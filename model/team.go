@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type TeamID int64
+
+// Team is a site or group of workers that owns its own shifts. Workers
+// can belong to any number of teams, and can be admins of some of
+// them: team admins manage the team's shifts and members, while
+// organisation admins (with the worker IsAdmin flag) manage everything.
+type Team struct {
+	ID   TeamID `db:"id"`
+	Name string `db:"name"`
+}
+
+// Membership records that a worker belongs to a team, and whether
+// they're one of its admins.
+type Membership struct {
+	Team    TeamID `db:"team_id"`
+	IsAdmin bool   `db:"is_admin"`
+}
+
+// InTeam checks whether a worker can work shifts for a team. Every
+// worker can work shifts that don't belong to a team (a nil team).
+func (w *Worker) InTeam(team *TeamID) bool {
+	if team == nil {
+		return true
+	}
+	for _, m := range w.Teams {
+		if m.Team == *team {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminOf checks whether a worker can manage a team. Organisation
+// admins can manage every team, and are the only ones who can manage
+// shifts that don't belong to a team.
+func (w *Worker) AdminOf(team *TeamID) bool {
+	if w.IsAdmin {
+		return true
+	}
+	if team == nil {
+		return false
+	}
+	for _, m := range w.Teams {
+		if m.Team == *team && m.IsAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminTeams lists the teams a worker is an admin of.
+func (w *Worker) AdminTeams() []TeamID {
+	teams := []TeamID{}
+	for _, m := range w.Teams {
+		if m.IsAdmin {
+			teams = append(teams, m.Team)
+		}
+	}
+	return teams
+}
+
+func TeamFromAPI(t *api.Team) *Team {
+	var id int64
+	if t.Id != nil {
+		id = *t.Id
+	}
+	return &Team{ID: TeamID(id), Name: strings.TrimSpace(t.Name)}
+}
+
+func TeamToAPI(t *Team) *api.Team {
+	id := int64(t.ID)
+	return &api.Team{Id: &id, Name: t.Name}
+}
+
+// Convert an optional team ID between API and model forms.
+func teamFromAPI(id *api.TeamId) *TeamID {
+	if id == nil {
+		return nil
+	}
+	team := TeamID(*id)
+	return &team
+}
+
+func teamToAPI(team *TeamID) *api.TeamId {
+	if team == nil {
+		return nil
+	}
+	id := int64(*team)
+	return &id
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"skybluetrades.net/work-planning-demo/api"
+)
+
+type TimeOffID int64
+
+// TimeOffStatus is the state of a time off request in the approval
+// workflow: requests start off pending, and are then approved or
+// rejected by an admin.
+type TimeOffStatus string
+
+const (
+	TimeOffPending  TimeOffStatus = "pending"
+	TimeOffApproved TimeOffStatus = "approved"
+	TimeOffRejected TimeOffStatus = "rejected"
+)
+
+// TimeOff is a worker's request for leave between two times, which
+// may cover whole days or parts of days.
+type TimeOff struct {
+	ID        TimeOffID     `db:"id"`
+	Worker    WorkerID      `db:"worker_id"`
+	StartTime time.Time     `db:"start_time"`
+	EndTime   time.Time     `db:"end_time"`
+	Reason    string        `db:"reason"`
+	Status    TimeOffStatus `db:"status"`
+}
+
+// Overlaps checks whether any part of a shift falls within a period of
+// time off.
+func (t *TimeOff) Overlaps(s *Shift) bool {
+	return s.StartTime.Before(t.EndTime) && s.EndTime.After(t.StartTime)
+}
+
+func TimeOffFromAPI(t *api.TimeOff) *TimeOff {
+	timeOff := &TimeOff{
+		StartTime: t.StartTime,
+		EndTime:   t.EndTime,
+		Status:    TimeOffPending,
+	}
+	if t.Id != nil {
+		timeOff.ID = TimeOffID(*t.Id)
+	}
+	if t.WorkerId != nil {
+		timeOff.Worker = WorkerID(*t.WorkerId)
+	}
+	if t.Reason != nil {
+		timeOff.Reason = *t.Reason
+	}
+	if t.Status != nil {
+		timeOff.Status = TimeOffStatus(*t.Status)
+	}
+	return timeOff
+}
+
+func TimeOffToAPI(t *TimeOff) *api.TimeOff {
+	id := int64(t.ID)
+	worker := int64(t.Worker)
+	status := api.TimeOffStatus(t.Status)
+	return &api.TimeOff{
+		Id:        &id,
+		WorkerId:  &worker,
+		StartTime: t.StartTime,
+		EndTime:   t.EndTime,
+		Reason:    &t.Reason,
+		Status:    &status,
+	}
+}
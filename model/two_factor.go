@@ -0,0 +1,14 @@
+package model
+
+// TwoFactor holds a worker's TOTP two-factor authentication settings.
+// The secret is saved when the worker starts enrolling, and two-factor
+// authentication is only enabled once they've confirmed it with a
+// code. LastStep is the TOTP time step of the last code used, so that
+// codes can't be used twice, and the recovery codes are stored hashed.
+type TwoFactor struct {
+	Worker        WorkerID `db:"worker_id"`
+	Secret        string   `db:"secret"`
+	Enabled       bool     `db:"enabled"`
+	LastStep      int64    `db:"last_step"`
+	RecoveryCodes []string `db:"-"`
+}
@@ -3,17 +3,27 @@ package model
 import (
 	"strings"
 
+	"golang.org/x/exp/slices"
+
 	"skybluetrades.net/work-planning-demo/api"
 )
 
 type WorkerID int64
 
 type Worker struct {
-	ID       WorkerID `db:"id"`
-	Email    string   `db:"email"`
-	Name     string   `db:"name"`
-	IsAdmin  bool     `db:"is_admin"`
-	Password string   `db:"password"`
+	ID       WorkerID     `db:"id"`
+	Email    string       `db:"email"`
+	Name     string       `db:"name"`
+	IsAdmin  bool         `db:"is_admin"`
+	Password string       `db:"password"`
+	Skills   []string     `db:"-"`
+	Teams    []Membership `db:"-"`
+	Roles    []RoleID     `db:"-"`
+}
+
+// HasSkill checks whether a worker has a skill.
+func (w *Worker) HasSkill(skill string) bool {
+	return slices.Contains(w.Skills, skill)
 }
 
 func WorkerFromAPI(w *api.Worker) *Worker {
@@ -21,20 +31,61 @@ func WorkerFromAPI(w *api.Worker) *Worker {
 	if w.Id != nil {
 		id = *w.Id
 	}
-	return &Worker{
+	worker := &Worker{
 		ID:      WorkerID(id),
 		Email:   strings.TrimSpace(w.Email),
 		Name:    strings.TrimSpace(w.Name),
 		IsAdmin: w.IsAdmin,
+		Skills:  []string{},
+		Teams:   []Membership{},
+		Roles:   []RoleID{},
+	}
+	if w.Password != nil {
+		worker.Password = *w.Password
+	}
+	if w.Skills != nil {
+		for _, sk := range *w.Skills {
+			if sk = strings.TrimSpace(sk); !slices.Contains(worker.Skills, sk) {
+				worker.Skills = append(worker.Skills, sk)
+			}
+		}
 	}
+	if w.Teams != nil {
+		for _, m := range *w.Teams {
+			team := TeamID(m.TeamId)
+			if !worker.InTeam(&team) {
+				worker.Teams = append(worker.Teams, Membership{Team: team, IsAdmin: m.IsAdmin})
+			}
+		}
+	}
+	if w.Roles != nil {
+		for _, r := range *w.Roles {
+			if !slices.Contains(worker.Roles, RoleID(r)) {
+				worker.Roles = append(worker.Roles, RoleID(r))
+			}
+		}
+	}
+	return worker
 }
 
 func WorkerToAPI(worker *Worker) *api.Worker {
 	id := int64(worker.ID)
+	skills := append([]string{}, worker.Skills...)
+	teams := []api.TeamMembership{}
+	for _, m := range worker.Teams {
+		teams = append(teams, api.TeamMembership{TeamId: int64(m.Team), IsAdmin: m.IsAdmin})
+	}
+	roles := []api.RoleId{}
+	for _, r := range worker.Roles {
+		roles = append(roles, int64(r))
+	}
 	return &api.Worker{
 		Id:      &id,
 		Email:   worker.Email,
 		Name:    worker.Name,
 		IsAdmin: worker.IsAdmin,
+		Skills:  &skills,
+		Teams:   &teams,
+		Roles:   &roles,
 	}
 }
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Replacement candidates for a shift at the start of the week must
+// have had enough rest after their shifts on the day before.
+func TestAbsenceContext(t *testing.T) {
+	e, done, db := contextSetup(t)
+	defer done()
+	ctx := context.Background()
+
+	absent := &model.Worker{Email: "absent@test.com", Name: "absent", Password: testPassword}
+	db.CreateWorker(ctx, absent)
+	monday := &model.Shift{
+		StartTime: time.Date(2023, 5, 8, 8, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2023, 5, 8, 16, 0, 0, 0, time.UTC),
+		Capacity:  1,
+	}
+	db.CreateShift(ctx, monday)
+	err := db.CreateShiftAssignment(ctx, absent.ID, monday.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker, _ := db.GetWorkerByEmail(ctx, "worker@test.com")
+	auth := loginAs(e, adminEmail, adminPassword)
+
+	result := e.POST(fmt.Sprintf("/worker/%d/absence", absent.ID)).
+		WithHeader("Authorization", auth).
+		WithJSON(map[string]string{"start_date": "2023-05-08", "end_date": "2023-05-08"}).
+		Expect().Status(http.StatusOK).JSON().Object()
+	vacancies := result.Value("vacancies").Array()
+	vacancies.Length().IsEqual(1)
+	candidates := vacancies.Value(0).Object().Value("candidates").Array()
+	candidates.Length().IsEqual(1)
+	candidates.Value(0).Object().Value("worker_id").IsEqual(worker.ID)
+}
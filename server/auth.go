@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -10,14 +13,16 @@ import (
 	"github.com/deepmap/oapi-codegen/pkg/middleware"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/golang-jwt/jwt"
+	"golang.org/x/exp/slices"
 	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
 )
 
 // NewAuthenticator creates a new JWT-based authenticator to use in
 // the Echo middleware. This checks a bearer token on each request,
 // and if that's successful, it extracts token claims into the Echo
 // request context for later use.
-func NewAuthenticator(cfg *Config) openapi3filter.AuthenticationFunc {
+func NewAuthenticator(cfg *Config, db store.Store) openapi3filter.AuthenticationFunc {
 	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
 		// Not sure when this might go wrong, but the oapi-codegen
 		// examples check it!
@@ -37,44 +42,116 @@ func NewAuthenticator(cfg *Config) openapi3filter.AuthenticationFunc {
 			return err
 		}
 
-		// Check claims for particular scopes from the OpenAPI spec (here,
-		// we just have an "admin" scope).
+		// Access tokens stop working when the login they came from is
+		// revoked, rather than only when they expire. The validator's
+		// context isn't the request's, so it doesn't get cancelled.
+		rctx := input.RequestValidationInput.Request.Context()
+		revoked, err := db.RefreshTokenFamilyRevoked(rctx, claims.Family)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return errors.New("token revoked")
+		}
+
+		// Check claims for the permissions named by the scopes from the
+		// OpenAPI spec. Operations with the "team" scope also let in
+		// admins of any team who don't have the permissions: handlers
+		// then check which teams they can manage.
+		teamScope := slices.Contains(input.Scopes, "team")
+		orgWide := true
 		for _, scope := range input.Scopes {
-			if scope == "admin" && !claims.IsAdmin {
-				return errors.New("admin action not permitted")
+			if scope == "team" || claims.HasPermission(model.Permission(scope)) {
+				continue
+			}
+			if teamScope && len(claims.AdminTeams) > 0 {
+				orgWide = false
+				continue
 			}
+			return fmt.Errorf("%s permission not granted", scope)
 		}
 
-		// Save the access token claims for later processing.
+		// Save the access token claims for later processing, along with
+		// whether the user can act for every team.
 		echoCtx := middleware.GetEchoContext(ctx)
 		echoCtx.Set("claims", claims)
+		echoCtx.Set("org_wide", orgWide)
 
 		return nil
 	}
 }
 
+// Token audiences keep the different kinds of token apart, so that
+// one can't be used in place of another.
+const (
+	accessAudience    = "access"
+	refreshAudience   = "refresh"
+	challengeAudience = "2fa-challenge"
+)
+
+// ChallengeLease is the time (in seconds) that workers have to
+// complete two-factor authentication after giving their password.
+const ChallengeLease = 300
+
 // JWTClaim is the claim structure for JWT access tokens.
 type JWTClaim struct {
-	ID      model.WorkerID `json:"id"`
-	IsAdmin bool           `json:"is_admin"`
+	ID          model.WorkerID     `json:"id"`
+	IsAdmin     bool               `json:"is_admin"`
+	AdminTeams  []model.TeamID     `json:"admin_teams,omitempty"`
+	Permissions []model.Permission `json:"permissions,omitempty"`
+	Family      string             `json:"family"`
 	jwt.StandardClaims
 }
 
-// JWTRefreshClaim is the claim structure for JWT refresh tokens.
+// HasPermission checks whether an access token carries a permission.
+// Organisation admins have every permission.
+func (c *JWTClaim) HasPermission(perm model.Permission) bool {
+	return c.IsAdmin || slices.Contains(c.Permissions, perm)
+}
+
+// JWTRefreshClaim is the claim structure for JWT refresh tokens. The
+// standard token ID claim identifies the token's record in the store.
 type JWTRefreshClaim struct {
 	ID model.WorkerID `json:"id"`
 	jwt.StandardClaims
 }
 
+// JWTChallengeClaim is the claim structure for the JWT challenge
+// tokens given out by the first step of a login with two-factor
+// authentication.
+type JWTChallengeClaim struct {
+	ID model.WorkerID `json:"id"`
+	jwt.StandardClaims
+}
+
+// NewTokenID creates a random ID for a refresh token or a token
+// family.
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // GenerateTokens creates access and refresh tokens for a given
-// Worker.
-func GenerateTokens(worker *model.Worker, cfg *Config) (string, string, error) {
-	// Create the access claims for the worker. This stores the user ID
-	// and whether the user is an admin.
+// Worker, with the permissions they get from their roles. The refresh
+// token record gives the refresh token's ID and expiry time, and the
+// token family that both tokens belong to.
+func GenerateTokens(worker *model.Worker, permissions []model.Permission,
+	refresh *model.RefreshToken, cfg *Config) (string, string, error) {
+	// Create the access claims for the worker. This stores the user ID,
+	// whether the user is an admin, which teams they're an admin of,
+	// their permissions, and the token family, so that the token can be
+	// revoked.
 	claims := &JWTClaim{
-		ID:      worker.ID,
-		IsAdmin: worker.IsAdmin,
+		ID:          worker.ID,
+		IsAdmin:     worker.IsAdmin,
+		AdminTeams:  worker.AdminTeams(),
+		Permissions: permissions,
+		Family:      refresh.Family,
 		StandardClaims: jwt.StandardClaims{
+			Audience:  accessAudience,
 			ExpiresAt: time.Now().Add(time.Duration(cfg.AccessTokenLease) * time.Second).Unix(),
 		},
 	}
@@ -87,14 +164,16 @@ func GenerateTokens(worker *model.Worker, cfg *Config) (string, string, error) {
 	}
 
 	// Create the refresh claims for the worker. This stores just the
-	// user ID. When a token refresh is requested, the user is looked up
-	// in the database, and tokens are regenerated — this means that any
-	// changes to user characteristics that affect access token claims
-	// become active at the next token refresh.
+	// user ID and the token ID. When a token refresh is requested, the
+	// user is looked up in the database, and tokens are regenerated —
+	// this means that any changes to user characteristics that affect
+	// access token claims become active at the next token refresh.
 	refreshClaims := &JWTRefreshClaim{
 		ID: worker.ID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Duration(cfg.RefreshTokenLease) * time.Second).Unix(),
+			Id:        refresh.ID,
+			Audience:  refreshAudience,
+			ExpiresAt: refresh.ExpiresAt.Unix(),
 		},
 	}
 
@@ -108,6 +187,20 @@ func GenerateTokens(worker *model.Worker, cfg *Config) (string, string, error) {
 	return t, rt, nil
 }
 
+// GenerateChallengeToken creates a challenge token for the second
+// step of a login with two-factor authentication.
+func GenerateChallengeToken(worker *model.Worker, cfg *Config) (string, error) {
+	claims := &JWTChallengeClaim{
+		ID: worker.ID,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  challengeAudience,
+			ExpiresAt: time.Now().Add(ChallengeLease * time.Second).Unix(),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.AuthKey))
+}
+
 // Extract JWT from request headers.
 func parseAuthHeader(r *http.Request) (string, error) {
 	// Get the Authorization header, which should be of the form "Bearer
@@ -151,11 +244,14 @@ func ValidateToken(signedToken string, secretKey string) (*JWTClaim, error) {
 	if !ok {
 		return nil, errors.New("couldn't parse claims")
 	}
+	if !claims.VerifyAudience(accessAudience, true) {
+		return nil, errors.New("not an access token")
+	}
 
 	return claims, nil
 }
 
-// ValidateRefreshToken validates an access token.
+// ValidateRefreshToken validates a refresh token.
 func ValidateRefreshToken(signedToken string, secretKey string) (*JWTRefreshClaim, error) {
 	// Parse the refresh token claims.
 	token, err := jwt.ParseWithClaims(
@@ -180,5 +276,34 @@ func ValidateRefreshToken(signedToken string, secretKey string) (*JWTRefreshClai
 	if !ok {
 		return nil, errors.New("couldn't parse refresh claims")
 	}
+	if !claims.VerifyAudience(refreshAudience, true) {
+		return nil, errors.New("not a refresh token")
+	}
+	return claims, nil
+}
+
+// ValidateChallengeToken validates a two-factor challenge token.
+func ValidateChallengeToken(signedToken string, secretKey string) (*JWTChallengeClaim, error) {
+	token, err := jwt.ParseWithClaims(
+		signedToken,
+		&JWTChallengeClaim{},
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(secretKey), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token invalid")
+	}
+
+	claims, ok := token.Claims.(*JWTChallengeClaim)
+	if !ok {
+		return nil, errors.New("couldn't parse challenge claims")
+	}
+	if !claims.VerifyAudience(challengeAudience, true) {
+		return nil, errors.New("not a challenge token")
+	}
 	return claims, nil
 }
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/mailer"
 	"skybluetrades.net/work-planning-demo/mocks"
 	"skybluetrades.net/work-planning-demo/model"
 	"skybluetrades.net/work-planning-demo/store"
@@ -31,35 +34,56 @@ func setupTestData(db *mocks.Store) {
 		Password: string(bcryptPassword),
 	}
 	db.
-		On("Authenticate", adminEmail, adminPassword).Return(&worker1, nil).
-		On("Authenticate", mock.Anything, mock.Anything).Return(nil, store.ErrWorkerNotFound)
+		On("Authenticate", mock.Anything, adminEmail, adminPassword).Return(&worker1, nil).
+		On("Authenticate", mock.Anything, mock.Anything, mock.Anything).Return(nil, store.ErrWorkerNotFound)
 	db.
-		On("GetWorkerById", model.WorkerID(1)).Return(&worker1, nil).
-		On("GetWorkerById", mock.Anything).Return(nil, store.ErrWorkerNotFound)
+		On("GetWorkerById", mock.Anything, model.WorkerID(1)).Return(&worker1, nil).
+		On("GetWorkerById", mock.Anything, mock.Anything).Return(nil, store.ErrWorkerNotFound)
 	db.
-		On("GetWorkers").Return([]*model.Worker{&worker1}, nil)
+		On("GetWorkers", mock.Anything, (*model.TeamID)(nil)).Return([]*model.Worker{&worker1}, nil)
+	db.
+		On("CreateRefreshToken", mock.Anything, mock.Anything).Return(nil).
+		On("UseRefreshToken", mock.Anything, mock.Anything).Return(&model.RefreshToken{Worker: 1}, nil).
+		On("RefreshTokenFamilyRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	db.
+		On("GetTwoFactor", mock.Anything, mock.Anything).Return(nil, store.ErrTwoFactorNotFound)
+	db.
+		On("GetLoginAttempts", mock.Anything, mock.Anything).Return(&model.LoginAttempts{}, nil).
+		On("RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.LoginAttempts{Failures: 1}, nil).
+		On("ClearLoginAttempts", mock.Anything, mock.Anything).Return(nil)
 }
 
 func serverSetup(t *testing.T, testData bool) (*httpexpect.Expect, *httptest.Server) {
-	// Test server configuration: we're going to inject a mock store
-	// layer, and we set the token leases very short (1 second for the
-	// access token and 60 seconds for the refresh token), so that we
-	// can test token expiry handling in a reasonable time.
-	cfg := &Config{
-		DevMode:           true,
-		StoreURL:          "mock",
-		Port:              8080,
-		AccessTokenLease:  1,  // second
-		RefreshTokenLease: 60, // second
-		AuthKey:           "test-key",
-	}
+	// We're going to inject a mock store layer.
 	db := &mocks.Store{}
-	serv := NewServer(cfg, db, nil)
-
 	if testData {
 		setupTestData(db)
 	}
 
+	return storeServerSetup(t, testConfig(), db, mailer.NewMemoryMailer())
+}
+
+// Test server configuration: we set the token leases very short (1
+// second for the access token and 60 seconds for the refresh token),
+// so that we can test token expiry handling in a reasonable time.
+func testConfig() *Config {
+	return &Config{
+		DevMode:            true,
+		StoreURL:           "mock",
+		Port:               8080,
+		AccessTokenLease:   1,  // second
+		RefreshTokenLease:  60, // second
+		AuthKey:            "test-key",
+		InvitationLease:    60,
+		PasswordResetLease: 60,
+	}
+}
+
+func storeServerSetup(t *testing.T, cfg *Config,
+	db store.Store, mail mailer.Mailer) (*httpexpect.Expect, *httptest.Server) {
+	serv := NewServer(cfg, db, mail, nil)
+
 	srv := httptest.NewServer(serv)
 	e := httpexpect.New(t, srv.URL)
 
@@ -124,6 +148,341 @@ func TestAuthMiddleware(t *testing.T) {
 		Expect().Status(http.StatusOK).JSON().Array()
 }
 
+func TestAuthTokenRevocation(t *testing.T) {
+	// Token rotation and revocation need a store that remembers the
+	// tokens it has issued, so this uses the in-memory store.
+	db, _ := store.NewMemoryStore()
+	db.CreateWorker(context.Background(), &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	})
+	e, srv := storeServerSetup(t, testConfig(), db, mailer.NewMemoryMailer())
+	defer srv.Close()
+
+	refresh := func(refreshToken string, status int) *httpexpect.Object {
+		return e.POST("/auth/refresh_token").
+			WithJSON(api.CredentialsRefresh{RefreshToken: refreshToken}).
+			Expect().Status(status).JSON().Object()
+	}
+	getWorkers := func(accessToken string, status int) {
+		e.GET("/worker").WithHeader("Authorization", "Bearer "+accessToken).
+			Expect().Status(status)
+	}
+
+	// Refreshing rotates the refresh token.
+	_, refreshToken := getTokens(e)
+	ro := refresh(refreshToken, http.StatusOK)
+	newAccessToken := ro.Value("access_token").String().Raw()
+	newRefreshToken := ro.Value("refresh_token").String().Raw()
+	getWorkers(newAccessToken, http.StatusOK)
+
+	// Reusing the old refresh token revokes the whole token family.
+	refresh(refreshToken, http.StatusForbidden).
+		HasValue("message", "Failed to refresh access token (token reused)")
+	refresh(newRefreshToken, http.StatusForbidden).
+		HasValue("message", "Failed to refresh access token (token revoked)")
+	getWorkers(newAccessToken, http.StatusForbidden)
+
+	// Logging out revokes the tokens from that login only.
+	accessToken1, refreshToken1 := getTokens(e)
+	accessToken2, _ := getTokens(e)
+	e.POST("/auth/logout").WithHeader("Authorization", "Bearer "+accessToken1).
+		Expect().Status(http.StatusNoContent)
+	getWorkers(accessToken1, http.StatusForbidden)
+	refresh(refreshToken1, http.StatusForbidden)
+	getWorkers(accessToken2, http.StatusOK)
+
+	// Forcing a worker to log out revokes all their tokens.
+	accessToken3, _ := getTokens(e)
+	e.POST("/worker/1/logout").WithHeader("Authorization", "Bearer "+accessToken2).
+		Expect().Status(http.StatusNoContent)
+	getWorkers(accessToken2, http.StatusForbidden)
+	getWorkers(accessToken3, http.StatusForbidden)
+}
+
+func TestAuthPasswordFlows(t *testing.T) {
+	db, _ := store.NewMemoryStore()
+	db.CreateWorker(context.Background(), &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	})
+	newWorker := &model.Worker{Email: "new@test.com", Name: "new"}
+	db.CreateWorker(context.Background(), newWorker)
+	mail := mailer.NewMemoryMailer()
+	e, srv := storeServerSetup(t, testConfig(), db, mail)
+	defer srv.Close()
+
+	tokenRe := regexp.MustCompile(`token=([0-9a-f]+)`)
+	lastToken := func() string {
+		msgs := mail.Messages()
+		match := tokenRe.FindStringSubmatch(msgs[len(msgs)-1].Body)
+		if match == nil {
+			t.Fatal("no token in mail message")
+		}
+		return match[1]
+	}
+	login := func(password string, status int) *httpexpect.Object {
+		return e.POST("/auth/login").
+			WithJSON(api.Login{Email: newWorker.Email, Password: password}).
+			Expect().Status(status).JSON().Object()
+	}
+
+	// Workers without a password can't log in until they accept an
+	// invitation, and invitations can only be used once.
+	login("", http.StatusForbidden)
+	adminToken, _ := getTokens(e)
+	e.POST("/worker/2/invitation").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().Status(http.StatusNoContent)
+	invitation := lastToken()
+	e.POST("/auth/invitation").
+		WithJSON(api.PasswordTokenRedemption{Token: invitation, Password: "first"}).
+		Expect().Status(http.StatusNoContent)
+	e.POST("/auth/invitation").
+		WithJSON(api.PasswordTokenRedemption{Token: invitation, Password: "again"}).
+		Expect().Status(http.StatusForbidden)
+	e.POST("/worker/2/invitation").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().Status(http.StatusBadRequest)
+
+	// Changing password needs the current password.
+	accessToken := login("first", http.StatusOK).Value("access_token").String().Raw()
+	e.PUT("/me/password").WithHeader("Authorization", "Bearer "+accessToken).
+		WithJSON(api.PasswordChange{CurrentPassword: "wrong", NewPassword: "second"}).
+		Expect().Status(http.StatusForbidden)
+	tokens := login("first", http.StatusOK)
+	e.PUT("/me/password").WithHeader("Authorization", "Bearer "+accessToken).
+		WithJSON(api.PasswordChange{CurrentPassword: "first", NewPassword: "second"}).
+		Expect().Status(http.StatusNoContent)
+	login("first", http.StatusForbidden)
+
+	// Changing password logs the worker out everywhere, so that anyone
+	// else who had the old password loses access.
+	e.GET("/me").WithHeader("Authorization", "Bearer "+accessToken).
+		Expect().Status(http.StatusForbidden)
+	e.POST("/auth/refresh_token").
+		WithJSON(api.CredentialsRefresh{RefreshToken: tokens.Value("refresh_token").String().Raw()}).
+		Expect().Status(http.StatusForbidden)
+	accessToken = login("second", http.StatusOK).Value("access_token").String().Raw()
+
+	// Unknown email addresses don't get reset tokens, and resetting a
+	// password logs the worker out.
+	sent := len(mail.Messages())
+	e.POST("/auth/password_reset").WithJSON(api.PasswordResetRequest{Email: "nobody@test.com"}).
+		Expect().Status(http.StatusNoContent)
+	if len(mail.Messages()) != sent {
+		t.Error("password reset mail sent for unknown email address")
+	}
+	e.POST("/auth/password_reset").WithJSON(api.PasswordResetRequest{Email: newWorker.Email}).
+		Expect().Status(http.StatusNoContent)
+	e.POST("/auth/invitation").
+		WithJSON(api.PasswordTokenRedemption{Token: lastToken(), Password: "third"}).
+		Expect().Status(http.StatusForbidden)
+	e.POST("/auth/password_reset/confirm").
+		WithJSON(api.PasswordTokenRedemption{Token: lastToken(), Password: "third"}).
+		Expect().Status(http.StatusNoContent)
+	e.GET("/me").WithHeader("Authorization", "Bearer "+accessToken).
+		Expect().Status(http.StatusForbidden)
+	login("third", http.StatusOK)
+}
+
+func TestAuthTwoFactor(t *testing.T) {
+	// Two-factor authentication is required for admins here, and the
+	// worker is an admin.
+	db, _ := store.NewMemoryStore()
+	db.CreateWorker(context.Background(), &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	})
+	cfg := testConfig()
+	cfg.AccessTokenLease = 60
+	cfg.AdminTwoFactor = true
+	e, srv := storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	defer srv.Close()
+
+	code := func(secret string, offset int64) string {
+		c, _ := totpCode(secret, time.Now().Unix()/totpPeriod+offset)
+		return c
+	}
+	login := func() *httpexpect.Object {
+		return e.POST("/auth/login").
+			WithJSON(api.Login{Email: adminEmail, Password: adminPassword}).
+			Expect().Status(http.StatusAccepted).JSON().Object()
+	}
+	complete := func(challenge string, code string, status int) *httpexpect.Object {
+		return e.POST("/auth/2fa").
+			WithJSON(api.TwoFactorLogin{ChallengeToken: challenge, Code: code}).
+			Expect().Status(status).JSON().Object()
+	}
+
+	// The admin hasn't enrolled, so logging in enrols them, and the
+	// challenge token can't be used as an access token.
+	challenge := login()
+	token := challenge.Value("challenge_token").String().Raw()
+	secret := challenge.Path("$.enrolment.secret").String().Raw()
+	e.GET("/me").WithHeader("Authorization", "Bearer "+token).
+		Expect().Status(http.StatusForbidden)
+	complete(token, "000000", http.StatusForbidden)
+	creds := complete(token, code(secret, 0), http.StatusOK)
+	recovery := creds.Value("recovery_codes").Array()
+	recovery.Length().IsEqual(recoveryCodeCount)
+	accessToken := creds.Value("access_token").String().Raw()
+
+	// Each code can only be used once, whether it's a TOTP code or a
+	// recovery code.
+	token = login().Value("challenge_token").String().Raw()
+	complete(token, code(secret, 0), http.StatusForbidden)
+	complete(token, code(secret, 1), http.StatusOK)
+	recoveryCode := recovery.Value(0).String().Raw()
+	token = login().Value("challenge_token").String().Raw()
+	complete(token, recoveryCode, http.StatusOK)
+	complete(token, recoveryCode, http.StatusForbidden)
+
+	e.GET("/me/2fa").WithHeader("Authorization", "Bearer "+accessToken).
+		Expect().Status(http.StatusOK).JSON().Object().
+		HasValue("enabled", true).HasValue("recovery_codes_left", recoveryCodeCount-1)
+
+	// Admins can't turn two-factor authentication off when it's
+	// required.
+	e.DELETE("/me/2fa").WithQuery("code", recovery.Value(1).String().Raw()).
+		WithHeader("Authorization", "Bearer "+accessToken).
+		Expect().Status(http.StatusBadRequest)
+}
+
+func TestAuthLockout(t *testing.T) {
+	db, _ := store.NewMemoryStore()
+	db.CreateWorker(context.Background(), &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	})
+	db.CreateWorker(context.Background(), &model.Worker{Email: "locked@test.com", Name: "locked", Password: "secret"})
+	cfg := testConfig()
+	cfg.AccessTokenLease = 60
+	cfg.LoginLockoutThreshold = 3
+	cfg.LoginIPLockoutThreshold = 5
+	cfg.LoginLockoutDuration = 60
+	e, srv := storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	defer srv.Close()
+
+	adminToken, _ := getTokens(e)
+	login := func(email, password string) *httpexpect.Response {
+		return e.POST("/auth/login").
+			WithJSON(api.Login{Email: email, Password: password}).Expect()
+	}
+
+	// Too many failures lock the account, even for the right password,
+	// until an admin unlocks it.
+	for i := 0; i < 3; i++ {
+		login("locked@test.com", "wrong").Status(http.StatusForbidden)
+	}
+	login("locked@test.com", "secret").Status(http.StatusTooManyRequests).
+		Header("Retry-After").NotEmpty()
+	lockouts := e.GET("/lockouts").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().Status(http.StatusOK).JSON().Array()
+	lockouts.Length().Equal(1)
+	lockouts.Element(0).Object().ValueEqual("kind", "account").ValueEqual("worker_id", 2)
+	e.DELETE("/worker/2/lockout").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().Status(http.StatusNoContent)
+	login("locked@test.com", "secret").Status(http.StatusOK)
+
+	// Failures from one client IP address across accounts lock the
+	// address.
+	login("nobody@test.com", "wrong").Status(http.StatusForbidden)
+	login("nobody@test.com", "wrong").Status(http.StatusForbidden)
+	login(adminEmail, adminPassword).Status(http.StatusTooManyRequests)
+	lockouts = e.GET("/lockouts").WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().Status(http.StatusOK).JSON().Array()
+	lockouts.Length().Equal(2)
+	lockouts.Element(0).Object().ValueEqual("kind", "ip").NotContainsKey("worker_id")
+}
+
+func TestAuthLoginIPBackoff(t *testing.T) {
+	setup := func(trustedProxies string) (*httpexpect.Expect, *httptest.Server) {
+		db, _ := store.NewMemoryStore()
+		db.CreateWorker(context.Background(), &model.Worker{
+			Email:    adminEmail,
+			Name:     adminName,
+			IsAdmin:  true,
+			Password: adminPassword,
+		})
+		cfg := testConfig()
+		cfg.LoginLockoutThreshold = 3
+		cfg.LoginIPLockoutThreshold = 5
+		cfg.LoginLockoutDuration = 60
+		cfg.LoginBackoffBase = 60
+		cfg.TrustedProxies = trustedProxies
+		return storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	}
+	login := func(e *httpexpect.Expect, email, password, ip string) *httpexpect.Response {
+		return e.POST("/auth/login").WithHeader("X-Forwarded-For", ip).
+			WithJSON(api.Login{Email: email, Password: password}).Expect()
+	}
+
+	// Without trusted proxies, client IP address headers are ignored,
+	// so a client can't dodge the backoff for its address by changing
+	// them.
+	e, srv := setup("")
+	defer srv.Close()
+	login(e, "nobody@test.com", "wrong", "10.0.0.1").Status(http.StatusForbidden)
+	login(e, adminEmail, adminPassword, "10.0.0.2").Status(http.StatusTooManyRequests).
+		Header("Retry-After").NotEmpty()
+
+	// Behind a trusted proxy, the client IP address comes from the
+	// header it adds.
+	e, srv = setup("127.0.0.1/32, ::1/128")
+	defer srv.Close()
+	login(e, "nobody@test.com", "wrong", "10.0.0.1").Status(http.StatusForbidden)
+	login(e, adminEmail, adminPassword, "10.0.0.1").Status(http.StatusTooManyRequests)
+	login(e, adminEmail, adminPassword, "10.0.0.2").Status(http.StatusOK)
+}
+
+func TestAuthWorkerPasswords(t *testing.T) {
+	db, _ := store.NewMemoryStore()
+	db.CreateWorker(context.Background(), &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	})
+	cfg := testConfig()
+	cfg.AccessTokenLease = 60
+	e, srv := storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	defer srv.Close()
+
+	adminToken, _ := getTokens(e)
+	login := func(password string, status int) {
+		e.POST("/auth/login").
+			WithJSON(api.Login{Email: "new@test.com", Password: password}).
+			Expect().Status(status)
+	}
+	password := func(p string) *string { return &p }
+
+	// Passwords given to new workers are kept.
+	id := e.POST("/worker").WithHeader("Authorization", "Bearer "+adminToken).
+		WithJSON(api.Worker{Email: "new@test.com", Name: "new", Password: password("first")}).
+		Expect().Status(http.StatusOK).JSON().Object().Value("id").Number().Raw()
+	workerId := int64(id)
+	login("first", http.StatusOK)
+
+	// Updating a worker without a password keeps their password, and
+	// updating with one changes it.
+	e.PUT("/worker").WithHeader("Authorization", "Bearer "+adminToken).
+		WithJSON(api.Worker{Id: &workerId, Email: "new@test.com", Name: "renamed"}).
+		Expect().Status(http.StatusOK).JSON().Object().HasValue("name", "renamed")
+	login("first", http.StatusOK)
+	e.PUT("/worker").WithHeader("Authorization", "Bearer "+adminToken).
+		WithJSON(api.Worker{Id: &workerId, Email: "new@test.com", Name: "renamed", Password: password("second")}).
+		Expect().Status(http.StatusOK)
+	login("first", http.StatusForbidden)
+	login("second", http.StatusOK)
+}
+
 // Helper to get API tokens for tests.
 func getTokens(e *httpexpect.Expect) (string, string) {
 	login := &api.Login{Email: adminEmail, Password: adminPassword}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Record a sick call and find replacements for a worker
+// (POST /worker/{worker-id}/absence)
+func (s *server) CreateWorkerAbsence(ctx echo.Context,
+	workerId api.WorkerIdParam, params api.CreateWorkerAbsenceParams) error {
+	var req api.Absence
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for absence")
+	}
+
+	// The absence is dealt with against a consistent view of the
+	// schedule, so that the replacements found are still free when
+	// they're assigned.
+	applied := params.Apply != nil && *params.Apply
+	var removed []model.ShiftAssignment
+	var replacements []domain.Replacement
+	err = s.inTx(ctx, func(s *server) error {
+		worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+		if errors.Is(err, store.ErrWorkerNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Unknown worker ID")
+		}
+		if err != nil {
+			return err
+		}
+		err = s.checkWorkerAdmin(ctx, worker)
+		if err != nil {
+			return err
+		}
+		admin, err := s.currentWorker(ctx)
+		if err != nil {
+			return err
+		}
+
+		// The end date is inclusive, as for schedule generation.
+		start := req.StartDate.Time
+		end := req.EndDate.Time.AddDate(0, 0, 1)
+		if !start.Before(end) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Bad date range for absence")
+		}
+
+		// Candidates are ranked by their hours for the whole week of each
+		// vacated shift, so the problem covers whole weeks. Assignments
+		// either side of those weeks are part of the problem's context,
+		// so a candidate who worked late the day before a vacated shift
+		// is still checked against the rest rules.
+		lastDay := req.EndDate.Time
+		weekStart, _ := store.SpanRange(&start, store.WeekSpan)
+		_, weekEnd := store.SpanRange(&lastDay, store.WeekSpan)
+		problem, err := s.schedulingProblem(ctx.Request().Context(), weekStart, weekEnd)
+		if err != nil {
+			return err
+		}
+
+		// Take the absent worker's assignments in the date range out of the
+		// problem, and treat the absence as time off so that they aren't a
+		// candidate for their own shifts. Team admins only deal with the
+		// shifts of the teams they manage.
+		absence := &model.TimeOff{
+			Worker:    worker.ID,
+			StartTime: start,
+			EndTime:   end,
+			Status:    model.TimeOffApproved,
+		}
+		shifts := map[model.ShiftID]*model.Shift{}
+		for _, sh := range problem.Shifts {
+			shifts[sh.ID] = sh
+		}
+		removed = []model.ShiftAssignment{}
+		vacated := []*model.Shift{}
+		kept := []model.ShiftAssignment{}
+		for _, a := range problem.Assignments {
+			sh, ok := shifts[a.Shift]
+			if ok && a.Worker == worker.ID && absence.Overlaps(sh) &&
+				(orgWide(ctx) || admin.AdminOf(sh.Team)) {
+				removed = append(removed, a)
+				vacated = append(vacated, sh)
+			} else {
+				kept = append(kept, a)
+			}
+		}
+		problem.Assignments = kept
+		problem.TimeOff = append(problem.TimeOff, absence)
+
+		replacements = domain.FindReplacements(problem, vacated)
+
+		// The absent worker's assignments are always removed, but the
+		// replacements are only assigned if we're asked to: both happen in
+		// a single store update.
+		add := []model.ShiftAssignment{}
+		if applied {
+			for _, r := range replacements {
+				if r.Worker != nil {
+					add = append(add, model.ShiftAssignment{Worker: *r.Worker, Shift: r.Shift.ID})
+				}
+			}
+		}
+		err = s.db.ReplaceShiftAssignments(ctx.Request().Context(), removed, add)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusConflict,
+				"Failed to update shift assignments: "+err.Error()).SetInternal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result := api.AbsenceResult{
+		Removed:   []api.ShiftAssignment{},
+		Vacancies: []api.Vacancy{},
+		Applied:   applied,
+	}
+	for _, a := range removed {
+		result.Removed = append(result.Removed, *model.ShiftAssignmentToAPI(&a))
+	}
+	for _, r := range replacements {
+		v := api.Vacancy{
+			Shift:      *model.ShiftToAPI(r.Shift),
+			Candidates: []api.ReplacementCandidate{},
+		}
+		for _, c := range r.Candidates {
+			v.Candidates = append(v.Candidates, api.ReplacementCandidate{
+				WorkerId: int64(c.Worker),
+				Hours:    c.Hours,
+			})
+		}
+		if r.Worker != nil {
+			replacement := int64(*r.Worker)
+			v.Replacement = &replacement
+		}
+		result.Vacancies = append(result.Vacancies, v)
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
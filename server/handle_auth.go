@@ -1,11 +1,15 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
 )
 
 // (POST /auth/login)
@@ -16,63 +20,158 @@ func (s *server) PostLogin(ctx echo.Context) error {
 		return sendError(ctx, http.StatusBadRequest, "Invalid format for login")
 	}
 
+	// Repeated failed logins for an account or from a client IP
+	// address have to wait before trying again.
+	err = s.checkLoginAllowed(ctx, login.Email)
+	if err != nil {
+		return err
+	}
+
 	// Authenticate user.
-	worker, err := s.db.Authenticate(login.Email, login.Password)
+	worker, err := s.db.Authenticate(ctx.Request().Context(), login.Email, login.Password)
 	if err != nil || worker == nil {
+		err = s.recordLoginFailure(ctx, login.Email)
+		if err != nil {
+			return err
+		}
 		return sendError(ctx, http.StatusForbidden, "Invalid login credentials")
 	}
-
-	// Generate JWTs and return credentials.
-	accessToken, refreshToken, err := GenerateTokens(worker, s.config)
+	err = s.db.ClearLoginAttempts(ctx.Request().Context(), accountLoginKey(login.Email))
 	if err != nil {
 		return err
 	}
-	creds := api.Credentials{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+
+	// Workers with two-factor authentication turned on, and admins if
+	// it's required for them, have to give a code before getting
+	// tokens.
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil && !errors.Is(err, store.ErrTwoFactorNotFound) {
+		return err
+	}
+	enabled := err == nil && tf.Enabled
+	if enabled || (s.config.AdminTwoFactor && worker.IsAdmin) {
+		return s.sendTwoFactorChallenge(ctx, worker, enabled)
+	}
+
+	creds, err := s.newLoginTokens(ctx.Request().Context(), worker)
+	if err != nil {
+		return err
 	}
 	return ctx.JSON(http.StatusOK, creds)
 }
 
 // (POST /auth/logout)
 func (s *server) PostLogout(ctx echo.Context) error {
-	// TODO: MAINTAIN LIST OF RETIRED ACCESS AND REFRESH TOKENS TO FORCE
-	// RE-LOGIN? I NEVER KNOW WHAT TO DO WITH THIS...
+	// Revoking the token family from the current user's login stops
+	// both the access token and the refresh token from working.
+	claims := ctx.Get("claims").(*JWTClaim)
+	err := s.db.RevokeRefreshTokenFamily(ctx.Request().Context(), claims.Family)
+	if err != nil {
+		return err
+	}
+
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 // (POST /auth/refresh_token)
 func (s *server) PostRefreshToken(ctx echo.Context) error {
-	fmt.Println("===> 1")
 	var refresh api.CredentialsRefresh
 	err := ctx.Bind(&refresh)
 	if err != nil {
 		return sendError(ctx, http.StatusBadRequest, "Invalid format for for token refresh")
 	}
-	fmt.Println("===> 2: refresh = ", refresh)
 
 	// Decode and validate the refresh token from the request.
 	claims, err := ValidateRefreshToken(refresh.RefreshToken, s.config.AuthKey)
-	fmt.Println("===> 3: claims = ", claims, "   err = ", err, "   claims.ID = ", claims.ID)
 	if err != nil {
 		return sendError(ctx, http.StatusForbidden, "Failed to refresh access token")
 	}
 
+	// Refresh tokens can only be used once. If a token that's already
+	// been used turns up again, someone else has a copy of it, and
+	// there's no way to tell which copy is the real one, so every
+	// token from the same login is revoked.
+	token, err := s.db.UseRefreshToken(ctx.Request().Context(), claims.Id)
+	if err != nil || token.Worker != claims.ID {
+		return sendError(ctx, http.StatusForbidden, "Failed to refresh access token (unknown token)")
+	}
+	if token.Revoked {
+		return sendError(ctx, http.StatusForbidden, "Failed to refresh access token (token revoked)")
+	}
+	if token.Used {
+		err = s.db.RevokeRefreshTokenFamily(ctx.Request().Context(), token.Family)
+		if err != nil {
+			return err
+		}
+		return sendError(ctx, http.StatusForbidden, "Failed to refresh access token (token reused)")
+	}
+
 	// Do a database lookup for the worker.
-	worker, err := s.db.GetWorkerById(claims.ID)
-	fmt.Println("===> 4: worker = ", worker, "   err = ", err)
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), claims.ID)
 	if err != nil {
 		return sendError(ctx, http.StatusForbidden, "Failed to refresh access token (unknown user)")
 	}
 
 	// Generate and send new tokens.
-	accessToken, refreshToken, err := GenerateTokens(worker, s.config)
+	creds, err := s.issueTokens(ctx.Request().Context(), worker, token.Family)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, creds)
+}
+
+// Log a worker out everywhere, revoking all their tokens
+// (POST /worker/{worker-id}/logout)
+func (s *server) LogoutWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
+	err := s.db.RevokeWorkerRefreshTokens(ctx.Request().Context(), model.WorkerID(workerId))
+	if errors.Is(err, store.ErrWorkerNotFound) {
+		return sendError(ctx, http.StatusNotFound, "Unknown worker ID")
+	}
 	if err != nil {
 		return err
 	}
-	creds := api.Credentials{
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Issue tokens for a new login, which starts a new token family.
+func (s *server) newLoginTokens(ctx context.Context, worker *model.Worker) (*api.Credentials, error) {
+	family, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, worker, family)
+}
+
+// Record a new refresh token in a token family, and make credentials
+// from it and a new access token.
+func (s *server) issueTokens(ctx context.Context, worker *model.Worker, family string) (*api.Credentials, error) {
+	permissions, err := s.workerPermissions(ctx, worker)
+	if err != nil {
+		return nil, err
+	}
+	id, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+	token := &model.RefreshToken{
+		ID:        id,
+		Family:    family,
+		Worker:    worker.ID,
+		ExpiresAt: time.Now().Add(time.Duration(s.config.RefreshTokenLease) * time.Second),
+	}
+	err = s.db.CreateRefreshToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := GenerateTokens(worker, permissions, token, s.config)
+	if err != nil {
+		return nil, err
+	}
+	creds := &api.Credentials{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}
-	return ctx.JSON(http.StatusOK, creds)
+	return creds, nil
 }
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get shift bids and waitlist places for current user
+// (GET /me/bids)
+func (s *server) GetMeBids(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sendBids(ctx, &worker.ID, nil)
+}
+
+// Withdraw a shift bid or leave a waitlist
+// (DELETE /me/bids/{bid-id})
+func (s *server) DeleteMeBid(ctx echo.Context, bidId api.BidIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	bid, err := s.db.GetBidById(ctx.Request().Context(), model.BidID(bidId))
+	if err != nil || bid.Worker != worker.ID {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift bid ID")
+	}
+	if bid.Status == model.BidAwarded {
+		return sendError(ctx, http.StatusBadRequest, "Shift bid has already been awarded")
+	}
+
+	err = s.db.DeleteBidById(ctx.Request().Context(), bid.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get bids and waitlist for a shift
+// (GET /shift/{shift-id}/bids)
+func (s *server) GetShiftBids(ctx echo.Context, shiftId api.ShiftIdParam) error {
+	shift, err := s.db.GetShiftById(ctx.Request().Context(), model.ShiftID(shiftId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift ID")
+	}
+	err = s.checkTeamAdmin(ctx, shift.Team)
+	if err != nil {
+		return err
+	}
+
+	return s.sendBids(ctx, nil, &shift.ID)
+}
+
+// Bid for a shift, or join its waitlist if it's full
+// (POST /shift/{shift-id}/bids)
+func (s *server) CreateShiftBid(ctx echo.Context, shiftId api.ShiftIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	bid := &model.Bid{Worker: worker.ID, Shift: model.ShiftID(shiftId)}
+	err = s.db.CreateBid(ctx.Request().Context(), bid)
+	if errors.Is(err, store.ErrShiftNotFound) {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift ID")
+	}
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to create shift bid: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.BidToAPI(bid))
+}
+
+// Close bidding for a shift and assign places to bidders
+// (POST /shift/{shift-id}/bids/resolve)
+func (s *server) ResolveShiftBids(ctx echo.Context,
+	shiftId api.ShiftIdParam, params api.ResolveShiftBidsParams) error {
+	policy := domain.BidFirstCome
+	if params.Policy != nil {
+		policy = domain.BidPolicy(*params.Policy)
+	}
+
+	// The bids are ranked and resolved against a consistent view of
+	// the schedule. A bid withdrawn in the meantime is a conflict, since
+	// the places can no longer be given out in the order decided.
+	err := s.inTx(ctx, func(s *server) error {
+		shift, err := s.db.GetShiftById(ctx.Request().Context(), model.ShiftID(shiftId))
+		if errors.Is(err, store.ErrShiftNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Unknown shift ID")
+		}
+		if err != nil {
+			return err
+		}
+		err = s.checkTeamAdmin(ctx, shift.Team)
+		if err != nil {
+			return err
+		}
+
+		bids, err := s.db.GetBids(ctx.Request().Context(), nil, &shift.ID)
+		if err != nil {
+			return err
+		}
+		pending := []*model.Bid{}
+		for _, b := range bids {
+			if b.Status == model.BidPending {
+				pending = append(pending, b)
+			}
+		}
+
+		// Policies based on hours look at the whole week of the shift.
+		weekStart, weekEnd := store.SpanRange(&shift.StartTime, store.WeekSpan)
+		problem, err := s.schedulingProblem(ctx.Request().Context(), weekStart, weekEnd)
+		if err != nil {
+			return err
+		}
+		ranked, err := domain.RankBids(problem, shift, pending, policy)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid bid policy").SetInternal(err)
+		}
+
+		order := make([]model.BidID, len(ranked))
+		for i, b := range ranked {
+			order[i] = b.ID
+		}
+		err = s.db.ResolveBids(ctx.Request().Context(), shift.ID, order)
+		if errors.Is(err, store.ErrBidNotFound) {
+			return echo.NewHTTPError(http.StatusConflict,
+				"Shift bids changed while being resolved").SetInternal(err)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	id := model.ShiftID(shiftId)
+	return s.sendBids(ctx, nil, &id)
+}
+
+func (s *server) sendBids(ctx echo.Context,
+	workerId *model.WorkerID, shiftId *model.ShiftID) error {
+	bids, err := s.db.GetBids(ctx.Request().Context(), workerId, shiftId)
+	if err != nil {
+		return err
+	}
+
+	result := []api.Bid{}
+	for _, b := range bids {
+		result = append(result, *model.BidToAPI(b))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Unlock a worker's account after too many failed logins
+// (DELETE /worker/{worker-id}/lockout)
+func (s *server) UnlockWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown worker ID")
+	}
+
+	err = s.db.ClearLoginAttempts(ctx.Request().Context(), accountLoginKey(worker.Email))
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get audit records of login lockouts, newest first
+// (GET /lockouts)
+func (s *server) GetLockouts(ctx echo.Context) error {
+	lockouts, err := s.db.GetLockouts(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []api.Lockout{}
+	for _, l := range lockouts {
+		result = append(result, *model.LockoutToAPI(l))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Failed logins are tracked separately for the account being logged
+// into and for the client IP address, so that guessing passwords for
+// one account and trying a few passwords for many accounts are both
+// slowed down.
+func accountLoginKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check that a login for an account is allowed now, returning an error
+// response with a Retry-After header if the account or the client IP
+// address is locked, or if either of them hasn't waited long enough
+// since its last failed login.
+func (s *server) checkLoginAllowed(ctx echo.Context, email string) error {
+	now := time.Now()
+	account, err := s.db.GetLoginAttempts(ctx.Request().Context(), accountLoginKey(email))
+	if err != nil {
+		return err
+	}
+	ip, err := s.db.GetLoginAttempts(ctx.Request().Context(), ipLoginKey(ctx.RealIP()))
+	if err != nil {
+		return err
+	}
+
+	until := s.loginRetryTime(account, account.Failures, now)
+	if ipUntil := s.loginRetryTime(ip, s.ipBackoffFailures(ip.Failures), now); ipUntil.After(until) {
+		until = ipUntil
+	}
+	if !until.After(now) {
+		return nil
+	}
+
+	wait := int(math.Ceil(until.Sub(now).Seconds()))
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(wait))
+	return echo.NewHTTPError(http.StatusTooManyRequests, "Too many failed logins")
+}
+
+// The time when logins for an account or IP address can next be tried:
+// the end of its lockout if it's locked, or the end of its backoff
+// after a number of failures.
+func (s *server) loginRetryTime(attempts *model.LoginAttempts, failures int, now time.Time) time.Time {
+	if attempts.Locked(now) {
+		return *attempts.LockedUntil
+	}
+	if failures > 0 {
+		return attempts.LastFailure.Add(s.loginBackoff(failures))
+	}
+	return time.Time{}
+}
+
+// Failures from an IP address may be for many accounts, for example
+// from workers behind the same NAT, so the IP address backs off more
+// slowly than an account does. The failures are scaled by the ratio of
+// the lockout thresholds, so that both back off by the same amount as
+// they get close to being locked.
+func (s *server) ipBackoffFailures(failures int) int {
+	account := s.config.LoginLockoutThreshold
+	ip := s.config.LoginIPLockoutThreshold
+	if account <= 0 || ip <= 0 || failures <= 0 {
+		return failures
+	}
+	return (failures*account + ip - 1) / ip
+}
+
+// The wait after a number of failed logins doubles with each failure,
+// up to the lockout duration.
+func (s *server) loginBackoff(failures int) time.Duration {
+	base := time.Duration(s.config.LoginBackoffBase) * time.Second
+	limit := time.Duration(s.config.LoginLockoutDuration) * time.Second
+	wait := base
+	for i := 1; i < failures && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return wait
+}
+
+// Record a failed login for an account and the client IP address,
+// locking either of them if they've reached their threshold. A zero
+// threshold turns off locking.
+func (s *server) recordLoginFailure(ctx echo.Context, email string) error {
+	window := time.Duration(s.config.LoginLockoutDuration) * time.Second
+
+	account, err := s.db.RecordLoginFailure(ctx.Request().Context(), accountLoginKey(email), window)
+	if err != nil {
+		return err
+	}
+	threshold := s.config.LoginLockoutThreshold
+	if threshold > 0 && account.Failures >= threshold {
+		err = s.lockLogin(ctx.Request().Context(), model.LockoutAccount, strings.TrimSpace(email), account)
+		if err != nil {
+			return err
+		}
+	}
+
+	ip, err := s.db.RecordLoginFailure(ctx.Request().Context(), ipLoginKey(ctx.RealIP()), window)
+	if err != nil {
+		return err
+	}
+	threshold = s.config.LoginIPLockoutThreshold
+	if threshold > 0 && ip.Failures >= threshold {
+		return s.lockLogin(ctx.Request().Context(), model.LockoutIP, ctx.RealIP(), ip)
+	}
+	return nil
+}
+
+// Lock logins for an account or IP address, and keep an audit record
+// of the lockout.
+func (s *server) lockLogin(ctx context.Context, kind model.LockoutKind,
+	subject string, attempts *model.LoginAttempts) error {
+	now := time.Now()
+	until := now.Add(time.Duration(s.config.LoginLockoutDuration) * time.Second)
+	err := s.db.LockLogin(ctx, attempts.Key, until)
+	if err != nil {
+		return err
+	}
+
+	lockout := &model.Lockout{
+		Kind:        kind,
+		Subject:     subject,
+		Failures:    attempts.Failures,
+		LockedAt:    now,
+		LockedUntil: until,
+	}
+	if kind == model.LockoutAccount {
+		if worker, err := s.db.GetWorkerByEmail(ctx, subject); err == nil {
+			lockout.Worker = &worker.ID
+		}
+	}
+	return s.db.CreateLockout(ctx, lockout)
+}
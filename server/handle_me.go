@@ -40,7 +40,7 @@ func (s *server) GetMeSchedule(ctx echo.Context, params api.GetMeScheduleParams)
 	if params.Span != nil && *params.Span == "day" {
 		span = store.DaySpan
 	}
-	shifts, err := s.db.GetShifts(date, span, &worker.ID)
+	shifts, err := s.db.GetShifts(ctx.Request().Context(), date, span, &worker.ID, nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/mailer"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Change current user's password, logging them out everywhere
+// (PUT /me/password)
+func (s *server) ChangeMePassword(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var change api.PasswordChange
+	err = ctx.Bind(&change)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for password change")
+	}
+	if change.NewPassword == "" {
+		return sendError(ctx, http.StatusBadRequest, "Missing new password")
+	}
+
+	_, err = s.db.Authenticate(ctx.Request().Context(), worker.Email, change.CurrentPassword)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Current password is wrong")
+	}
+	err = s.db.SetWorkerPassword(ctx.Request().Context(), worker.ID, change.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// Whoever might have been using the old password is logged out,
+	// along with the worker's other logins, including this one.
+	err = s.db.RevokeWorkerRefreshTokens(ctx.Request().Context(), worker.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Email a worker an invitation link to set their initial password
+// (POST /worker/{worker-id}/invitation)
+func (s *server) InviteWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown worker ID")
+	}
+	if worker.Password != "" {
+		return sendError(ctx, http.StatusBadRequest, "Worker has already set a password")
+	}
+
+	err = s.sendPasswordToken(ctx.Request().Context(), worker, model.PasswordInvitation)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Accept an invitation, setting an initial password
+// (POST /auth/invitation)
+func (s *server) AcceptInvitation(ctx echo.Context) error {
+	_, err := s.redeemPasswordToken(ctx, model.PasswordInvitation)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Request a password reset token by email
+// (POST /auth/password_reset)
+func (s *server) RequestPasswordReset(ctx echo.Context) error {
+	var req api.PasswordResetRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for password reset request")
+	}
+
+	// Unknown email addresses get the same response as known ones.
+	worker, err := s.db.GetWorkerByEmail(ctx.Request().Context(), strings.TrimSpace(req.Email))
+	if errors.Is(err, store.ErrUnknownWorkerEmail) {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.sendPasswordToken(ctx.Request().Context(), worker, model.PasswordReset)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Reset a password using a reset token, logging the worker out
+// everywhere
+// (POST /auth/password_reset/confirm)
+func (s *server) ConfirmPasswordReset(ctx echo.Context) error {
+	token, err := s.redeemPasswordToken(ctx, model.PasswordReset)
+	if err != nil {
+		return err
+	}
+
+	// Whoever might have been using the old password is logged out.
+	err = s.db.RevokeWorkerRefreshTokens(ctx.Request().Context(), token.Worker)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Create a password token for a worker and mail it to them as a link.
+func (s *server) sendPasswordToken(ctx context.Context, worker *model.Worker, purpose model.PasswordTokenPurpose) error {
+	secret, err := NewTokenID()
+	if err != nil {
+		return err
+	}
+	lease := s.config.PasswordResetLease
+	if purpose == model.PasswordInvitation {
+		lease = s.config.InvitationLease
+	}
+	token := &model.PasswordToken{
+		ID:        hashToken(secret),
+		Worker:    worker.ID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(time.Duration(lease) * time.Second),
+	}
+	err = s.db.CreatePasswordToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	msg := &mailer.Message{To: worker.Email}
+	expiry := token.ExpiresAt.Format(time.RFC1123)
+	switch purpose {
+	case model.PasswordInvitation:
+		msg.Subject = "Your work planning invitation"
+		msg.Body = fmt.Sprintf("Hello %s,\n\nYou've been invited to use work planning. "+
+			"Follow this link to set your password:\n\n%s/invitation?token=%s\n\n"+
+			"The link expires at %s.\n", worker.Name, s.config.AppURL, secret, expiry)
+	case model.PasswordReset:
+		msg.Subject = "Resetting your work planning password"
+		msg.Body = fmt.Sprintf("Hello %s,\n\nSomeone asked to reset your password. "+
+			"If it was you, follow this link to choose a new one:\n\n%s/password-reset?token=%s\n\n"+
+			"The link expires at %s. If you didn't ask for this, you can ignore this email.\n",
+			worker.Name, s.config.AppURL, secret, expiry)
+	}
+	return s.mail.Send(msg)
+}
+
+// Use a password token from the request body to set a worker's
+// password, returning an error response if the request or the token
+// are no good.
+func (s *server) redeemPasswordToken(ctx echo.Context,
+	purpose model.PasswordTokenPurpose) (*model.PasswordToken, error) {
+	var r api.PasswordTokenRedemption
+	err := ctx.Bind(&r)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid format for password token")
+	}
+	if r.Password == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Missing password")
+	}
+
+	token, err := s.db.UsePasswordToken(ctx.Request().Context(), hashToken(r.Token), purpose)
+	if errors.Is(err, store.ErrPasswordTokenInvalid) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Invalid or expired "+string(purpose)+" token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.SetWorkerPassword(ctx.Request().Context(), token.Worker, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Only hashes of password tokens and recovery codes are stored, so
+// that they can't be used by anyone who gets a look at the store.
+func hashToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Get shift preferences for current user
+// (GET /me/preferences)
+func (s *server) GetMePreferences(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPreferences(ctx, worker.ID)
+}
+
+// Create new shift preference for current user
+// (POST /me/preferences)
+func (s *server) CreateMePreference(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var p api.ShiftPreference
+	err = ctx.Bind(&p)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift preference")
+	}
+	err = checkShiftPreference(ctx, &p, false)
+	if err != nil {
+		return err
+	}
+
+	pref := model.ShiftPreferenceFromAPI(&p)
+	pref.Worker = worker.ID
+	err = s.db.CreateShiftPreference(ctx.Request().Context(), pref)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to create shift preference: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.ShiftPreferenceToAPI(pref))
+}
+
+// Update an existing shift preference for current user
+// (PUT /me/preferences)
+func (s *server) UpdateMePreference(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var p api.ShiftPreference
+	err = ctx.Bind(&p)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift preference")
+	}
+	err = checkShiftPreference(ctx, &p, true)
+	if err != nil {
+		return err
+	}
+	existing, err := s.db.GetShiftPreferenceById(ctx.Request().Context(), model.ShiftPreferenceID(*p.Id))
+	if err != nil || existing.Worker != worker.ID {
+		return sendError(ctx, http.StatusBadRequest, "Unknown shift preference ID")
+	}
+
+	pref := model.ShiftPreferenceFromAPI(&p)
+	pref.Worker = worker.ID
+	err = s.db.UpdateShiftPreference(ctx.Request().Context(), pref)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to update shift preference: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.ShiftPreferenceToAPI(pref))
+}
+
+// Get a single shift preference for current user
+// (GET /me/preferences/{preference-id})
+func (s *server) GetMePreference(ctx echo.Context, preferenceId api.PreferenceIdParam) error {
+	pref, err := s.currentWorkerPreference(ctx, preferenceId)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.ShiftPreferenceToAPI(pref))
+}
+
+// Delete a shift preference for current user
+// (DELETE /me/preferences/{preference-id})
+func (s *server) DeleteMePreference(ctx echo.Context, preferenceId api.PreferenceIdParam) error {
+	pref, err := s.currentWorkerPreference(ctx, preferenceId)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.DeleteShiftPreferenceById(ctx.Request().Context(), pref.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get shift preferences for a single worker
+// (GET /worker/{worker-id}/preferences)
+func (s *server) GetWorkerPreferences(ctx echo.Context, workerId api.WorkerIdParam) error {
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return err
+	}
+	err = s.checkWorkerAdmin(ctx, worker)
+	if err != nil {
+		return err
+	}
+
+	return s.sendPreferences(ctx, worker.ID)
+}
+
+// Look up one of the current user's shift preferences, sending a 404
+// response if it doesn't exist or belongs to someone else.
+func (s *server) currentWorkerPreference(ctx echo.Context,
+	preferenceId api.PreferenceIdParam) (*model.ShiftPreference, error) {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pref, err := s.db.GetShiftPreferenceById(ctx.Request().Context(), model.ShiftPreferenceID(preferenceId))
+	if err != nil || pref.Worker != worker.ID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Unknown shift preference ID")
+	}
+	return pref, nil
+}
+
+func (s *server) sendPreferences(ctx echo.Context, workerId model.WorkerID) error {
+	prefs, err := s.db.GetShiftPreferences(ctx.Request().Context(), &workerId)
+	if err != nil {
+		return err
+	}
+
+	// Convert the ShiftPreference models from the store into OpenAPI
+	// ShiftPreference schema objects for return.
+	ps := make([]*api.ShiftPreference, len(prefs))
+	for i, p := range prefs {
+		ps[i] = model.ShiftPreferenceToAPI(p)
+	}
+	return ctx.JSON(http.StatusOK, ps)
+}
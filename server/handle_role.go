@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/exp/slices"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get all roles
+// (GET /roles)
+func (s *server) GetRoles(ctx echo.Context) error {
+	roles, err := s.db.GetRoles(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []api.Role{}
+	for _, r := range roles {
+		result = append(result, *model.RoleToAPI(r))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Create new role
+// (POST /roles)
+func (s *server) CreateRole(ctx echo.Context) error {
+	var r api.Role
+	err := ctx.Bind(&r)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for role")
+	}
+
+	role := model.RoleFromAPI(&r)
+	err = checkRole(ctx, role)
+	if err != nil {
+		return err
+	}
+	err = s.db.CreateRole(ctx.Request().Context(), role)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.RoleToAPI(role))
+}
+
+// Update an existing role
+// (PUT /roles)
+func (s *server) UpdateRole(ctx echo.Context) error {
+	var r api.Role
+	err := ctx.Bind(&r)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for role")
+	}
+	if r.Id == nil {
+		return sendError(ctx, http.StatusBadRequest, "Missing role ID")
+	}
+
+	role := model.RoleFromAPI(&r)
+	err = checkRole(ctx, role)
+	if err != nil {
+		return err
+	}
+	err = s.db.UpdateRole(ctx.Request().Context(), role)
+	if errors.Is(err, store.ErrRoleNotFound) {
+		return sendError(ctx, http.StatusNotFound, "Unknown role ID")
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.RoleToAPI(role))
+}
+
+// Get a single role
+// (GET /roles/{role-id})
+func (s *server) GetRole(ctx echo.Context, roleId api.RoleIdParam) error {
+	role, err := s.db.GetRoleById(ctx.Request().Context(), model.RoleID(roleId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown role ID")
+	}
+
+	return ctx.JSON(http.StatusOK, model.RoleToAPI(role))
+}
+
+// Delete a role, taking it away from its workers
+// (DELETE /roles/{role-id})
+func (s *server) DeleteRole(ctx echo.Context, roleId api.RoleIdParam) error {
+	err := s.db.DeleteRoleById(ctx.Request().Context(), model.RoleID(roleId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown role ID")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func checkRole(ctx echo.Context, role *model.Role) error {
+	if role.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing name for role")
+	}
+	for _, p := range role.Permissions {
+		if !slices.Contains(model.AllPermissions, p) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown permission: "+string(p))
+		}
+	}
+	return nil
+}
+
+// Collect the permissions that a worker gets from their roles, for
+// their access token.
+func (s *server) workerPermissions(ctx context.Context, worker *model.Worker) ([]model.Permission, error) {
+	perms := []model.Permission{}
+	if len(worker.Roles) == 0 {
+		return perms, nil
+	}
+
+	roles, err := s.db.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		if !slices.Contains(worker.Roles, r.ID) {
+			continue
+		}
+		for _, p := range r.Permissions {
+			if !slices.Contains(perms, p) {
+				perms = append(perms, p)
+			}
+		}
+	}
+	return perms, nil
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get all versions of the scheduling rule set
+// (GET /rules)
+func (s *server) GetRuleSets(ctx echo.Context) error {
+	ruleSets, err := s.db.GetRuleSets(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []api.RuleSet{}
+	for _, rs := range ruleSets {
+		result = append(result, *model.RuleSetToAPI(rs))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Create a new version of the scheduling rule set
+// (POST /rules)
+func (s *server) CreateRuleSet(ctx echo.Context) error {
+	var rs api.RuleSet
+	err := ctx.Bind(&rs)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for rule set")
+	}
+
+	ruleSet := model.RuleSetFromAPI(&rs)
+	ruleSet.ID = 0
+	if rs.EffectiveFrom == nil {
+		y, m, d := time.Now().UTC().Date()
+		ruleSet.EffectiveFrom = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Check that the rules can be built before storing them, so that
+	// shift assignment never fails because of bad configuration.
+	_, err = domain.NewRuleSet(ruleSet.Rules)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid rule: "+err.Error())
+	}
+
+	err = s.db.CreateRuleSet(ctx.Request().Context(), ruleSet)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to create rule set: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.RuleSetToAPI(ruleSet))
+}
+
+// Get the scheduling rule set currently in effect
+// (GET /rules/current)
+func (s *server) GetCurrentRuleSet(ctx echo.Context) error {
+	ruleSet, err := s.db.GetRuleSetAt(ctx.Request().Context(), time.Now())
+	if err == store.ErrRuleSetNotFound {
+		ruleSet = &model.RuleSet{Rules: domain.DefaultRuleConfigs}
+	} else if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.RuleSetToAPI(ruleSet))
+}
+
+// Get a single version of the scheduling rule set
+// (GET /rules/{rule-set-id})
+func (s *server) GetRuleSet(ctx echo.Context, ruleSetId api.RuleSetIdParam) error {
+	ruleSet, err := s.db.GetRuleSetById(ctx.Request().Context(), model.RuleSetID(ruleSetId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown rule set ID")
+	}
+
+	return ctx.JSON(http.StatusOK, model.RuleSetToAPI(ruleSet))
+}
+
+// Delete a version of the scheduling rule set
+// (DELETE /rules/{rule-set-id})
+func (s *server) DeleteRuleSet(ctx echo.Context, ruleSetId api.RuleSetIdParam) error {
+	err := s.db.DeleteRuleSetById(ctx.Request().Context(), model.RuleSetID(ruleSetId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown rule set ID")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Generate a schedule for a range of dates
+// (POST /schedule/solve)
+func (s *server) SolveSchedule(ctx echo.Context, params api.SolveScheduleParams) error {
+	var req api.ScheduleRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for schedule request")
+	}
+
+	// The end date is inclusive, so the time range runs up to the start
+	// of the following day.
+	start := req.StartDate.Time
+	end := req.EndDate.Time.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return sendError(ctx, http.StatusBadRequest, "Bad date range for schedule")
+	}
+
+	solver, err := newSolver(&req)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	problem, err := s.schedulingProblem(ctx.Request().Context(), start, end)
+	if err != nil {
+		return err
+	}
+
+	solution, err := solver.Solve(problem)
+	if err != nil {
+		return err
+	}
+
+	// Only store the new assignments if we're asked to: by default, the
+	// proposal is just returned for inspection.
+	committed := false
+	if params.Commit != nil && *params.Commit {
+		err = s.db.CreateShiftAssignments(ctx.Request().Context(), solution.Assignments)
+		if err != nil {
+			return sendError(ctx, http.StatusConflict, "Failed to commit shift assignments: "+err.Error())
+		}
+		committed = true
+	}
+
+	// Convert the proposed ShiftAssignment models into OpenAPI
+	// ShiftAssignment schema objects for return.
+	as := make([]api.ShiftAssignment, len(solution.Assignments))
+	for i, a := range solution.Assignments {
+		as[i] = *model.ShiftAssignmentToAPI(&a)
+	}
+	proposal := api.ScheduleProposal{
+		Assignments: as,
+		Score:       solution.Score,
+		Committed:   committed,
+	}
+	if solution.History != nil {
+		proposal.History = &solution.History
+	}
+	return ctx.JSON(http.StatusOK, proposal)
+}
+
+// Create the solver requested for schedule generation, filling in
+// default values for any missing solver options.
+func newSolver(req *api.ScheduleRequest) (domain.Solver, error) {
+	solver := api.Greedy
+	if req.Solver != nil {
+		solver = *req.Solver
+	}
+
+	switch solver {
+	case api.Greedy:
+		return domain.GreedySolver{}, nil
+
+	case api.Annealing:
+		opts := api.AnnealingOptions{}
+		if req.Annealing != nil {
+			opts = *req.Annealing
+		}
+		annealing := domain.AnnealingSolver{
+			InitialTemperature: 100,
+			Iterations:         10000,
+			Seed:               time.Now().UnixNano(),
+		}
+		if opts.InitialTemperature != nil {
+			annealing.InitialTemperature = *opts.InitialTemperature
+		}
+		if opts.Iterations != nil {
+			annealing.Iterations = int(*opts.Iterations)
+		}
+		if opts.Seed != nil {
+			annealing.Seed = *opts.Seed
+		}
+		rate := 0.999
+		if opts.CoolingRate != nil {
+			rate = *opts.CoolingRate
+		}
+		annealing.Cooling = domain.GeometricCooling(rate)
+		if opts.Cooling != nil && *opts.Cooling == api.Linear {
+			annealing.Cooling = domain.LinearCooling
+		}
+		return annealing, nil
+
+	case api.Genetic:
+		opts := api.GeneticOptions{}
+		if req.Genetic != nil {
+			opts = *req.Genetic
+		}
+		genetic := domain.GeneticSolver{
+			PopulationSize: 50,
+			Generations:    200,
+			Seed:           time.Now().UnixNano(),
+		}
+		if opts.PopulationSize != nil {
+			genetic.PopulationSize = int(*opts.PopulationSize)
+		}
+		if opts.Generations != nil {
+			genetic.Generations = int(*opts.Generations)
+		}
+		if opts.Seed != nil {
+			genetic.Seed = *opts.Seed
+		}
+		rate := 0.05
+		if opts.MutationRate != nil {
+			rate = *opts.MutationRate
+		}
+		genetic.Crossover = domain.OnePointCrossover
+		if opts.Crossover != nil && *opts.Crossover == api.Uniform {
+			genetic.Crossover = domain.UniformCrossover
+		}
+		genetic.Mutation = domain.MoveMutation(rate)
+		if opts.Mutation != nil && *opts.Mutation == api.Flip {
+			genetic.Mutation = domain.FlipMutation(rate)
+		}
+		return genetic, nil
+	}
+
+	return nil, errors.New("Unknown solver type")
+}
+
+// Check how much of the shift capacity for a span of time can be covered
+// (GET /schedule/feasibility)
+func (s *server) GetScheduleFeasibility(ctx echo.Context, params api.GetScheduleFeasibilityParams) error {
+	// Date defaults to today, span defaults to week.
+	date := time.Now().UTC()
+	if params.Date != nil {
+		date = params.Date.Time
+	}
+	span := store.WeekSpan
+	if params.Span != nil && *params.Span == "day" {
+		span = store.DaySpan
+	}
+	start, end := store.SpanRange(&date, span)
+
+	problem, err := s.schedulingProblem(ctx.Request().Context(), start, end)
+	if err != nil {
+		return err
+	}
+	f := domain.CheckFeasibility(problem)
+
+	// Convert the result into an OpenAPI Feasibility schema object for
+	// return.
+	unfillable := make([]api.ShiftShortfall, len(f.Shortfalls))
+	for i, sf := range f.Shortfalls {
+		unfillable[i] = api.ShiftShortfall{
+			ShiftId:  int64(sf.Shift),
+			Unfilled: int32(sf.Unfilled),
+		}
+	}
+	return ctx.JSON(http.StatusOK, api.Feasibility{
+		Capacity:    int32(f.Capacity),
+		MaxCoverage: int32(f.MaxCoverage),
+		Unfillable:  unfillable,
+	})
+}
+
+// Collect the workers, shifts, existing shift assignments and shift
+// preferences for a scheduling problem covering a range of times.
+// Shifts either side of the range, as far as the longest rule window,
+// are included as context, so that workers' existing assignments just
+// outside the range count when checking the rules.
+func (s *server) schedulingProblem(ctx context.Context, start time.Time, end time.Time) (*domain.Problem, error) {
+	workers, err := s.db.GetWorkers(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := store.RulesAt(ctx, s.db, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	margin := rules.Window()
+	all, err := s.db.GetShiftsInRange(ctx, start.Add(-margin), end.Add(margin))
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.db.GetShiftAssignmentsInRange(ctx, start.Add(-margin), end.Add(margin))
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.db.GetShiftPreferences(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	shifts := []*model.Shift{}
+	nearby := []*model.Shift{}
+	for _, sh := range all {
+		if sh.StartTime.Before(end) && sh.EndTime.After(start) {
+			shifts = append(shifts, sh)
+		} else {
+			nearby = append(nearby, sh)
+		}
+	}
+	approved := model.TimeOffApproved
+	timeOff, err := s.db.GetTimeOff(ctx, nil, &approved)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Problem{
+		Workers:     workers,
+		Shifts:      shifts,
+		Context:     nearby,
+		Assignments: assignments,
+		Rules:       rules,
+		TimeOff:     timeOff,
+		Weights:     domain.DefaultWeights,
+		Preferences: domain.PreferenceMatrix(prefs, shifts),
+	}, nil
+}
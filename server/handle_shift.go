@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -22,16 +23,31 @@ func (s *server) GetShifts(ctx echo.Context, params api.GetShiftsParams) error {
 	if params.Span != nil && *params.Span == "day" {
 		span = store.DaySpan
 	}
-	shifts, err := s.db.GetShifts(date, span, nil)
+
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+	var team *model.TeamID
+	if params.Team != nil {
+		t := model.TeamID(*params.Team)
+		if !canSeeShift(ctx, worker, &model.Shift{Team: &t}) {
+			return sendError(ctx, http.StatusForbidden, "Not a member of team")
+		}
+		team = &t
+	}
+	shifts, err := s.db.GetShifts(ctx.Request().Context(), date, span, nil, team)
 	if err != nil {
 		return err
 	}
 
 	// Convert the Shift models from the store into OpenAPI Shift
-	// schema objects for return.
-	ss := make([]*api.Shift, len(shifts))
-	for i, s := range shifts {
-		ss[i] = model.ShiftToAPI(s)
+	// schema objects for return, leaving out other teams' shifts.
+	ss := []*api.Shift{}
+	for _, sh := range shifts {
+		if canSeeShift(ctx, worker, sh) {
+			ss = append(ss, model.ShiftToAPI(sh))
+		}
 	}
 	return ctx.JSON(http.StatusOK, ss)
 }
@@ -50,7 +66,15 @@ func (s *server) CreateShift(ctx echo.Context) error {
 	}
 
 	shift := model.ShiftFromAPI(&sh)
-	err = s.db.CreateShift(shift)
+	err = s.checkTeamAdmin(ctx, shift.Team)
+	if err != nil {
+		return err
+	}
+	err = s.checkTeamExists(ctx, shift.Team)
+	if err != nil {
+		return err
+	}
+	err = s.db.CreateShift(ctx.Request().Context(), shift)
 	if err != nil {
 		return err
 	}
@@ -70,13 +94,32 @@ func (s *server) UpdateShift(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	_, err = s.db.GetShiftById(model.ShiftID(*sh.Id))
-	if err != nil {
-		return sendError(ctx, http.StatusBadRequest, "Unknown shift ID")
-	}
-
 	shift := model.ShiftFromAPI(&sh)
-	err = s.db.UpdateShift(shift)
+	err = s.inTx(ctx, func(s *server) error {
+		existing, err := s.db.GetShiftById(ctx.Request().Context(), shift.ID)
+		if errors.Is(err, store.ErrShiftNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown shift ID")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Moving a shift between teams needs admin rights for both.
+		err = s.checkTeamAdmin(ctx, existing.Team)
+		if err != nil {
+			return err
+		}
+		err = s.checkTeamAdmin(ctx, shift.Team)
+		if err != nil {
+			return err
+		}
+		err = s.checkTeamExists(ctx, shift.Team)
+		if err != nil {
+			return err
+		}
+		shift.BiddingClosed = existing.BiddingClosed
+		return s.db.UpdateShift(ctx.Request().Context(), shift)
+	})
 	if err != nil {
 		return err
 	}
@@ -87,7 +130,16 @@ func (s *server) UpdateShift(ctx echo.Context) error {
 // Delete an existing shift
 // (DELETE /shift/{shift-id})
 func (s *server) DeleteShift(ctx echo.Context, shiftId api.ShiftIdParam) error {
-	err := s.db.DeleteShiftById(model.ShiftID(shiftId))
+	shift, err := s.db.GetShiftById(ctx.Request().Context(), model.ShiftID(shiftId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift ID")
+	}
+	err = s.checkTeamAdmin(ctx, shift.Team)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.DeleteShiftById(ctx.Request().Context(), shift.ID)
 	if err != nil {
 		return err
 	}
@@ -98,10 +150,25 @@ func (s *server) DeleteShift(ctx echo.Context, shiftId api.ShiftIdParam) error {
 // Get a single shift
 // (GET /shift/{shift-id})
 func (s *server) GetShift(ctx echo.Context, shiftId api.ShiftIdParam) error {
-	shift, err := s.db.GetShiftById(model.ShiftID(shiftId))
+	shift, err := s.db.GetShiftById(ctx.Request().Context(), model.ShiftID(shiftId))
 	if err != nil {
 		return err
 	}
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+	if !canSeeShift(ctx, worker, shift) {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift ID")
+	}
 
 	return ctx.JSON(http.StatusOK, model.ShiftToAPI(shift))
 }
+
+// Workers can see the shifts of the teams they belong to or manage,
+// plus the shifts that don't belong to a team. Workers with the
+// "shifts:read" permission can see every team's shifts.
+func canSeeShift(ctx echo.Context, worker *model.Worker, shift *model.Shift) bool {
+	return worker.InTeam(shift.Team) || worker.AdminOf(shift.Team) ||
+		hasPermission(ctx, model.PermShiftsRead)
+}
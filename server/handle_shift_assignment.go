@@ -2,10 +2,12 @@ package server
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"skybluetrades.net/work-planning-demo/api"
 	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
 )
 
 // Delete an existing shift assignment
@@ -16,7 +18,7 @@ func (s *server) DeleteShiftAssignment(ctx echo.Context, shiftId api.ShiftIdPara
 		return err
 	}
 
-	err = s.db.DeleteShiftAssignment(worker.ID, model.ShiftID(shiftId))
+	err = s.db.DeleteShiftAssignment(ctx.Request().Context(), worker.ID, model.ShiftID(shiftId))
 	if err != nil {
 		return sendError(ctx, http.StatusBadRequest, "failed to delete assignment")
 	}
@@ -32,10 +34,122 @@ func (s *server) CreateShiftAssignment(ctx echo.Context, shiftId api.ShiftIdPara
 		return err
 	}
 
-	err = s.db.CreateShiftAssignment(worker.ID, model.ShiftID(shiftId))
+	err = s.db.CreateShiftAssignment(ctx.Request().Context(), worker.ID, model.ShiftID(shiftId), nil)
 	if err != nil {
-		return sendError(ctx, http.StatusBadRequest, "failed to delete assignment")
+		return sendError(ctx, http.StatusBadRequest, "failed to create assignment: "+err.Error())
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Assign a worker to a shift
+// (POST /shift/{shift-id}/assignment/{worker-id})
+func (s *server) CreateWorkerShiftAssignment(ctx echo.Context, shiftId api.ShiftIdParam,
+	workerId api.WorkerIdParam, params api.CreateWorkerShiftAssignmentParams) error {
+	err := s.checkShiftAdmin(ctx, model.ShiftID(shiftId))
+	if err != nil {
+		return err
+	}
+	override, err := s.ruleOverride(ctx, params.Override, params.Reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.CreateShiftAssignment(ctx.Request().Context(), model.WorkerID(workerId), model.ShiftID(shiftId), override)
+	if err != nil {
+		return assignmentError(ctx, "failed to create assignment", err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Remove a worker from a shift
+// (DELETE /shift/{shift-id}/assignment/{worker-id})
+func (s *server) DeleteWorkerShiftAssignment(ctx echo.Context,
+	shiftId api.ShiftIdParam, workerId api.WorkerIdParam) error {
+	err := s.checkShiftAdmin(ctx, model.ShiftID(shiftId))
+	if err != nil {
+		return err
+	}
+	err = s.db.DeleteShiftAssignment(ctx.Request().Context(), model.WorkerID(workerId), model.ShiftID(shiftId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift assignment")
 	}
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// Move a worker from one shift to another
+// (PUT /shift/{shift-id}/assignment/{worker-id}/move)
+func (s *server) MoveWorkerShiftAssignment(ctx echo.Context, shiftId api.ShiftIdParam,
+	workerId api.WorkerIdParam, params api.MoveWorkerShiftAssignmentParams) error {
+	err := s.checkShiftAdmin(ctx, model.ShiftID(shiftId))
+	if err != nil {
+		return err
+	}
+	err = s.checkShiftAdmin(ctx, model.ShiftID(params.To))
+	if err != nil {
+		return err
+	}
+	override, err := s.ruleOverride(ctx, params.Override, params.Reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.MoveShiftAssignment(ctx.Request().Context(), model.WorkerID(workerId),
+		model.ShiftID(shiftId), model.ShiftID(params.To), override)
+	if err != nil {
+		return assignmentError(ctx, "failed to move assignment", err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get records of admin rule overrides
+// (GET /overrides)
+func (s *server) GetRuleOverrides(ctx echo.Context, params api.GetRuleOverridesParams) error {
+	var shiftId *model.ShiftID
+	if params.ShiftId != nil {
+		id := model.ShiftID(*params.ShiftId)
+		shiftId = &id
+	}
+
+	overrides, err := s.db.GetRuleOverrides(ctx.Request().Context(), shiftId)
+	if err != nil {
+		return err
+	}
+
+	result := []api.RuleOverride{}
+	for _, o := range overrides {
+		result = append(result, *model.RuleOverrideToAPI(o))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Set up a rule override for the current (admin) user if one is
+// requested, returning an error response if no reason is given.
+func (s *server) ruleOverride(ctx echo.Context,
+	override *bool, reason *string) (*model.RuleOverride, error) {
+	if override == nil || !*override {
+		return nil, nil
+	}
+	if reason == nil || strings.TrimSpace(*reason) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Missing reason for rule override")
+	}
+
+	admin, err := s.currentWorker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.RuleOverride{Admin: admin.ID, Reason: *reason}, nil
+}
+
+// Send the error response for a failed change to shift assignments.
+func assignmentError(ctx echo.Context, msg string, err error) error {
+	switch err {
+	case store.ErrWorkerNotFound, store.ErrShiftNotFound, store.ErrShiftAssignmentNotFound:
+		return sendError(ctx, http.StatusNotFound, msg+": "+err.Error())
+	}
+	return sendError(ctx, http.StatusBadRequest, msg+": "+err.Error())
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+// Longest range of dates to generate shifts for in one request.
+const maxGenerationDays = 366
+
+// Get all shift templates
+// (GET /shift-templates)
+func (s *server) GetShiftTemplates(ctx echo.Context) error {
+	templates, err := s.db.GetShiftTemplates(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []api.ShiftTemplate{}
+	for _, t := range templates {
+		result = append(result, *model.ShiftTemplateToAPI(t))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Create new shift template
+// (POST /shift-templates)
+func (s *server) CreateShiftTemplate(ctx echo.Context) error {
+	var t api.ShiftTemplate
+	err := ctx.Bind(&t)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift template")
+	}
+	err = checkShiftTemplate(ctx, &t)
+	if err != nil {
+		return err
+	}
+
+	template := model.ShiftTemplateFromAPI(&t)
+	err = s.checkTeamExists(ctx, template.Team)
+	if err != nil {
+		return err
+	}
+	err = s.db.CreateShiftTemplate(ctx.Request().Context(), template)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.ShiftTemplateToAPI(template))
+}
+
+// Delete a shift template
+// (DELETE /shift-templates/{template-id})
+func (s *server) DeleteShiftTemplate(ctx echo.Context, templateId api.ShiftTemplateIdParam) error {
+	err := s.db.DeleteShiftTemplateById(ctx.Request().Context(), model.ShiftTemplateID(templateId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown shift template ID")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Generate shifts from templates over a range of dates
+// (POST /shift/generate)
+func (s *server) GenerateShifts(ctx echo.Context) error {
+	var req api.ShiftGeneration
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift generation request")
+	}
+
+	// The end date is inclusive, as for schedule generation.
+	start := req.StartDate.Time
+	end := req.EndDate.Time.AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return sendError(ctx, http.StatusBadRequest, "Bad date range for shift generation")
+	}
+	if end.After(start.AddDate(0, 0, maxGenerationDays)) {
+		return sendError(ctx, http.StatusBadRequest, "Date range too long for shift generation")
+	}
+
+	var templates []*model.ShiftTemplate
+	if req.TemplateIds == nil {
+		templates, err = s.db.GetShiftTemplates(ctx.Request().Context())
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, id := range *req.TemplateIds {
+			t, err := s.db.GetShiftTemplateById(ctx.Request().Context(), model.ShiftTemplateID(id))
+			if err != nil {
+				return sendError(ctx, http.StatusBadRequest, "Unknown shift template ID")
+			}
+			templates = append(templates, t)
+		}
+	}
+
+	shifts := domain.GenerateShifts(templates, start, end)
+	created, err := s.db.CreateShifts(ctx.Request().Context(), shifts)
+	if err != nil {
+		return err
+	}
+
+	isNew := map[model.ShiftID]bool{}
+	result := api.ShiftGenerationResult{Created: []api.Shift{}, Skipped: []api.Shift{}}
+	for _, sh := range created {
+		isNew[sh.ID] = true
+		result.Created = append(result.Created, *model.ShiftToAPI(sh))
+	}
+	for _, sh := range shifts {
+		if !isNew[sh.ID] {
+			result.Skipped = append(result.Skipped, *model.ShiftToAPI(sh))
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get shift swaps offered or proposed by current user
+// (GET /me/swaps)
+func (s *server) GetMeSwaps(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sendSwaps(ctx, &worker.ID, nil)
+}
+
+// Offer one of current user's shifts for swap
+// (POST /me/swaps)
+func (s *server) CreateMeSwap(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var offer api.SwapOffer
+	err = ctx.Bind(&offer)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift swap offer")
+	}
+
+	swap := &model.Swap{
+		Offerer: worker.ID,
+		Shift:   model.ShiftID(offer.ShiftId),
+		Status:  model.SwapOpen,
+	}
+	err = s.db.CreateSwap(ctx.Request().Context(), swap)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to create shift swap offer: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.SwapToAPI(swap))
+}
+
+// Cancel a shift swap offered by current user
+// (DELETE /me/swaps/{swap-id})
+func (s *server) CancelMeSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.inTx(ctx, func(s *server) error {
+		swap, err := s.getSwap(ctx, swapId, &worker.ID)
+		if err != nil {
+			return err
+		}
+		switch swap.Status {
+		case model.SwapCompleted, model.SwapRejected, model.SwapCancelled:
+			return echo.NewHTTPError(http.StatusBadRequest, "Shift swap is already "+string(swap.Status))
+		}
+
+		swap.Status = model.SwapCancelled
+		return s.db.UpdateSwap(ctx.Request().Context(), swap)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get open shift swap offers that current user can take
+// (GET /swaps)
+func (s *server) GetOpenSwaps(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := model.SwapOpen
+	swaps, err := s.db.GetSwaps(ctx.Request().Context(), nil, &status)
+	if err != nil {
+		return err
+	}
+
+	// Only offers that the current user could take outright are listed:
+	// the store checks them exactly as it would the real swap.
+	result := []api.Swap{}
+	for _, swap := range swaps {
+		if swap.Offerer == worker.ID {
+			continue
+		}
+		swap.Taker = &worker.ID
+		if s.db.CheckSwap(ctx.Request().Context(), swap) != nil {
+			continue
+		}
+		swap.Taker = nil
+		result = append(result, *model.SwapToAPI(swap))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Get shift swaps awaiting admin approval
+// (GET /swaps/pending)
+func (s *server) GetPendingSwaps(ctx echo.Context) error {
+	status := model.SwapAccepted
+	return s.sendSwaps(ctx, nil, &status)
+}
+
+// Propose to take an offered shift, optionally in exchange for another
+// (POST /swaps/{swap-id}/proposal)
+func (s *server) ProposeSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var proposal api.SwapProposal
+	err = ctx.Bind(&proposal)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for shift swap proposal")
+	}
+
+	var swap *model.Swap
+	err = s.inTx(ctx, func(s *server) error {
+		var err error
+		swap, err = s.getSwap(ctx, swapId, nil)
+		if err != nil {
+			return err
+		}
+		if swap.Offerer == worker.ID {
+			return echo.NewHTTPError(http.StatusBadRequest, "Can't propose to take your own shift")
+		}
+		if swap.Status != model.SwapOpen {
+			return echo.NewHTTPError(http.StatusBadRequest, "Shift swap is not open")
+		}
+
+		swap.Taker = &worker.ID
+		if proposal.CounterShiftId != nil {
+			counter := model.ShiftID(*proposal.CounterShiftId)
+			swap.CounterShift = &counter
+		}
+		err = s.db.CheckSwap(ctx.Request().Context(), swap)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				"Ineligible shift swap proposal: "+err.Error()).SetInternal(err)
+		}
+
+		swap.Status = model.SwapProposed
+		return s.db.UpdateSwap(ctx.Request().Context(), swap)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.SwapToAPI(swap))
+}
+
+// Accept the proposal for a shift swap offered by current user
+// (PUT /swaps/{swap-id}/accept)
+func (s *server) AcceptSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.inTx(ctx, func(s *server) error {
+		swap, err := s.getSwap(ctx, swapId, &worker.ID)
+		if err != nil {
+			return err
+		}
+		if swap.Status != model.SwapProposed {
+			return echo.NewHTTPError(http.StatusBadRequest, "No proposal to accept")
+		}
+
+		if s.config.SwapApproval {
+			swap.Status = model.SwapAccepted
+			return s.db.UpdateSwap(ctx.Request().Context(), swap)
+		}
+		return s.executeSwap(ctx, swap.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sendSwap(ctx, model.SwapID(swapId))
+}
+
+// Decline the proposal for a shift swap offered by current user
+// (PUT /swaps/{swap-id}/decline)
+func (s *server) DeclineSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var swap *model.Swap
+	err = s.inTx(ctx, func(s *server) error {
+		var err error
+		swap, err = s.getSwap(ctx, swapId, &worker.ID)
+		if err != nil {
+			return err
+		}
+		if swap.Status != model.SwapProposed {
+			return echo.NewHTTPError(http.StatusBadRequest, "No proposal to decline")
+		}
+
+		swap.Taker = nil
+		swap.CounterShift = nil
+		swap.Status = model.SwapOpen
+		return s.db.UpdateSwap(ctx.Request().Context(), swap)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.SwapToAPI(swap))
+}
+
+// Approve an accepted shift swap
+// (PUT /swaps/{swap-id}/approve)
+func (s *server) ApproveSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	err := s.inTx(ctx, func(s *server) error {
+		swap, err := s.getSwap(ctx, swapId, nil)
+		if err != nil {
+			return err
+		}
+		if swap.Status != model.SwapAccepted {
+			return echo.NewHTTPError(http.StatusBadRequest, "Shift swap is not awaiting approval")
+		}
+
+		return s.executeSwap(ctx, swap.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sendSwap(ctx, model.SwapID(swapId))
+}
+
+// Reject an accepted shift swap
+// (PUT /swaps/{swap-id}/reject)
+func (s *server) RejectSwap(ctx echo.Context, swapId api.SwapIdParam) error {
+	var swap *model.Swap
+	err := s.inTx(ctx, func(s *server) error {
+		var err error
+		swap, err = s.getSwap(ctx, swapId, nil)
+		if err != nil {
+			return err
+		}
+		if swap.Status != model.SwapAccepted {
+			return echo.NewHTTPError(http.StatusBadRequest, "Shift swap is not awaiting approval")
+		}
+
+		swap.Status = model.SwapRejected
+		return s.db.UpdateSwap(ctx.Request().Context(), swap)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.SwapToAPI(swap))
+}
+
+// Get a swap, returning a not found error response if it doesn't
+// exist, or if it wasn't offered by the worker given.
+func (s *server) getSwap(ctx echo.Context,
+	id api.SwapIdParam, offerer *model.WorkerID) (*model.Swap, error) {
+	swap, err := s.db.GetSwapById(ctx.Request().Context(), model.SwapID(id))
+	if errors.Is(err, store.ErrSwapNotFound) || err == nil && offerer != nil && swap.Offerer != *offerer {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Unknown shift swap ID")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+// Carry out a swap. The store checks the swap again, since the
+// workers' schedules may have changed since it was proposed, and leaves
+// the shift assignments alone if it fails.
+func (s *server) executeSwap(ctx echo.Context, id model.SwapID) error {
+	err := s.db.ExecuteSwap(ctx.Request().Context(), id)
+	if errors.Is(err, store.ErrSwapNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown shift swap ID")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusConflict,
+			"Shift swap could not be carried out: "+err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+func (s *server) sendSwap(ctx echo.Context, id model.SwapID) error {
+	swap, err := s.db.GetSwapById(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.SwapToAPI(swap))
+}
+
+func (s *server) sendSwaps(ctx echo.Context,
+	workerId *model.WorkerID, status *model.SwapStatus) error {
+	swaps, err := s.db.GetSwaps(ctx.Request().Context(), workerId, status)
+	if err != nil {
+		return err
+	}
+
+	result := []api.Swap{}
+	for _, swap := range swaps {
+		result = append(result, *model.SwapToAPI(swap))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
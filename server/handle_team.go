@@ -0,0 +1,201 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get all teams
+// (GET /teams)
+func (s *server) GetTeams(ctx echo.Context) error {
+	teams, err := s.db.GetTeams(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	result := []api.Team{}
+	for _, t := range teams {
+		result = append(result, *model.TeamToAPI(t))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// Create new team
+// (POST /teams)
+func (s *server) CreateTeam(ctx echo.Context) error {
+	var t api.Team
+	err := ctx.Bind(&t)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for team")
+	}
+
+	team := model.TeamFromAPI(&t)
+	if team.Name == "" {
+		return sendError(ctx, http.StatusBadRequest, "Missing name for team")
+	}
+	err = s.db.CreateTeam(ctx.Request().Context(), team)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.TeamToAPI(team))
+}
+
+// Get a single team
+// (GET /teams/{team-id})
+func (s *server) GetTeam(ctx echo.Context, teamId api.TeamIdParam) error {
+	team, err := s.db.GetTeamById(ctx.Request().Context(), model.TeamID(teamId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown team ID")
+	}
+
+	return ctx.JSON(http.StatusOK, model.TeamToAPI(team))
+}
+
+// Delete a team, along with its shifts
+// (DELETE /teams/{team-id})
+func (s *server) DeleteTeam(ctx echo.Context, teamId api.TeamIdParam) error {
+	err := s.db.DeleteTeamById(ctx.Request().Context(), model.TeamID(teamId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown team ID")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get the members of a team
+// (GET /teams/{team-id}/members)
+func (s *server) GetTeamMembers(ctx echo.Context, teamId api.TeamIdParam) error {
+	team, err := s.db.GetTeamById(ctx.Request().Context(), model.TeamID(teamId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown team ID")
+	}
+	err = s.checkTeamAdmin(ctx, &team.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.sendWorkers(ctx, &team.ID)
+}
+
+// Add a worker to a team, or change whether they're a team admin
+// (PUT /teams/{team-id}/members/{worker-id})
+func (s *server) SetTeamMember(ctx echo.Context,
+	teamId api.TeamIdParam, workerId api.WorkerIdParam) error {
+	var m api.TeamMember
+	err := ctx.Bind(&m)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for team member")
+	}
+
+	team := model.TeamID(teamId)
+	err = s.checkTeamAdmin(ctx, &team)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.SetTeamMember(ctx.Request().Context(), team, model.WorkerID(workerId), m.IsAdmin)
+	if errors.Is(err, store.ErrTeamNotFound) {
+		return sendError(ctx, http.StatusNotFound, "Unknown team ID")
+	}
+	if errors.Is(err, store.ErrWorkerNotFound) {
+		return sendError(ctx, http.StatusNotFound, "Unknown worker ID")
+	}
+	if err != nil {
+		return err
+	}
+
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, model.WorkerToAPI(worker))
+}
+
+// Remove a worker from a team
+// (DELETE /teams/{team-id}/members/{worker-id})
+func (s *server) DeleteTeamMember(ctx echo.Context,
+	teamId api.TeamIdParam, workerId api.WorkerIdParam) error {
+	team := model.TeamID(teamId)
+	err := s.checkTeamAdmin(ctx, &team)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.DeleteTeamMember(ctx.Request().Context(), team, model.WorkerID(workerId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown team member")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Check that the current user can manage a team (or, for a nil team,
+// the shifts that don't belong to one), returning an error response if
+// they can't. Users with the operation's permissions can manage every
+// team.
+func (s *server) checkTeamAdmin(ctx echo.Context, team *model.TeamID) error {
+	if orgWide(ctx) {
+		return nil
+	}
+	admin, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin.AdminOf(team) {
+		return echo.NewHTTPError(http.StatusForbidden, "Not an admin for team")
+	}
+	return nil
+}
+
+// Check that the current user can manage a shift's team, returning an
+// error response if the shift doesn't exist or they can't.
+func (s *server) checkShiftAdmin(ctx echo.Context, shiftId model.ShiftID) error {
+	shift, err := s.db.GetShiftById(ctx.Request().Context(), shiftId)
+	if errors.Is(err, store.ErrShiftNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown shift ID")
+	}
+	if err != nil {
+		return err
+	}
+	return s.checkTeamAdmin(ctx, shift.Team)
+}
+
+// Check that the current user can manage a worker, which team admins
+// can do for the members of their teams, returning an error response
+// if they can't.
+func (s *server) checkWorkerAdmin(ctx echo.Context, worker *model.Worker) error {
+	admin, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+	if orgWide(ctx) || admin.IsAdmin {
+		return nil
+	}
+	for _, m := range worker.Teams {
+		if admin.AdminOf(&m.Team) {
+			return nil
+		}
+	}
+	return echo.NewHTTPError(http.StatusForbidden, "Not an admin for any of worker's teams")
+}
+
+// Check that a team referred to in a request exists, returning an
+// error response if it doesn't.
+func (s *server) checkTeamExists(ctx echo.Context, team *model.TeamID) error {
+	if team == nil {
+		return nil
+	}
+	_, err := s.db.GetTeamById(ctx.Request().Context(), *team)
+	if errors.Is(err, store.ErrTeamNotFound) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown team ID")
+	}
+	return err
+}
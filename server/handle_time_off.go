@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Get time off requests for current user
+// (GET /me/time-off)
+func (s *server) GetMeTimeOff(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sendTimeOff(ctx, &worker.ID, nil)
+}
+
+// Request time off for current user
+// (POST /me/time-off)
+func (s *server) CreateMeTimeOff(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var t api.TimeOff
+	err = ctx.Bind(&t)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for time off request")
+	}
+	err = checkTimeOff(ctx, &t)
+	if err != nil {
+		return err
+	}
+
+	// New requests always need approval, whatever the worker asks for.
+	timeOff := model.TimeOffFromAPI(&t)
+	timeOff.Worker = worker.ID
+	timeOff.Status = model.TimeOffPending
+	err = s.db.CreateTimeOff(ctx.Request().Context(), timeOff)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Failed to create time off request: "+err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, model.TimeOffToAPI(timeOff))
+}
+
+// Cancel a time off request for current user
+// (DELETE /me/time-off/{time-off-id})
+func (s *server) DeleteMeTimeOff(ctx echo.Context, timeOffId api.TimeOffIdParam) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeOff, err := s.db.GetTimeOffById(ctx.Request().Context(), model.TimeOffID(timeOffId))
+	if err != nil || timeOff.Worker != worker.ID {
+		return sendError(ctx, http.StatusNotFound, "Unknown time off request ID")
+	}
+
+	err = s.db.DeleteTimeOffById(ctx.Request().Context(), timeOff.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Get time off requests for all workers
+// (GET /time-off)
+func (s *server) GetTimeOffRequests(ctx echo.Context, params api.GetTimeOffRequestsParams) error {
+	var status *model.TimeOffStatus
+	if params.Status != nil {
+		st := model.TimeOffStatus(*params.Status)
+		status = &st
+	}
+
+	return s.sendTimeOff(ctx, nil, status)
+}
+
+// Get a single time off request
+// (GET /time-off/{time-off-id})
+func (s *server) GetTimeOffRequest(ctx echo.Context, timeOffId api.TimeOffIdParam) error {
+	timeOff, err := s.db.GetTimeOffById(ctx.Request().Context(), model.TimeOffID(timeOffId))
+	if err != nil {
+		return sendError(ctx, http.StatusNotFound, "Unknown time off request ID")
+	}
+
+	return ctx.JSON(http.StatusOK, model.TimeOffToAPI(timeOff))
+}
+
+// Approve or reject a time off request
+// (PUT /time-off/{time-off-id})
+func (s *server) DecideTimeOffRequest(ctx echo.Context, timeOffId api.TimeOffIdParam) error {
+	var d api.TimeOffDecision
+	err := ctx.Bind(&d)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for time off decision")
+	}
+
+	// The request is checked and decided in one transaction, so that two
+	// admins can't both decide it.
+	var timeOff *model.TimeOff
+	var conflicts []api.Shift
+	err = s.inTx(ctx, func(s *server) error {
+		var err error
+		timeOff, err = s.db.GetTimeOffById(ctx.Request().Context(), model.TimeOffID(timeOffId))
+		if errors.Is(err, store.ErrTimeOffNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Unknown time off request ID")
+		}
+		if err != nil {
+			return err
+		}
+
+		// Requests can only be decided once, and only before they start.
+		status := model.TimeOffStatus(d.Status)
+		if status != model.TimeOffApproved && status != model.TimeOffRejected {
+			return echo.NewHTTPError(http.StatusBadRequest, "Bad status for time off decision")
+		}
+		if timeOff.Status != model.TimeOffPending {
+			return echo.NewHTTPError(http.StatusBadRequest, "Time off request has already been decided")
+		}
+		if !time.Now().Before(timeOff.StartTime) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Time off request has already started")
+		}
+
+		timeOff.Status = status
+		err = s.db.UpdateTimeOffStatus(ctx.Request().Context(), timeOff.ID, timeOff.Status)
+		if err != nil {
+			return err
+		}
+
+		// Approving time off doesn't remove the worker from shifts
+		// they're already assigned to: we report them so that an admin
+		// can sort out cover.
+		conflicts = []api.Shift{}
+		if timeOff.Status == model.TimeOffApproved {
+			shifts, err := s.timeOffConflicts(ctx.Request().Context(), timeOff)
+			if err != nil {
+				return err
+			}
+			for _, sh := range shifts {
+				conflicts = append(conflicts, *model.ShiftToAPI(sh))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, api.TimeOffDecisionResult{
+		TimeOff:   *model.TimeOffToAPI(timeOff),
+		Conflicts: conflicts,
+	})
+}
+
+// Find the shifts a worker is assigned to during a period of time off.
+func (s *server) timeOffConflicts(ctx context.Context, timeOff *model.TimeOff) ([]*model.Shift, error) {
+	shifts, err := s.db.GetShiftsInRange(ctx, timeOff.StartTime, timeOff.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.db.GetShiftAssignmentsInRange(ctx, timeOff.StartTime, timeOff.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := map[model.ShiftID]bool{}
+	for _, a := range assignments {
+		if a.Worker == timeOff.Worker {
+			assigned[a.Shift] = true
+		}
+	}
+	conflicts := []*model.Shift{}
+	for _, sh := range shifts {
+		if assigned[sh.ID] && timeOff.Overlaps(sh) {
+			conflicts = append(conflicts, sh)
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].StartTime.Before(conflicts[j].StartTime)
+	})
+	return conflicts, nil
+}
+
+func (s *server) sendTimeOff(ctx echo.Context,
+	workerId *model.WorkerID, status *model.TimeOffStatus) error {
+	timeOff, err := s.db.GetTimeOff(ctx.Request().Context(), workerId, status)
+	if err != nil {
+		return err
+	}
+
+	result := []api.TimeOff{}
+	for _, t := range timeOff {
+		result = append(result, *model.TimeOffToAPI(t))
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Number of recovery codes given out when two-factor authentication is
+// turned on.
+const recoveryCodeCount = 10
+
+// Complete a login with a two-factor authentication code
+// (POST /auth/2fa)
+func (s *server) PostLoginTwoFactor(ctx echo.Context) error {
+	var login api.TwoFactorLogin
+	err := ctx.Bind(&login)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for two-factor login")
+	}
+
+	claims, err := ValidateChallengeToken(login.ChallengeToken, s.config.AuthKey)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Invalid challenge token")
+	}
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), claims.ID)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Invalid challenge token")
+	}
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Invalid challenge token")
+	}
+	// Guessing codes counts as failed logins for the worker's account.
+	err = s.checkLoginAllowed(ctx, worker.Email)
+	if err != nil {
+		return err
+	}
+	err = s.checkTwoFactorCode(ctx.Request().Context(), tf, login.Code)
+	if err != nil {
+		err = s.recordLoginFailure(ctx, worker.Email)
+		if err != nil {
+			return err
+		}
+		return sendError(ctx, http.StatusForbidden, "Invalid two-factor authentication code")
+	}
+	err = s.db.ClearLoginAttempts(ctx.Request().Context(), accountLoginKey(worker.Email))
+	if err != nil {
+		return err
+	}
+
+	// Workers who had to enrol when they logged in are enrolled now.
+	var codes []string
+	if !tf.Enabled {
+		codes, err = s.enableTwoFactor(ctx.Request().Context(), tf)
+		if err != nil {
+			return err
+		}
+	}
+
+	creds, err := s.newLoginTokens(ctx.Request().Context(), worker)
+	if err != nil {
+		return err
+	}
+	if codes != nil {
+		creds.RecoveryCodes = &codes
+	}
+	return ctx.JSON(http.StatusOK, creds)
+}
+
+// Get current user's two-factor authentication status
+// (GET /me/2fa)
+func (s *server) GetMeTwoFactor(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := api.TwoFactorStatus{}
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil && !errors.Is(err, store.ErrTwoFactorNotFound) {
+		return err
+	}
+	if err == nil && tf.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesLeft = len(tf.RecoveryCodes)
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
+
+// Start enrolling current user for two-factor authentication
+// (POST /me/2fa)
+func (s *server) EnrolMeTwoFactor(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err == nil && tf.Enabled {
+		return sendError(ctx, http.StatusBadRequest, "Two-factor authentication is already enabled")
+	}
+
+	enrolment, err := s.startTwoFactorEnrolment(ctx.Request().Context(), worker)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, enrolment)
+}
+
+// Confirm two-factor enrolment with a code, turning it on
+// (POST /me/2fa/confirm)
+func (s *server) ConfirmMeTwoFactor(ctx echo.Context) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	var code api.TwoFactorCode
+	err = ctx.Bind(&code)
+	if err != nil {
+		return sendError(ctx, http.StatusBadRequest, "Invalid format for two-factor code")
+	}
+
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil || tf.Enabled {
+		return sendError(ctx, http.StatusBadRequest, "No two-factor enrolment to confirm")
+	}
+	err = s.checkTwoFactorCode(ctx.Request().Context(), tf, code.Code)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Invalid two-factor authentication code")
+	}
+
+	codes, err := s.enableTwoFactor(ctx.Request().Context(), tf)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, api.RecoveryCodes{RecoveryCodes: codes})
+}
+
+// Turn off two-factor authentication for current user
+// (DELETE /me/2fa)
+func (s *server) DeleteMeTwoFactor(ctx echo.Context, params api.DeleteMeTwoFactorParams) error {
+	worker, err := s.currentWorker(ctx)
+	if err != nil {
+		return err
+	}
+
+	tf, err := s.db.GetTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil || !tf.Enabled {
+		return sendError(ctx, http.StatusBadRequest, "Two-factor authentication is not enabled")
+	}
+	if s.config.AdminTwoFactor && worker.IsAdmin {
+		return sendError(ctx, http.StatusBadRequest, "Two-factor authentication is required for admins")
+	}
+	err = s.checkTwoFactorCode(ctx.Request().Context(), tf, params.Code)
+	if err != nil {
+		return sendError(ctx, http.StatusForbidden, "Invalid two-factor authentication code")
+	}
+
+	err = s.db.DeleteTwoFactor(ctx.Request().Context(), worker.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Send the challenge for the second step of a login. Workers who
+// haven't enrolled yet get a new TOTP secret to enrol with.
+func (s *server) sendTwoFactorChallenge(ctx echo.Context, worker *model.Worker, enabled bool) error {
+	token, err := GenerateChallengeToken(worker, s.config)
+	if err != nil {
+		return err
+	}
+	challenge := api.TwoFactorChallenge{ChallengeToken: token}
+	if !enabled {
+		challenge.Enrolment, err = s.startTwoFactorEnrolment(ctx.Request().Context(), worker)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.JSON(http.StatusAccepted, challenge)
+}
+
+// Save a new TOTP secret for a worker, which isn't used until they
+// confirm it with a code.
+func (s *server) startTwoFactorEnrolment(ctx context.Context, worker *model.Worker) (*api.TwoFactorEnrolment, error) {
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.SetTwoFactor(ctx, &model.TwoFactor{Worker: worker.ID, Secret: secret})
+	if err != nil {
+		return nil, err
+	}
+	return &api.TwoFactorEnrolment{
+		Secret:     secret,
+		OtpauthUrl: totpURL(secret, worker.Email),
+	}, nil
+}
+
+// Turn on two-factor authentication for a worker whose enrolment has
+// been confirmed, returning their new recovery codes.
+func (s *server) enableTwoFactor(ctx context.Context, tf *model.TwoFactor) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(b)
+		codes[i] = code[:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:]
+		hashes[i] = hashToken(code)
+	}
+
+	// The TOTP code that confirmed the enrolment has been recorded in
+	// the store already, so the last step is carried over from there.
+	current, err := s.db.GetTwoFactor(ctx, tf.Worker)
+	if err != nil {
+		return nil, err
+	}
+	current.Enabled = true
+	current.RecoveryCodes = hashes
+	err = s.db.SetTwoFactor(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Check a two-factor code, which can be a TOTP code or (once two-factor
+// authentication is turned on) one of the worker's recovery codes.
+// Either kind of code can only be used once.
+func (s *server) checkTwoFactorCode(ctx context.Context, tf *model.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totpDigits {
+		step, ok := checkTOTP(tf.Secret, code, time.Now())
+		if !ok {
+			return store.ErrTwoFactorCodeInvalid
+		}
+		return s.db.RecordTOTPStep(ctx, tf.Worker, step)
+	}
+
+	if !tf.Enabled {
+		return store.ErrTwoFactorCodeInvalid
+	}
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	return s.db.UseRecoveryCode(ctx, tf.Worker, hashToken(code))
+}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/exp/slices"
 	"skybluetrades.net/work-planning-demo/api"
 	"skybluetrades.net/work-planning-demo/model"
 	"skybluetrades.net/work-planning-demo/store"
@@ -13,7 +15,7 @@ import (
 // Delete an existing worker
 // (DELETE /worker/{worker-id})
 func (s *server) DeleteWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
-	err := s.db.DeleteWorkerById(model.WorkerID(workerId))
+	err := s.db.DeleteWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
 	if err != nil {
 		return err
 	}
@@ -23,19 +25,40 @@ func (s *server) DeleteWorker(ctx echo.Context, workerId api.WorkerIdParam) erro
 
 // Get all workers
 // (GET /worker)
-func (s *server) GetWorkers(ctx echo.Context) error {
-	workers, err := s.db.GetWorkers()
+func (s *server) GetWorkers(ctx echo.Context, params api.GetWorkersParams) error {
+	if params.Team != nil {
+		team := model.TeamID(*params.Team)
+		err := s.checkTeamAdmin(ctx, &team)
+		if err != nil {
+			return err
+		}
+		return s.sendWorkers(ctx, &team)
+	}
+
+	if orgWide(ctx) {
+		return s.sendWorkers(ctx, nil)
+	}
+	admin, err := s.currentWorker(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Convert the Worker models from the store into OpenAPI Worker
-	// schema objects for return.
-	ws := make([]*api.Worker, len(workers))
-	for i, w := range workers {
-		ws[i] = model.WorkerToAPI(w)
+	// Team admins get the members of all the teams they manage.
+	workers := []*model.Worker{}
+	seen := map[model.WorkerID]bool{}
+	for _, team := range admin.AdminTeams() {
+		members, err := s.db.GetWorkers(ctx.Request().Context(), &team)
+		if err != nil {
+			return err
+		}
+		for _, w := range members {
+			if !seen[w.ID] {
+				seen[w.ID] = true
+				workers = append(workers, w)
+			}
+		}
 	}
-	return ctx.JSON(http.StatusOK, ws)
+	return ctx.JSON(http.StatusOK, workersToAPI(workers))
 }
 
 // Create new worker
@@ -52,7 +75,14 @@ func (s *server) CreateWorker(ctx echo.Context) error {
 	}
 
 	worker := model.WorkerFromAPI(&w)
-	err = s.db.CreateWorker(worker)
+	err = s.checkWorkerDetails(ctx, worker, &model.Worker{})
+	if err != nil {
+		return err
+	}
+	err = s.db.CreateWorker(ctx.Request().Context(), worker)
+	if errors.Is(err, store.ErrWorkerEmailExists) {
+		return sendError(ctx, http.StatusBadRequest, "Worker email already in use")
+	}
 	if err != nil {
 		return err
 	}
@@ -72,13 +102,25 @@ func (s *server) UpdateWorker(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	_, err = s.db.GetWorkerById(model.WorkerID(*w.Id))
-	if err != nil {
-		return sendError(ctx, http.StatusBadRequest, "Unknown worker ID")
-	}
-
 	worker := model.WorkerFromAPI(&w)
-	err = s.db.UpdateWorker(worker)
+	err = s.inTx(ctx, func(s *server) error {
+		existing, err := s.db.GetWorkerById(ctx.Request().Context(), worker.ID)
+		if errors.Is(err, store.ErrWorkerNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown worker ID")
+		}
+		if err != nil {
+			return err
+		}
+
+		err = s.checkWorkerDetails(ctx, worker, existing)
+		if err != nil {
+			return err
+		}
+		return s.db.UpdateWorker(ctx.Request().Context(), worker)
+	})
+	if errors.Is(err, store.ErrWorkerEmailExists) {
+		return sendError(ctx, http.StatusBadRequest, "Worker email already in use")
+	}
 	if err != nil {
 		return err
 	}
@@ -89,7 +131,11 @@ func (s *server) UpdateWorker(ctx echo.Context) error {
 // Get a single worker
 // (GET /worker/{worker-id})
 func (s *server) GetWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
-	worker, err := s.db.GetWorkerById(model.WorkerID(workerId))
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return err
+	}
+	err = s.checkWorkerAdmin(ctx, worker)
 	if err != nil {
 		return err
 	}
@@ -101,7 +147,11 @@ func (s *server) GetWorker(ctx echo.Context, workerId api.WorkerIdParam) error {
 // (GET /worker/{worker-id}/schedule)
 func (s *server) GetWorkerSchedule(ctx echo.Context,
 	workerId api.WorkerIdParam, params api.GetWorkerScheduleParams) error {
-	worker, err := s.db.GetWorkerById(model.WorkerID(workerId))
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), model.WorkerID(workerId))
+	if err != nil {
+		return err
+	}
+	err = s.checkWorkerAdmin(ctx, worker)
 	if err != nil {
 		return err
 	}
@@ -116,7 +166,7 @@ func (s *server) GetWorkerSchedule(ctx echo.Context,
 	if params.Span != nil && *params.Span == "day" {
 		span = store.DaySpan
 	}
-	shifts, err := s.db.GetShifts(date, span, &worker.ID)
+	shifts, err := s.db.GetShifts(ctx.Request().Context(), date, span, &worker.ID, nil)
 	if err != nil {
 		return err
 	}
@@ -129,3 +179,104 @@ func (s *server) GetWorkerSchedule(ctx echo.Context,
 	}
 	return ctx.JSON(http.StatusOK, ss)
 }
+
+func (s *server) sendWorkers(ctx echo.Context, team *model.TeamID) error {
+	workers, err := s.db.GetWorkers(ctx.Request().Context(), team)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, workersToAPI(workers))
+}
+
+// Convert the Worker models from the store into OpenAPI Worker schema
+// objects for return.
+func workersToAPI(workers []*model.Worker) []*api.Worker {
+	ws := make([]*api.Worker, len(workers))
+	for i, w := range workers {
+		ws[i] = model.WorkerToAPI(w)
+	}
+	return ws
+}
+
+// Check that the teams and roles a worker is being given exist,
+// returning an error response if they don't, and that the current user
+// is allowed to make the changes. Changing a worker's roles or making
+// them an organisation admin needs the "roles:manage" permission, and
+// changing their team memberships needs "teams:manage" and the right to
+// manage the teams involved, as it would through the team member
+// endpoints.
+func (s *server) checkWorkerDetails(ctx echo.Context,
+	worker *model.Worker, existing *model.Worker) error {
+	for _, m := range worker.Teams {
+		err := s.checkTeamExists(ctx, &m.Team)
+		if err != nil {
+			return err
+		}
+	}
+
+	changed := worker.IsAdmin != existing.IsAdmin || len(worker.Roles) != len(existing.Roles)
+	for _, r := range worker.Roles {
+		if !slices.Contains(existing.Roles, r) {
+			changed = true
+		}
+		_, err := s.db.GetRoleById(ctx.Request().Context(), r)
+		if errors.Is(err, store.ErrRoleNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unknown role ID")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if changed && !hasPermission(ctx, model.PermRolesManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "roles:manage permission needed to change worker's roles")
+	}
+
+	teams := changedTeams(worker.Teams, existing.Teams)
+	if len(teams) > 0 && !hasPermission(ctx, model.PermTeamsManage) {
+		return echo.NewHTTPError(http.StatusForbidden, "teams:manage permission needed to change worker's teams")
+	}
+	for _, team := range teams {
+		err := s.checkTeamAdmin(ctx, &team)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Taking over a privileged worker's account by changing their email
+	// or password would give the caller the worker's permissions, so
+	// only the worker themselves and users who could give out those
+	// permissions can do it.
+	credentials := worker.Password != "" || worker.Email != existing.Email
+	if credentials && privileged(existing) && !hasPermission(ctx, model.PermRolesManage) {
+		claims, _ := ctx.Get("claims").(*JWTClaim)
+		if claims == nil || claims.ID != existing.ID {
+			return echo.NewHTTPError(http.StatusForbidden,
+				"roles:manage permission needed to change privileged worker's credentials")
+		}
+	}
+	return nil
+}
+
+// Find the teams whose membership changes between two lists of team
+// memberships, either by a worker joining or leaving them or by a
+// change to whether they're a team admin.
+func changedTeams(memberships []model.Membership, existing []model.Membership) []model.TeamID {
+	teams := []model.TeamID{}
+	for _, m := range memberships {
+		if !slices.Contains(existing, m) {
+			teams = append(teams, m.Team)
+		}
+	}
+	for _, m := range existing {
+		if !slices.Contains(memberships, m) && !slices.Contains(teams, m.Team) {
+			teams = append(teams, m.Team)
+		}
+	}
+	return teams
+}
+
+// Privileged workers have permissions beyond those of an ordinary
+// worker: organisation admins, team admins and workers with roles.
+func privileged(worker *model.Worker) bool {
+	return worker.IsAdmin || len(worker.Roles) > 0 || len(worker.AdminTeams()) > 0
+}
@@ -1,17 +1,21 @@
 package server
 
 import (
+	"context"
+	"time"
+
 	"github.com/deepmap/oapi-codegen/pkg/middleware"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
+	"skybluetrades.net/work-planning-demo/store"
 )
 
-func CreateMiddleware(spec *openapi3.T, cfg *Config) ([]echo.MiddlewareFunc, error) {
+func CreateMiddleware(spec *openapi3.T, cfg *Config, db store.Store) ([]echo.MiddlewareFunc, error) {
 	validator := middleware.OapiRequestValidatorWithOptions(spec,
 		&middleware.Options{
 			Options: openapi3filter.Options{
-				AuthenticationFunc: NewAuthenticator(cfg),
+				AuthenticationFunc: NewAuthenticator(cfg, db),
 			},
 			Skipper: func(ctx echo.Context) bool {
 				// Skip checks for static files.
@@ -20,5 +24,23 @@ func CreateMiddleware(spec *openapi3.T, cfg *Config) ([]echo.MiddlewareFunc, err
 			},
 		})
 
-	return []echo.MiddlewareFunc{validator}, nil
+	mw := []echo.MiddlewareFunc{validator}
+	if cfg.RequestTimeout > 0 {
+		timeout := time.Duration(cfg.RequestTimeout) * time.Second
+		mw = append([]echo.MiddlewareFunc{requestTimeout(timeout)}, mw...)
+	}
+	return mw, nil
+}
+
+// Give each request's context a deadline, which is passed on to the
+// store along with the cancellation when a client disconnects.
+func requestTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			rctx, cancel := context.WithTimeout(ctx.Request().Context(), timeout)
+			defer cancel()
+			ctx.SetRequest(ctx.Request().WithContext(rctx))
+			return next(ctx)
+		}
+	}
 }
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/mailer"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+const testPassword = "test-password"
+
+// Set up a server with an organisation admin, a worker with a role
+// giving them some permissions, and an ordinary worker.
+func permissionSetup(t *testing.T, perms ...model.Permission) (*httpexpect.Expect, func(), store.Store) {
+	ctx := context.Background()
+	db, _ := store.NewMemoryStore()
+	role := &model.Role{Name: "test", Permissions: perms}
+	db.CreateRole(ctx, role)
+	db.CreateTeam(ctx, &model.Team{Name: "team"})
+	for _, w := range []*model.Worker{
+		{Email: adminEmail, Name: adminName, IsAdmin: true, Password: adminPassword},
+		{Email: "role@test.com", Name: "role", Password: testPassword, Roles: []model.RoleID{role.ID}},
+		{Email: "worker@test.com", Name: "worker", Password: testPassword},
+	} {
+		db.CreateWorker(ctx, w)
+	}
+
+	cfg := testConfig()
+	cfg.AccessTokenLease = 60
+	e, srv := storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	return e, srv.Close, db
+}
+
+// Log in as a worker, returning an authorization header value.
+func loginAs(e *httpexpect.Expect, email string, password string) string {
+	token := e.POST("/auth/login").WithJSON(api.Login{Email: email, Password: password}).
+		Expect().Status(http.StatusOK).
+		JSON().Object().Value("access_token").String().Raw()
+	return "Bearer " + token
+}
+
+func getWorker(t *testing.T, db store.Store, email string) *api.Worker {
+	worker, err := db.GetWorkerByEmail(context.Background(), email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.WorkerToAPI(worker)
+}
+
+// Operations need the permissions named by their scopes in the OpenAPI
+// spec, which workers get from their roles.
+func TestPermissionScopes(t *testing.T) {
+	e, done, _ := permissionSetup(t, model.PermWorkersRead, model.PermShiftsRead)
+	defer done()
+
+	role := loginAs(e, "role@test.com", testPassword)
+	worker := loginAs(e, "worker@test.com", testPassword)
+	admin := loginAs(e, adminEmail, adminPassword)
+
+	tests := []struct {
+		method string
+		path   string
+		auth   string
+		status int
+	}{
+		{"GET", "/worker", role, http.StatusOK},
+		{"GET", "/worker", worker, http.StatusForbidden},
+		{"GET", "/worker", admin, http.StatusOK},
+		{"GET", "/teams/1/members", role, http.StatusForbidden},
+		{"GET", "/teams/1/members", admin, http.StatusOK},
+		{"GET", "/roles", role, http.StatusForbidden},
+		{"GET", "/roles", admin, http.StatusOK},
+		{"GET", "/overrides", role, http.StatusForbidden},
+		{"GET", "/lockouts", role, http.StatusOK},
+		{"GET", "/lockouts", worker, http.StatusForbidden},
+	}
+	for _, test := range tests {
+		e.Request(test.method, test.path).WithHeader("Authorization", test.auth).
+			Expect().Status(test.status)
+	}
+}
+
+// Workers who can edit workers can't use that to give out permissions,
+// or to take over the accounts of workers who have them.
+func TestPermissionWorkerEscalation(t *testing.T) {
+	e, done, db := permissionSetup(t, model.PermWorkersRead, model.PermWorkersWrite)
+	defer done()
+
+	role := loginAs(e, "role@test.com", testPassword)
+	admin := loginAs(e, adminEmail, adminPassword)
+	password := func(p string) *string { return &p }
+	update := func(auth string, w *api.Worker, status int) {
+		e.PUT("/worker").WithHeader("Authorization", auth).WithJSON(w).
+			Expect().Status(status)
+	}
+
+	// Ordinary workers' details can be changed.
+	worker := getWorker(t, db, "worker@test.com")
+	worker.Name = "renamed"
+	worker.Password = password("new-password")
+	update(role, worker, http.StatusOK)
+	loginAs(e, "worker@test.com", "new-password")
+
+	// Giving out roles or organisation admin rights needs the
+	// "roles:manage" permission.
+	worker = getWorker(t, db, "worker@test.com")
+	worker.IsAdmin = true
+	update(role, worker, http.StatusForbidden)
+	worker.IsAdmin = false
+	worker.Roles = &[]api.RoleId{1}
+	update(role, worker, http.StatusForbidden)
+
+	// Changing team memberships needs the "teams:manage" permission.
+	worker = getWorker(t, db, "worker@test.com")
+	worker.Teams = &[]api.TeamMembership{{TeamId: 1, IsAdmin: true}}
+	update(role, worker, http.StatusForbidden)
+	e.POST("/worker").WithHeader("Authorization", role).
+		WithJSON(api.Worker{Email: "new@test.com", Name: "new", Teams: worker.Teams}).
+		Expect().Status(http.StatusForbidden)
+	update(admin, worker, http.StatusOK)
+
+	// The credentials of privileged workers, including the team admin
+	// just made, can't be changed without "roles:manage"...
+	for _, email := range []string{adminEmail, "worker@test.com"} {
+		w := getWorker(t, db, email)
+		w.Password = password("taken-over")
+		update(role, w, http.StatusForbidden)
+		w.Password = nil
+		w.Email = "attacker@test.com"
+		update(role, w, http.StatusForbidden)
+	}
+	e.POST("/auth/login").WithJSON(api.Login{Email: adminEmail, Password: "taken-over"}).
+		Expect().Status(http.StatusForbidden)
+
+	// ...except by the worker themselves.
+	self := getWorker(t, db, "role@test.com")
+	self.Password = password("changed")
+	update(role, self, http.StatusOK)
+	loginAs(e, "role@test.com", "changed")
+}
+
+// Team admins can only see and manage their own teams' members and
+// shifts, while workers with org-wide permissions can manage every
+// team's.
+func TestPermissionTeamScope(t *testing.T) {
+	e, done, db := permissionSetup(t, model.PermWorkersRead, model.PermShiftsRead,
+		model.PermShiftsWrite, model.PermAssignmentsWrite, model.PermTeamsManage)
+	defer done()
+	ctx := context.Background()
+
+	// Team 1 is managed by the lead, and each team has one member and
+	// one shift.
+	db.CreateTeam(ctx, &model.Team{Name: "other"})
+	lead := &model.Worker{Email: "lead@test.com", Name: "lead", Password: testPassword}
+	member1 := &model.Worker{Email: "member1@test.com", Name: "member1"}
+	member2 := &model.Worker{Email: "member2@test.com", Name: "member2"}
+	for _, w := range []*model.Worker{lead, member1, member2} {
+		db.CreateWorker(ctx, w)
+	}
+	db.SetTeamMember(ctx, 1, lead.ID, true)
+	db.SetTeamMember(ctx, 1, member1.ID, false)
+	db.SetTeamMember(ctx, 2, member2.ID, false)
+	shifts := []*model.Shift{}
+	for i, team := range []model.TeamID{1, 2} {
+		team := team
+		start := time.Date(2023, 5, 1+i, 8, 0, 0, 0, time.UTC)
+		shift := &model.Shift{StartTime: start, EndTime: start.Add(8 * time.Hour), Capacity: 1, Team: &team}
+		db.CreateShift(ctx, shift)
+		shifts = append(shifts, shift)
+	}
+	shift1, shift2 := model.ShiftToAPI(shifts[0]), model.ShiftToAPI(shifts[1])
+	shift1.BiddingClosed, shift2.BiddingClosed = nil, nil
+	moved := *shift1
+	moved.TeamId = shift2.TeamId
+	id1, id2 := *shift1.Id, *shift2.Id
+
+	teamAdmin := loginAs(e, "lead@test.com", testPassword)
+	role := loginAs(e, "role@test.com", testPassword)
+
+	// The team admin only sees their own team's members and shifts in
+	// listings.
+	workers := e.GET("/worker").WithHeader("Authorization", teamAdmin).
+		Expect().Status(http.StatusOK).JSON().Array()
+	workers.Length().IsEqual(2)
+	for _, w := range workers.Iter() {
+		w.Object().Value("email").NotEqual(member2.Email)
+	}
+	listed := e.GET("/shift").WithQuery("date", "2023-05-01").WithHeader("Authorization", teamAdmin).
+		Expect().Status(http.StatusOK).JSON().Array()
+	listed.Length().IsEqual(1)
+	listed.Value(0).Object().Value("id").IsEqual(id1)
+
+	// The team admin's request is made first, since the org-wide
+	// request may change things.
+	tests := []struct {
+		method string
+		path   string
+		query  string
+		body   interface{}
+		lead   int
+		role   int
+	}{
+		{"GET", "/teams/1/members", "", nil, http.StatusOK, http.StatusOK},
+		{"GET", "/teams/2/members", "", nil, http.StatusForbidden, http.StatusOK},
+		{"PUT", fmt.Sprintf("/teams/2/members/%d", member1.ID), "", api.TeamMember{}, http.StatusForbidden, http.StatusOK},
+		{"GET", "/worker", "team=1", nil, http.StatusOK, http.StatusOK},
+		{"GET", "/worker", "team=2", nil, http.StatusForbidden, http.StatusOK},
+		{"GET", fmt.Sprintf("/worker/%d", member1.ID), "", nil, http.StatusOK, http.StatusOK},
+		{"GET", fmt.Sprintf("/worker/%d", member2.ID), "", nil, http.StatusForbidden, http.StatusOK},
+		{"GET", fmt.Sprintf("/shift/%d", id1), "", nil, http.StatusOK, http.StatusOK},
+		{"GET", fmt.Sprintf("/shift/%d", id2), "", nil, http.StatusNotFound, http.StatusOK},
+		{"GET", "/shift", "team=2", nil, http.StatusForbidden, http.StatusOK},
+		{"PUT", "/shift", "", shift1, http.StatusOK, http.StatusOK},
+		{"PUT", "/shift", "", shift2, http.StatusForbidden, http.StatusOK},
+		{"POST", fmt.Sprintf("/shift/%d/assignment/%d", id1, member1.ID), "", nil, http.StatusNoContent, http.StatusBadRequest}, // Already assigned.
+		{"PUT", "/shift", "", &moved, http.StatusForbidden, http.StatusOK},
+		{"GET", fmt.Sprintf("/shift/%d/bids", id2), "", nil, http.StatusForbidden, http.StatusOK},
+		{"POST", fmt.Sprintf("/shift/%d/assignment/%d", id2, member2.ID), "", nil, http.StatusForbidden, http.StatusNoContent},
+	}
+	for _, test := range tests {
+		for _, auth := range []struct {
+			header string
+			status int
+		}{{teamAdmin, test.lead}, {role, test.role}} {
+			req := e.Request(test.method, test.path).WithQueryString(test.query).
+				WithHeader("Authorization", auth.header)
+			if test.body != nil {
+				req = req.WithJSON(test.body)
+			}
+			req.Expect().Status(auth.status)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/mailer"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Set up a server with an 11 hour minimum rest between shifts, and an
+// admin who worked the evening shift on Sunday 2023-05-07, just
+// before the week starting on Monday 2023-05-08.
+func contextSetup(t *testing.T) (*httpexpect.Expect, func(), store.Store) {
+	ctx := context.Background()
+	db, _ := store.NewMemoryStore()
+	for _, w := range []*model.Worker{
+		{Email: adminEmail, Name: adminName, IsAdmin: true, Password: adminPassword},
+		{Email: "worker@test.com", Name: "worker", Password: testPassword},
+	} {
+		db.CreateWorker(ctx, w)
+	}
+	err := db.CreateRuleSet(ctx, &model.RuleSet{
+		EffectiveFrom: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rules: []model.RuleConfig{
+			{Name: domain.RuleSameDay},
+			{Name: domain.RuleMinRest, Limit: 11},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sunday := &model.Shift{
+		StartTime: time.Date(2023, 5, 7, 16, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+		Capacity:  1,
+	}
+	db.CreateShift(ctx, sunday)
+	admin, _ := db.GetWorkerByEmail(ctx, adminEmail)
+	err = db.CreateShiftAssignment(ctx, admin.ID, sunday.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, srv := storeServerSetup(t, testConfig(), db, mailer.NewMemoryMailer())
+	return e, srv.Close, db
+}
+
+// Assignments just before the range being scheduled count when
+// checking the rules.
+func TestScheduleContext(t *testing.T) {
+	e, done, db := contextSetup(t)
+	defer done()
+	ctx := context.Background()
+
+	monday := &model.Shift{
+		StartTime: time.Date(2023, 5, 8, 8, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2023, 5, 8, 16, 0, 0, 0, time.UTC),
+		Capacity:  1,
+	}
+	db.CreateShift(ctx, monday)
+	worker, _ := db.GetWorkerByEmail(ctx, "worker@test.com")
+	auth := loginAs(e, adminEmail, adminPassword)
+
+	proposal := e.POST("/schedule/solve").WithHeader("Authorization", auth).
+		WithJSON(map[string]string{"start_date": "2023-05-08", "end_date": "2023-05-08"}).
+		Expect().Status(http.StatusOK).JSON().Object()
+	as := proposal.Value("assignments").Array()
+	as.Length().IsEqual(1)
+	as.Value(0).Object().Value("worker_id").IsEqual(worker.ID)
+	as.Value(0).Object().Value("shift_id").IsEqual(monday.ID)
+
+	// The admin can't work the shift at all, so only one of its two
+	// places can be filled.
+	monday.Capacity = 2
+	db.UpdateShift(ctx, monday)
+	e.GET("/schedule/feasibility").WithQuery("date", "2023-05-08").
+		WithHeader("Authorization", auth).
+		Expect().Status(http.StatusOK).JSON().Object().
+		Value("max_coverage").IsEqual(1)
+}
@@ -2,22 +2,28 @@ package server
 
 import (
 	"embed"
+	"fmt"
 	"io/fs"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/mailer"
 	"skybluetrades.net/work-planning-demo/store"
 )
 
 type server struct {
 	config *Config
 	db     store.Store
+	mail   mailer.Mailer
 }
 
-func NewServer(cfg *Config, db store.Store, staticFiles *embed.FS) *echo.Echo {
+func NewServer(cfg *Config, db store.Store,
+	mail mailer.Mailer, staticFiles *embed.FS) *echo.Echo {
 	// Retrieve API spec.
 	spec, err := api.GetSwagger()
 	if err != nil {
@@ -25,7 +31,7 @@ func NewServer(cfg *Config, db store.Store, staticFiles *embed.FS) *echo.Echo {
 	}
 
 	// Authentication/validation middleware.
-	mw, err := CreateMiddleware(spec, cfg)
+	mw, err := CreateMiddleware(spec, cfg, db)
 	if err != nil {
 		log.Fatalln("Error creating middleware: ", err)
 	}
@@ -33,6 +39,10 @@ func NewServer(cfg *Config, db store.Store, staticFiles *embed.FS) *echo.Echo {
 	// Set up Echo.
 	e := echo.New()
 	e.Debug = true
+	e.IPExtractor, err = ipExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalln("Error setting up trusted proxies: ", err)
+	}
 	e.Use(middleware.Logger())
 	e.Use(mw...)
 
@@ -40,6 +50,7 @@ func NewServer(cfg *Config, db store.Store, staticFiles *embed.FS) *echo.Echo {
 	srv := &server{
 		config: cfg,
 		db:     db,
+		mail:   mail,
 	}
 	api.RegisterHandlers(e, srv)
 
@@ -58,3 +69,27 @@ func NewServer(cfg *Config, db store.Store, staticFiles *embed.FS) *echo.Echo {
 	// Return Echo instance to main!
 	return e
 }
+
+// Client IP addresses (used to track failed logins) come from the
+// connection, unless there are trusted proxies in front of the server,
+// when they come from the X-Forwarded-For header. Echo's default is to
+// believe whatever client IP address headers a client sends.
+func ipExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, r := range strings.Split(trustedProxies, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(r))
+		if err != nil {
+			return nil, fmt.Errorf("bad trusted proxy range %q: %w", r, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
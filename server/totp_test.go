@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors from RFC 6238 (SHA-1), cut down to six digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).
+		EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, test := range tests {
+		code, err := totpCode(secret, test.time/totpPeriod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Errorf("code at %d: expected %s, got %s", test.time, test.code, code)
+		}
+	}
+
+	// Codes from the neighbouring time steps are accepted too.
+	now := time.Unix(1234567890, 0)
+	if step, ok := checkTOTP(secret, "005924", now.Add(totpPeriod*time.Second)); !ok || step != 1234567890/totpPeriod {
+		t.Error("code from previous time step not accepted")
+	}
+	if _, ok := checkTOTP(secret, "005924", now.Add(3*totpPeriod*time.Second)); ok {
+		t.Error("code from old time step accepted")
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"skybluetrades.net/work-planning-demo/api"
+	"skybluetrades.net/work-planning-demo/mailer"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+var errSerialization = errors.New("could not serialize access")
+
+// A store whose transactions fail with a serialization error the first
+// few times they look up a worker, and are retried as the Postgres
+// store retries them.
+type retryStore struct {
+	store.Store
+	failures int
+}
+
+func (s *retryStore) WithTx(ctx context.Context, fn func(store.Store) error) error {
+	for {
+		err := s.Store.WithTx(ctx, func(tx store.Store) error {
+			return fn(&retryTx{tx, s})
+		})
+		if !errors.Is(err, errSerialization) {
+			return err
+		}
+	}
+}
+
+type retryTx struct {
+	store.Store
+	outer *retryStore
+}
+
+func (tx *retryTx) GetWorkerById(ctx context.Context, id model.WorkerID) (*model.Worker, error) {
+	if tx.outer.failures > 0 {
+		tx.outer.failures--
+		return nil, errSerialization
+	}
+	return tx.Store.GetWorkerById(ctx, id)
+}
+
+// Store errors other than missing records aren't turned into error
+// responses inside transactions, so that the store can still retry
+// them.
+func TestTxRetry(t *testing.T) {
+	mem, _ := store.NewMemoryStore()
+	admin := &model.Worker{
+		Email:    adminEmail,
+		Name:     adminName,
+		IsAdmin:  true,
+		Password: adminPassword,
+	}
+	mem.CreateWorker(context.Background(), admin)
+	db := &retryStore{Store: mem}
+	cfg := testConfig()
+	cfg.AccessTokenLease = 60
+	e, srv := storeServerSetup(t, cfg, db, mailer.NewMemoryMailer())
+	defer srv.Close()
+
+	adminToken, _ := getTokens(e)
+	id := int64(admin.ID)
+	db.failures = 2
+	e.PUT("/worker").WithHeader("Authorization", "Bearer "+adminToken).
+		WithJSON(api.Worker{Id: &id, Email: adminEmail, Name: "renamed", IsAdmin: true}).
+		Expect().Status(http.StatusOK).JSON().Object().HasValue("name", "renamed")
+	assert.Zero(t, db.failures)
+
+	// Missing records are still reported as such.
+	unknown := int64(1000)
+	e.PUT("/worker").WithHeader("Authorization", "Bearer "+adminToken).
+		WithJSON(api.Worker{Id: &unknown, Email: "x@test.com", Name: "x"}).
+		Expect().Status(http.StatusBadRequest)
+}
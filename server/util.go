@@ -1,12 +1,14 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 	"skybluetrades.net/work-planning-demo/api"
 	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
 )
 
 // This function wraps sending of an error in the Error format, and
@@ -19,44 +21,129 @@ func sendError(ctx echo.Context, code int, message string) error {
 	return err
 }
 
+// Make a handler's store calls as a single unit of work: the function
+// gets a copy of the server that makes its calls through the store's
+// transaction. The function can be run more than once, so it mustn't
+// send a response: it should return errors as HTTP errors, and leave
+// sending the result until the transaction is done.
+func (s *server) inTx(ctx echo.Context, fn func(s *server) error) error {
+	return s.db.WithTx(ctx.Request().Context(), func(tx store.Store) error {
+		txs := *s
+		txs.db = tx
+		return fn(&txs)
+	})
+}
+
 func (s *server) currentWorker(ctx echo.Context) (*model.Worker, error) {
 	// We need the idea of the "current user" independent of the
 	// authentication flow. We can get the user ID associated with the
 	// JWT that was used for authentication from the token claims that
 	// we stored in the Echo context in the authentication middleware.
 	claims := ctx.Get("claims").(*JWTClaim)
-	worker, err := s.db.GetWorkerById(claims.ID)
+	worker, err := s.db.GetWorkerById(ctx.Request().Context(), claims.ID)
+	if errors.Is(err, store.ErrWorkerNotFound) {
+		// Return the error response rather than sending it, so that
+		// callers know not to carry on.
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Worker record not found")
+	}
 	if err != nil {
-		return nil, sendError(ctx, http.StatusNotFound, "Worker record not found")
+		return nil, err
 	}
 	return worker, nil
 }
 
+// Check whether the current user has a permission for every team.
+// Organisation admins have them all, and other workers get them from
+// their roles.
+func hasPermission(ctx echo.Context, perm model.Permission) bool {
+	claims, ok := ctx.Get("claims").(*JWTClaim)
+	return ok && claims.HasPermission(perm)
+}
+
+// Check whether the authentication middleware let the current user in
+// because of their permissions, rather than because they're a team
+// admin, so that they can act for every team.
+func orgWide(ctx echo.Context) bool {
+	ok, _ := ctx.Get("org_wide").(bool)
+	return ok
+}
+
+// The check functions below return (rather than send) error
+// responses, so that callers know not to carry on.
+
 func checkWorker(ctx echo.Context, w *api.Worker, needId bool) error {
 	if needId && w.Id == nil {
-		return sendError(ctx, http.StatusBadRequest, "Missing worker ID")
-	}
-	if w.Password == nil || len(*w.Password) == 0 {
-		return sendError(ctx, http.StatusBadRequest, "Missing password for worker")
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing worker ID")
 	}
+	// Passwords are optional: new workers can be created without one,
+	// and invited to set one themselves, and updates without one keep
+	// the worker's current password.
 	if strings.TrimSpace(w.Name) == "" {
-		return sendError(ctx, http.StatusBadRequest, "Missing name for worker")
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing name for worker")
 	}
 	if strings.TrimSpace(w.Email) == "" {
-		return sendError(ctx, http.StatusBadRequest, "Missing email for worker")
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing email for worker")
 	}
 	return nil
 }
 
 func checkShift(ctx echo.Context, s *api.Shift, needId bool) error {
 	if needId && s.Id == nil {
-		return sendError(ctx, http.StatusBadRequest, "Missing shift ID")
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing shift ID")
 	}
 	if s.Capacity <= 0 {
-		return sendError(ctx, http.StatusBadRequest, "Bad capacity value for shift")
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad capacity value for shift")
 	}
 	if s.StartTime.After(s.EndTime) {
-		return sendError(ctx, http.StatusBadRequest, "Bad time range for shift")
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad time range for shift")
+	}
+	if s.Requirements != nil {
+		skills := map[string]bool{}
+		reserved := int32(0)
+		for _, r := range *s.Requirements {
+			if r.Count <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "Bad count value for shift requirement")
+			}
+			skill := strings.TrimSpace(r.Skill)
+			if skill == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Missing skill for shift requirement")
+			}
+			if skills[skill] {
+				return echo.NewHTTPError(http.StatusBadRequest, "Duplicate skill in shift requirements")
+			}
+			skills[skill] = true
+			reserved += r.Count
+		}
+		if reserved > s.Capacity {
+			return echo.NewHTTPError(http.StatusBadRequest, "Shift requirements exceed shift capacity")
+		}
+	}
+	return nil
+}
+
+func checkShiftPreference(ctx echo.Context, p *api.ShiftPreference, needId bool) error {
+	if needId && p.Id == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing shift preference ID")
+	}
+	if p.ShiftId == nil && p.Weekday == nil && p.StartHour == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing shift or recurring pattern for shift preference")
+	}
+	if p.ShiftId != nil && (p.Weekday != nil || p.StartHour != nil) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Shift preference can't have both shift and recurring pattern")
+	}
+	return nil
+}
+
+func checkTimeOff(ctx echo.Context, t *api.TimeOff) error {
+	if !t.EndTime.After(t.StartTime) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad time range for time off request")
+	}
+	return nil
+}
+
+func checkShiftTemplate(ctx echo.Context, t *api.ShiftTemplate) error {
+	if t.Capacity <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad capacity value for shift template")
 	}
 	return nil
 }
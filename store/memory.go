@@ -1,11 +1,14 @@
 // `MemoryStore` is a simple in-memory database for workers and
 // shifts. It implements the `Store` interface, and includes some test
 // data setup via its `Migrate` method (called from the main program
-// during application startup).
+// during application startup). It can optionally persist its state to
+// disk: see memory_persist.go.
 
 package store
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -16,24 +19,104 @@ import (
 )
 
 type MemoryStore struct {
-	sync.RWMutex
-	lastWorkerID   model.WorkerID
-	lastShiftID    model.ShiftID
+	*memoryState
+
+	// Set in the view of the store that WithTx gives its function,
+	// which runs with the store already locked.
+	inTx bool
+}
+
+type memoryState struct {
+	mu sync.RWMutex
+	memoryValues
 	workers        map[model.WorkerID]*model.Worker
 	workersByEmail map[string]*model.Worker
 	shifts         map[model.ShiftID]*model.Shift
-	assignments    []model.ShiftAssignment
+	preferences    map[model.ShiftPreferenceID]*model.ShiftPreference
+	timeOff        map[model.TimeOffID]*model.TimeOff
+	ruleSets       map[model.RuleSetID]*model.RuleSet
+	swaps          map[model.SwapID]*model.Swap
+	bids           map[model.BidID]*model.Bid
+	templates      map[model.ShiftTemplateID]*model.ShiftTemplate
+	teams          map[model.TeamID]*model.Team
+	roles          map[model.RoleID]*model.Role
+	refreshTokens  map[string]*model.RefreshToken
+	passwordTokens map[string]*model.PasswordToken
+	twoFactor      map[model.WorkerID]*model.TwoFactor
+	loginAttempts  map[string]*model.LoginAttempts
+
+	// Snapshots and write-ahead log, if the store is persistent (see
+	// memory_persist.go).
+	persist   *persister
+	writeTime time.Time
+	replaying bool
+
+	// The functions that undo the changes of the running transaction,
+	// or of the running method in a persistent store, in the order the
+	// changes were made, and the write-ahead log entries to write if
+	// the transaction succeeds.
+	undo  []func()
+	txLog []*walEntry
+}
+
+// The parts of the store's state that are held by value: the last IDs
+// used, and the lists, which are only ever replaced or appended to,
+// never changed in place (though the records they point to can be).
+// They're small enough to save whole before each change in a
+// transaction.
+type memoryValues struct {
+	lastWorkerID     model.WorkerID
+	lastShiftID      model.ShiftID
+	lastPreferenceID model.ShiftPreferenceID
+	lastTimeOffID    model.TimeOffID
+	lastRuleSetID    model.RuleSetID
+	lastOverrideID   model.RuleOverrideID
+	lastSwapID       model.SwapID
+	lastBidID        model.BidID
+	lastTemplateID   model.ShiftTemplateID
+	lastTeamID       model.TeamID
+	lastRoleID       model.RoleID
+	lastLockoutID    model.LockoutID
+	assignments      []model.ShiftAssignment
+	overrides        []*model.RuleOverride
+	lockouts         []*model.Lockout
 }
 
 func NewMemoryStore() (Store, error) {
-	return &MemoryStore{
-		lastWorkerID:   0,
-		lastShiftID:    0,
+	return &MemoryStore{memoryState: &memoryState{
+		memoryValues: memoryValues{
+			lastWorkerID:     0,
+			lastShiftID:      0,
+			lastPreferenceID: 0,
+			lastTimeOffID:    0,
+			lastRuleSetID:    0,
+			lastOverrideID:   0,
+			lastSwapID:       0,
+			lastBidID:        0,
+			lastTemplateID:   0,
+			lastTeamID:       0,
+			lastRoleID:       0,
+			lastLockoutID:    0,
+			assignments:      []model.ShiftAssignment{},
+			overrides:        []*model.RuleOverride{},
+			lockouts:         []*model.Lockout{},
+		},
 		workers:        make(map[model.WorkerID]*model.Worker),
 		workersByEmail: make(map[string]*model.Worker),
 		shifts:         make(map[model.ShiftID]*model.Shift),
-		assignments:    []model.ShiftAssignment{},
-	}, nil
+		preferences:    make(map[model.ShiftPreferenceID]*model.ShiftPreference),
+		timeOff:        make(map[model.TimeOffID]*model.TimeOff),
+		ruleSets:       make(map[model.RuleSetID]*model.RuleSet),
+		swaps:          make(map[model.SwapID]*model.Swap),
+		bids:           make(map[model.BidID]*model.Bid),
+		templates:      make(map[model.ShiftTemplateID]*model.ShiftTemplate),
+		teams:          make(map[model.TeamID]*model.Team),
+		roles:          make(map[model.RoleID]*model.Role),
+		refreshTokens:  make(map[string]*model.RefreshToken),
+		passwordTokens: make(map[string]*model.PasswordToken),
+		twoFactor:      make(map[model.WorkerID]*model.TwoFactor),
+		loginAttempts:  make(map[string]*model.LoginAttempts),
+	}}, nil
 }
 
 func (s *MemoryStore) Migrate() {
@@ -42,7 +125,127 @@ func (s *MemoryStore) Migrate() {
 	}
 }
 
-func (s *MemoryStore) Authenticate(email string, password string) (*model.Worker, error) {
+// The store's methods lock it as they need to, except in a
+// transaction, where it's locked already.
+func (s *MemoryStore) Lock() {
+	if !s.inTx {
+		s.mu.Lock()
+	}
+}
+
+func (s *MemoryStore) Unlock() {
+	if !s.inTx {
+		s.mu.Unlock()
+	}
+}
+
+func (s *MemoryStore) RLock() {
+	if !s.inTx {
+		s.mu.RLock()
+	}
+}
+
+func (s *MemoryStore) RUnlock() {
+	if !s.inTx {
+		s.mu.RUnlock()
+	}
+}
+
+// WithTx holds the store lock while the function runs, so nothing
+// else sees the store part way through. Each change the function makes
+// saves what it changes first (see saveValue and setEntry), so that
+// the changes can be undone if the function fails, and its write-ahead
+// log entries are held back until it succeeds. Nested transactions
+// are undone on their own.
+func (s *MemoryStore) WithTx(ctx context.Context, fn func(tx Store) error) (err error) {
+	tx := s
+	if !s.inTx {
+		s.Lock()
+		defer s.Unlock()
+		tx = &MemoryStore{memoryState: s.memoryState, inTx: true}
+	}
+
+	mark, txLog := len(s.undo), s.txLog
+	s.txLog = nil
+	err = fn(tx)
+	if err != nil {
+		s.undoTo(mark)
+		s.txLog = txLog
+		return err
+	}
+	s.txLog = append(txLog, s.txLog...)
+	if s.inTx {
+		return nil
+	}
+
+	// The transaction's log entries are written together, and if they
+	// can't be, the transaction is undone.
+	entries := s.txLog
+	s.txLog = nil
+	if s.persist != nil && len(entries) > 0 {
+		err = s.persist.append(entries...)
+		if err != nil {
+			s.undoTo(0)
+			return fmt.Errorf("writing transaction to write-ahead log: %w", err)
+		}
+	}
+	s.undo = nil
+	return nil
+}
+
+// Undo the changes saved since a mark in the list of undo functions.
+func (s *MemoryStore) undoTo(mark int) {
+	for i := len(s.undo) - 1; i >= mark; i-- {
+		s.undo[i]()
+	}
+	s.undo = s.undo[:mark]
+}
+
+// Changes are saved so that they can be undone in a transaction, and
+// in a persistent store, where changes that can't be written to the
+// write-ahead log are undone.
+func (s *MemoryStore) saving() bool {
+	return s.inTx || s.persist != nil
+}
+
+// In a transaction, or in a persistent store, the store's methods save
+// each part of the state they change, just before changing it. Records
+// that are changed in place are saved with saveValue, and map entries
+// that are added, replaced or deleted are changed with setEntry and
+// deleteEntry. The values in memoryValues are saved by record.
+func saveValue[V any](s *MemoryStore, v *V) {
+	if !s.saving() {
+		return
+	}
+	old := *v
+	s.undo = append(s.undo, func() { *v = old })
+}
+
+func setEntry[K comparable, V any](s *MemoryStore, m map[K]*V, k K, v *V) {
+	saveEntry(s, m, k)
+	m[k] = v
+}
+
+func deleteEntry[K comparable, V any](s *MemoryStore, m map[K]*V, k K) {
+	saveEntry(s, m, k)
+	delete(m, k)
+}
+
+func saveEntry[K comparable, V any](s *MemoryStore, m map[K]*V, k K) {
+	if !s.saving() {
+		return
+	}
+	old, exists := m[k]
+	s.undo = append(s.undo, func() {
+		if exists {
+			m[k] = old
+		} else {
+			delete(m, k)
+		}
+	})
+}
+
+func (s *MemoryStore) Authenticate(ctx context.Context, email string, password string) (*model.Worker, error) {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -55,26 +258,333 @@ func (s *MemoryStore) Authenticate(email string, password string) (*model.Worker
 		return nil, err
 	}
 
-	retWorker := *s.workers[worker.ID]
-	return &retWorker, nil
+	return copyWorker(worker), nil
+}
+
+func (s *MemoryStore) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateRefreshToken", token)()
+
+	now := s.now()
+	for id, t := range s.refreshTokens {
+		if t.ExpiresAt.Before(now) {
+			deleteEntry(s, s.refreshTokens, id)
+		}
+	}
+
+	stored := *token
+	setEntry(s, s.refreshTokens, stored.ID, &stored)
+
+	return nil
+}
+
+func (s *MemoryStore) UseRefreshToken(ctx context.Context, id string) (_ *model.RefreshToken, err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UseRefreshToken", id)()
+
+	token, exists := s.refreshTokens[id]
+	if !exists {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	rtoken := *token
+	saveValue(s, token)
+	token.Used = true
+	return &rtoken, nil
+}
+
+func (s *MemoryStore) RevokeRefreshTokenFamily(ctx context.Context, family string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "RevokeRefreshTokenFamily", family)()
+
+	for _, t := range s.refreshTokens {
+		if t.Family == family {
+			saveValue(s, t)
+			t.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) RevokeWorkerRefreshTokens(ctx context.Context, workerId model.WorkerID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "RevokeWorkerRefreshTokens", workerId)()
+
+	if _, exists := s.workers[workerId]; !exists {
+		return ErrWorkerNotFound
+	}
+	for _, t := range s.refreshTokens {
+		if t.Worker == workerId {
+			saveValue(s, t)
+			t.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) RefreshTokenFamilyRevoked(ctx context.Context, family string) (bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, t := range s.refreshTokens {
+		if t.Family == family && t.Revoked {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *MemoryStore) CreatePasswordToken(ctx context.Context, token *model.PasswordToken) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreatePasswordToken", token)()
+
+	now := s.now()
+	for id, t := range s.passwordTokens {
+		if t.ExpiresAt.Before(now) {
+			deleteEntry(s, s.passwordTokens, id)
+		}
+	}
+
+	stored := *token
+	setEntry(s, s.passwordTokens, stored.ID, &stored)
+
+	return nil
+}
+
+func (s *MemoryStore) UsePasswordToken(ctx context.Context, id string,
+	purpose model.PasswordTokenPurpose) (_ *model.PasswordToken, err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UsePasswordToken", id, purpose)()
+
+	token, exists := s.passwordTokens[id]
+	if !exists || token.Used || token.Purpose != purpose || token.ExpiresAt.Before(s.now()) {
+		return nil, ErrPasswordTokenInvalid
+	}
+
+	saveValue(s, token)
+	token.Used = true
+	rtoken := *token
+	return &rtoken, nil
+}
+
+func (s *MemoryStore) SetWorkerPassword(ctx context.Context, id model.WorkerID, password string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	// Only the password hash goes into the write-ahead log.
+	hash := s.hashPassword(password)
+	defer s.record(&err, "SetWorkerPassword", id, hash)()
+
+	worker, exists := s.workers[id]
+	if !exists {
+		return ErrWorkerNotFound
+	}
+
+	saveValue(s, worker)
+	worker.Password = hash
+
+	return nil
+}
+
+func (s *MemoryStore) GetTwoFactor(ctx context.Context, workerId model.WorkerID) (*model.TwoFactor, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	tf, exists := s.twoFactor[workerId]
+	if !exists {
+		return nil, ErrTwoFactorNotFound
+	}
+
+	rtf := *tf
+	rtf.RecoveryCodes = copyList(tf.RecoveryCodes)
+	return &rtf, nil
+}
+
+func (s *MemoryStore) SetTwoFactor(ctx context.Context, tf *model.TwoFactor) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "SetTwoFactor", tf)()
+
+	if _, exists := s.workers[tf.Worker]; !exists {
+		return ErrWorkerNotFound
+	}
+
+	stored := *tf
+	stored.RecoveryCodes = copyList(tf.RecoveryCodes)
+	setEntry(s, s.twoFactor, stored.Worker, &stored)
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteTwoFactor(ctx context.Context, workerId model.WorkerID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteTwoFactor", workerId)()
+
+	if _, exists := s.twoFactor[workerId]; !exists {
+		return ErrTwoFactorNotFound
+	}
+	deleteEntry(s, s.twoFactor, workerId)
+
+	return nil
+}
+
+func (s *MemoryStore) RecordTOTPStep(ctx context.Context, workerId model.WorkerID, step int64) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "RecordTOTPStep", workerId, step)()
+
+	tf, exists := s.twoFactor[workerId]
+	if !exists {
+		return ErrTwoFactorCodeInvalid
+	}
+	if step <= tf.LastStep {
+		return ErrTwoFactorCodeInvalid
+	}
+	saveValue(s, tf)
+	tf.LastStep = step
+
+	return nil
+}
+
+func (s *MemoryStore) UseRecoveryCode(ctx context.Context, workerId model.WorkerID, hash string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UseRecoveryCode", workerId, hash)()
+
+	tf, exists := s.twoFactor[workerId]
+	if !exists {
+		return ErrTwoFactorCodeInvalid
+	}
+	i := slices.Index(tf.RecoveryCodes, hash)
+	if i < 0 {
+		return ErrTwoFactorCodeInvalid
+	}
+	saveValue(s, tf)
+	tf.RecoveryCodes = slices.Delete(slices.Clone(tf.RecoveryCodes), i, i+1)
+
+	return nil
+}
+
+func (s *MemoryStore) GetLoginAttempts(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	attempts, exists := s.loginAttempts[key]
+	if !exists {
+		return &model.LoginAttempts{Key: key}, nil
+	}
+
+	rattempts := *attempts
+	return &rattempts, nil
+}
+
+func (s *MemoryStore) RecordLoginFailure(ctx context.Context, key string,
+	window time.Duration) (_ *model.LoginAttempts, err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "RecordLoginFailure", key, window)()
+
+	now := s.now()
+	for k, a := range s.loginAttempts {
+		if a.LastFailure.Before(now.Add(-window)) && !a.Locked(now) {
+			deleteEntry(s, s.loginAttempts, k)
+		}
+	}
+	attempts, exists := s.loginAttempts[key]
+	if !exists {
+		attempts = &model.LoginAttempts{Key: key}
+		setEntry(s, s.loginAttempts, key, attempts)
+	}
+	saveValue(s, attempts)
+	if attempts.LastFailure.Before(now.Add(-window)) {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+
+	rattempts := *attempts
+	return &rattempts, nil
+}
+
+func (s *MemoryStore) LockLogin(ctx context.Context, key string, until time.Time) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "LockLogin", key, until)()
+
+	attempts, exists := s.loginAttempts[key]
+	if !exists {
+		attempts = &model.LoginAttempts{Key: key}
+		setEntry(s, s.loginAttempts, key, attempts)
+	}
+	saveValue(s, attempts)
+	attempts.LockedUntil = &until
+
+	return nil
+}
+
+func (s *MemoryStore) ClearLoginAttempts(ctx context.Context, key string) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "ClearLoginAttempts", key)()
+
+	deleteEntry(s, s.loginAttempts, key)
+
+	return nil
+}
+
+func (s *MemoryStore) CreateLockout(ctx context.Context, lockout *model.Lockout) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateLockout", lockout)()
+
+	stored := *lockout
+	s.lastLockoutID++
+	stored.ID = s.lastLockoutID
+	s.lockouts = append(s.lockouts, &stored)
+
+	lockout.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) GetLockouts(ctx context.Context) ([]*model.Lockout, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	lockouts := []*model.Lockout{}
+	for i := len(s.lockouts) - 1; i >= 0; i-- {
+		rlockout := *s.lockouts[i]
+		lockouts = append(lockouts, &rlockout)
+	}
+
+	return lockouts, nil
 }
 
-func (s *MemoryStore) GetWorkers() ([]*model.Worker, error) {
+func (s *MemoryStore) GetWorkers(ctx context.Context, teamId *model.TeamID) ([]*model.Worker, error) {
 	s.RLock()
-	s.RUnlock()
+	defer s.RUnlock()
 
-	workers := make([]*model.Worker, len(s.workers))
-	i := 0
+	workers := []*model.Worker{}
 	for _, w := range s.workers {
-		rworker := *w
-		workers[i] = &rworker
-		i++
+		if teamId != nil && !w.InTeam(teamId) {
+			continue
+		}
+		workers = append(workers, copyWorker(w))
 	}
 
+	slices.SortFunc(workers, func(a, b *model.Worker) bool { return a.ID < b.ID })
 	return workers, nil
 }
 
-func (s *MemoryStore) GetWorkerById(id model.WorkerID) (*model.Worker, error) {
+func (s *MemoryStore) GetWorkerById(ctx context.Context, id model.WorkerID) (*model.Worker, error) {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -83,70 +593,160 @@ func (s *MemoryStore) GetWorkerById(id model.WorkerID) (*model.Worker, error) {
 		return nil, ErrWorkerNotFound
 	}
 
-	rworker := *worker
-	return &rworker, nil
+	return copyWorker(worker), nil
 }
 
-func (s *MemoryStore) CreateWorker(worker *model.Worker) error {
+func (s *MemoryStore) GetWorkerByEmail(ctx context.Context, email string) (*model.Worker, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	stored := *worker
-	bcryptPassword, _ := bcrypt.GenerateFromPassword([]byte(worker.Password), 0)
-	stored.Password = string(bcryptPassword)
+	worker, exists := s.workersByEmail[email]
+	if !exists {
+		return nil, ErrUnknownWorkerEmail
+	}
+
+	return copyWorker(worker), nil
+}
+
+func (s *MemoryStore) CreateWorker(ctx context.Context, worker *model.Worker) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	stored := copyWorker(worker)
+	stored.Password = s.hashPassword(worker.Password)
+	// Only the password hash goes into the write-ahead log.
+	defer s.record(&err, "CreateWorker", stored)()
+
+	if _, exists := s.workersByEmail[stored.Email]; exists {
+		return ErrWorkerEmailExists
+	}
+
 	s.lastWorkerID++
 	stored.ID = s.lastWorkerID
-	s.workers[stored.ID] = &stored
-	s.workersByEmail[stored.Email] = &stored
+	setEntry(s, s.workers, stored.ID, stored)
+	setEntry(s, s.workersByEmail, stored.Email, stored)
 
 	worker.ID = stored.ID
 	return nil
 }
 
-func (s *MemoryStore) UpdateWorker(worker *model.Worker) error {
-	s.RLock()
-	defer s.RUnlock()
-
+func (s *MemoryStore) UpdateWorker(ctx context.Context, worker *model.Worker) (err error) {
+	s.Lock()
+	defer s.Unlock()
 	existing, exists := s.workers[worker.ID]
+	stored := copyWorker(worker)
+	stored.Password = s.hashPassword(worker.Password)
+	if exists && worker.Password == "" {
+		stored.Password = existing.Password
+	}
+	// Only the password hash goes into the write-ahead log.
+	defer s.record(&err, "UpdateWorker", stored)()
+
 	if !exists {
 		return ErrWorkerNotFound
 	}
+	if other, exists := s.workersByEmail[stored.Email]; exists && other.ID != stored.ID {
+		return ErrWorkerEmailExists
+	}
 
-	delete(s.workers, worker.ID)
-	delete(s.workersByEmail, existing.Email)
+	deleteEntry(s, s.workers, worker.ID)
+	deleteEntry(s, s.workersByEmail, existing.Email)
 
-	stored := *worker
-	s.workers[stored.ID] = &stored
-	s.workersByEmail[stored.Email] = &stored
+	setEntry(s, s.workers, stored.ID, stored)
+	setEntry(s, s.workersByEmail, stored.Email, stored)
 
 	return nil
 }
 
-func (s *MemoryStore) DeleteWorkerById(id model.WorkerID) error {
-	s.RLock()
-	defer s.RUnlock()
+// Deleting a worker deletes everything that refers to them, as the
+// database stores' foreign keys do.
+func (s *MemoryStore) DeleteWorkerById(ctx context.Context, id model.WorkerID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteWorkerById", id)()
 
 	existing, exists := s.workers[id]
 	if !exists {
 		return ErrWorkerNotFound
 	}
 
-	delete(s.workers, id)
-	delete(s.workersByEmail, existing.Email)
+	deleteEntry(s, s.workers, id)
+	deleteEntry(s, s.workersByEmail, existing.Email)
+
+	assignments := []model.ShiftAssignment{}
+	freed := []model.ShiftID{}
+	for _, a := range s.assignments {
+		if a.Worker != id {
+			assignments = append(assignments, a)
+		} else {
+			freed = append(freed, a.Shift)
+		}
+	}
+	s.assignments = assignments
+	for prefId, p := range s.preferences {
+		if p.Worker == id {
+			deleteEntry(s, s.preferences, prefId)
+		}
+	}
+	for timeOffId, t := range s.timeOff {
+		if t.Worker == id {
+			deleteEntry(s, s.timeOff, timeOffId)
+		}
+	}
+	overrides := []*model.RuleOverride{}
+	for _, o := range s.overrides {
+		if o.Worker != id {
+			overrides = append(overrides, o)
+		}
+	}
+	s.overrides = overrides
+	for swapId, sw := range s.swaps {
+		if sw.Offerer == id {
+			deleteEntry(s, s.swaps, swapId)
+		} else if sw.Taker != nil && *sw.Taker == id {
+			saveValue(s, sw)
+			sw.Taker = nil
+		}
+	}
+	for bidId, b := range s.bids {
+		if b.Worker == id {
+			deleteEntry(s, s.bids, bidId)
+		}
+	}
+	slices.Sort(freed)
+	for _, shiftId := range freed {
+		s.assignments = s.promoteWaitlist(s.assignments, shiftId)
+	}
+	for tokenId, t := range s.refreshTokens {
+		if t.Worker == id {
+			deleteEntry(s, s.refreshTokens, tokenId)
+		}
+	}
+	for tokenId, t := range s.passwordTokens {
+		if t.Worker == id {
+			deleteEntry(s, s.passwordTokens, tokenId)
+		}
+	}
+	deleteEntry(s, s.twoFactor, id)
+	for _, l := range s.lockouts {
+		if l.Worker != nil && *l.Worker == id {
+			saveValue(s, l)
+			l.Worker = nil
+		}
+	}
 
 	return nil
 }
 
-func (s *MemoryStore) GetShifts(
-	date *time.Time, span TimeSpan, workerId *model.WorkerID) ([]*model.Shift, error) {
+func (s *MemoryStore) GetShifts(ctx context.Context, date *time.Time, span TimeSpan,
+	workerId *model.WorkerID, teamId *model.TeamID) ([]*model.Shift, error) {
 	s.RLock()
-	s.RUnlock()
+	defer s.RUnlock()
 
 	// Calculate interval start and end from date and span.
 	var intStart, intEnd time.Time
 	includeAll := date == nil
 	if !includeAll {
-		intStart, intEnd = getSpanRange(date, span)
+		intStart, intEnd = SpanRange(date, span)
 	}
 
 	// If we're extracting shifts for a given worker, collect the
@@ -162,23 +762,39 @@ func (s *MemoryStore) GetShifts(
 
 	// Include only shifts in interval.
 	shifts := []*model.Shift{}
-	i := 0
 	for _, s := range s.shifts {
 		include := includeAll || s.StartTime.Before(intEnd) && s.EndTime.After(intStart)
 		if workerId != nil && include {
 			include = slices.Contains(assigned, s.ID)
 		}
+		if teamId != nil && include {
+			include = s.Team != nil && *s.Team == *teamId
+		}
 		if include {
-			rshift := *s
-			shifts = append(shifts, &rshift)
-			i++
+			shifts = append(shifts, copyShift(s))
+		}
+	}
+
+	slices.SortFunc(shifts, shiftBefore)
+	return shifts, nil
+}
+
+func (s *MemoryStore) GetShiftsInRange(ctx context.Context, start time.Time, end time.Time) ([]*model.Shift, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	shifts := []*model.Shift{}
+	for _, sh := range s.shifts {
+		if sh.StartTime.Before(end) && sh.EndTime.After(start) {
+			shifts = append(shifts, copyShift(sh))
 		}
 	}
 
+	slices.SortFunc(shifts, shiftBefore)
 	return shifts, nil
 }
 
-func (s *MemoryStore) GetShiftById(id model.ShiftID) (*model.Shift, error) {
+func (s *MemoryStore) GetShiftById(ctx context.Context, id model.ShiftID) (*model.Shift, error) {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -187,97 +803,1248 @@ func (s *MemoryStore) GetShiftById(id model.ShiftID) (*model.Shift, error) {
 		return nil, ErrShiftNotFound
 	}
 
-	rshift := *shift
-	return &rshift, nil
+	return copyShift(shift), nil
 }
 
-func (s *MemoryStore) CreateShift(shift *model.Shift) error {
-	s.RLock()
-	defer s.RUnlock()
+func (s *MemoryStore) CreateShift(ctx context.Context, shift *model.Shift) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShift", shift)()
 
-	stored := *shift
+	stored := copyShift(shift)
 	s.lastShiftID++
 	stored.ID = s.lastShiftID
-	s.shifts[stored.ID] = &stored
+	stored.BiddingClosed = false
+	setEntry(s, s.shifts, stored.ID, stored)
 
 	shift.ID = stored.ID
 	return nil
 }
 
-func (s *MemoryStore) UpdateShift(shift *model.Shift) error {
-	s.RLock()
-	defer s.RUnlock()
+func (s *MemoryStore) CreateShifts(ctx context.Context, shifts []*model.Shift) (_ []*model.Shift, err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShifts", shifts)()
 
-	_, exists := s.shifts[shift.ID]
-	if !exists {
-		return ErrShiftNotFound
+	type shiftKey struct {
+		start, end time.Time
+		team       model.TeamID
+	}
+	keyOf := func(sh *model.Shift) shiftKey {
+		key := shiftKey{start: sh.StartTime.UTC(), end: sh.EndTime.UTC()}
+		if sh.Team != nil {
+			key.team = *sh.Team
+		}
+		return key
+	}
+	existing := map[shiftKey]model.ShiftID{}
+	for _, sh := range s.shifts {
+		existing[keyOf(sh)] = sh.ID
 	}
 
-	delete(s.shifts, shift.ID)
+	created := []*model.Shift{}
+	for _, shift := range shifts {
+		key := keyOf(shift)
+		if id, exists := existing[key]; exists {
+			shift.ID = id
+			continue
+		}
 
-	stored := *shift
-	s.shifts[stored.ID] = &stored
+		stored := copyShift(shift)
+		s.lastShiftID++
+		stored.ID = s.lastShiftID
+		stored.BiddingClosed = false
+		setEntry(s, s.shifts, stored.ID, stored)
+		existing[key] = stored.ID
 
-	return nil
+		shift.ID = stored.ID
+		created = append(created, shift)
+	}
+
+	return created, nil
 }
 
-func (s *MemoryStore) DeleteShiftById(id model.ShiftID) error {
-	s.RLock()
-	defer s.RUnlock()
+func (s *MemoryStore) UpdateShift(ctx context.Context, shift *model.Shift) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UpdateShift", shift)()
 
-	_, exists := s.shifts[id]
+	existing, exists := s.shifts[shift.ID]
 	if !exists {
 		return ErrShiftNotFound
 	}
 
-	delete(s.shifts, id)
+	stored := copyShift(shift)
+	stored.BiddingClosed = existing.BiddingClosed
+	setEntry(s, s.shifts, shift.ID, stored)
 
 	return nil
 }
 
-func (s *MemoryStore) CreateShiftAssignment(
-	workerId model.WorkerID, shiftId model.ShiftID) error {
-	s.RLock()
-	defer s.RUnlock()
+func (s *MemoryStore) DeleteShiftById(ctx context.Context, id model.ShiftID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteShiftById", id)()
 
-	shift, exists := s.shifts[shiftId]
+	_, exists := s.shifts[id]
 	if !exists {
 		return ErrShiftNotFound
 	}
 
-	existing := 0
+	s.deleteShift(id)
+
+	return nil
+}
+
+// Delete a shift and everything that refers to it, as the database
+// stores' foreign keys do. The caller must hold the store lock.
+func (s *MemoryStore) deleteShift(id model.ShiftID) {
+	deleteEntry(s, s.shifts, id)
+
+	assignments := []model.ShiftAssignment{}
+	for _, a := range s.assignments {
+		if a.Shift != id {
+			assignments = append(assignments, a)
+		}
+	}
+	s.assignments = assignments
+	for prefId, p := range s.preferences {
+		if p.Shift != nil && *p.Shift == id {
+			deleteEntry(s, s.preferences, prefId)
+		}
+	}
+	overrides := []*model.RuleOverride{}
+	for _, o := range s.overrides {
+		if o.Shift != id {
+			overrides = append(overrides, o)
+		}
+	}
+	s.overrides = overrides
+	for swapId, sw := range s.swaps {
+		if sw.Shift == id {
+			deleteEntry(s, s.swaps, swapId)
+		} else if sw.CounterShift != nil && *sw.CounterShift == id {
+			saveValue(s, sw)
+			sw.CounterShift = nil
+		}
+	}
+	for bidId, b := range s.bids {
+		if b.Shift == id {
+			deleteEntry(s, s.bids, bidId)
+		}
+	}
+}
+
+func (s *MemoryStore) GetTeams(ctx context.Context) ([]*model.Team, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	teams := []*model.Team{}
+	for _, t := range s.teams {
+		rteam := *t
+		teams = append(teams, &rteam)
+	}
+	slices.SortFunc(teams, func(a, b *model.Team) bool { return a.ID < b.ID })
+
+	return teams, nil
+}
+
+func (s *MemoryStore) GetTeamById(ctx context.Context, id model.TeamID) (*model.Team, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	team, exists := s.teams[id]
+	if !exists {
+		return nil, ErrTeamNotFound
+	}
+
+	rteam := *team
+	return &rteam, nil
+}
+
+func (s *MemoryStore) CreateTeam(ctx context.Context, team *model.Team) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateTeam", team)()
+
+	stored := *team
+	s.lastTeamID++
+	stored.ID = s.lastTeamID
+	setEntry(s, s.teams, stored.ID, &stored)
+
+	team.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) DeleteTeamById(ctx context.Context, id model.TeamID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteTeamById", id)()
+
+	if _, exists := s.teams[id]; !exists {
+		return ErrTeamNotFound
+	}
+
+	deleteEntry(s, s.teams, id)
+	for shiftId, sh := range s.shifts {
+		if sh.Team != nil && *sh.Team == id {
+			s.deleteShift(shiftId)
+		}
+	}
+	for templateId, t := range s.templates {
+		if t.Team != nil && *t.Team == id {
+			deleteEntry(s, s.templates, templateId)
+		}
+	}
+	for _, w := range s.workers {
+		teams := []model.Membership{}
+		for _, m := range w.Teams {
+			if m.Team != id {
+				teams = append(teams, m)
+			}
+		}
+		if len(teams) != len(w.Teams) {
+			saveValue(s, w)
+			w.Teams = teams
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) SetTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID, isAdmin bool) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "SetTeamMember", teamId, workerId, isAdmin)()
+
+	if _, exists := s.teams[teamId]; !exists {
+		return ErrTeamNotFound
+	}
+	worker, exists := s.workers[workerId]
+	if !exists {
+		return ErrWorkerNotFound
+	}
+
+	// Worker records are copied on the way out of the store, so the
+	// membership list is replaced rather than updated in place.
+	teams := slices.Clone(worker.Teams)
+	i := slices.IndexFunc(teams, func(m model.Membership) bool { return m.Team == teamId })
+	if i < 0 {
+		teams = append(teams, model.Membership{Team: teamId, IsAdmin: isAdmin})
+	} else {
+		teams[i].IsAdmin = isAdmin
+	}
+	saveValue(s, worker)
+	worker.Teams = teams
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteTeamMember", teamId, workerId)()
+
+	worker, exists := s.workers[workerId]
+	if !exists {
+		return ErrWorkerNotFound
+	}
+	i := slices.IndexFunc(worker.Teams, func(m model.Membership) bool { return m.Team == teamId })
+	if i < 0 {
+		return ErrTeamNotFound
+	}
+	saveValue(s, worker)
+	worker.Teams = slices.Delete(slices.Clone(worker.Teams), i, i+1)
+
+	return nil
+}
+
+func (s *MemoryStore) GetRoles(ctx context.Context) ([]*model.Role, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	roles := []*model.Role{}
+	for _, r := range s.roles {
+		rrole := *r
+		rrole.Permissions = copyList(r.Permissions)
+		roles = append(roles, &rrole)
+	}
+	slices.SortFunc(roles, func(a, b *model.Role) bool { return a.ID < b.ID })
+
+	return roles, nil
+}
+
+func (s *MemoryStore) GetRoleById(ctx context.Context, id model.RoleID) (*model.Role, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	role, exists := s.roles[id]
+	if !exists {
+		return nil, ErrRoleNotFound
+	}
+
+	rrole := *role
+	rrole.Permissions = copyList(role.Permissions)
+	return &rrole, nil
+}
+
+func (s *MemoryStore) CreateRole(ctx context.Context, role *model.Role) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateRole", role)()
+
+	stored := *role
+	stored.Permissions = copyList(role.Permissions)
+	s.lastRoleID++
+	stored.ID = s.lastRoleID
+	setEntry(s, s.roles, stored.ID, &stored)
+
+	role.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) UpdateRole(ctx context.Context, role *model.Role) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UpdateRole", role)()
+
+	if _, exists := s.roles[role.ID]; !exists {
+		return ErrRoleNotFound
+	}
+
+	stored := *role
+	stored.Permissions = copyList(role.Permissions)
+	setEntry(s, s.roles, stored.ID, &stored)
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteRoleById(ctx context.Context, id model.RoleID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteRoleById", id)()
+
+	if _, exists := s.roles[id]; !exists {
+		return ErrRoleNotFound
+	}
+
+	deleteEntry(s, s.roles, id)
+	for _, w := range s.workers {
+		if i := slices.Index(w.Roles, id); i >= 0 {
+			saveValue(s, w)
+			w.Roles = slices.Delete(slices.Clone(w.Roles), i, i+1)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) GetShiftTemplates(ctx context.Context) ([]*model.ShiftTemplate, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	templates := []*model.ShiftTemplate{}
+	for _, t := range s.templates {
+		rt := *t
+		templates = append(templates, &rt)
+	}
+
+	slices.SortFunc(templates, func(a, b *model.ShiftTemplate) bool { return a.ID < b.ID })
+	return templates, nil
+}
+
+func (s *MemoryStore) GetShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) (*model.ShiftTemplate, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	t, exists := s.templates[id]
+	if !exists {
+		return nil, ErrShiftTemplateNotFound
+	}
+
+	rt := *t
+	return &rt, nil
+}
+
+func (s *MemoryStore) CreateShiftTemplate(ctx context.Context, template *model.ShiftTemplate) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShiftTemplate", template)()
+
+	stored := *template
+	s.lastTemplateID++
+	stored.ID = s.lastTemplateID
+	setEntry(s, s.templates, stored.ID, &stored)
+
+	template.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) DeleteShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteShiftTemplateById", id)()
+
+	if _, exists := s.templates[id]; !exists {
+		return ErrShiftTemplateNotFound
+	}
+
+	deleteEntry(s, s.templates, id)
+
+	return nil
+}
+
+func (s *MemoryStore) GetShiftAssignmentsInRange(ctx context.Context,
+	start time.Time, end time.Time) ([]model.ShiftAssignment, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	assignments := []model.ShiftAssignment{}
 	for _, a := range s.assignments {
+		sh, exists := s.shifts[a.Shift]
+		if exists && sh.StartTime.Before(end) && sh.EndTime.After(start) {
+			assignments = append(assignments, a)
+		}
+	}
+
+	return assignments, nil
+}
+
+func (s *MemoryStore) CreateShiftAssignment(ctx context.Context,
+	workerId model.WorkerID, shiftId model.ShiftID, override *model.RuleOverride) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShiftAssignment", workerId, shiftId, override)()
+
+	assignments, soft, err := s.addShiftAssignment(s.assignments, workerId, shiftId, override != nil)
+	if err != nil {
+		return err
+	}
+
+	s.assignments = assignments
+	s.recordOverride(override, workerId, shiftId, soft)
+	return nil
+}
+
+func (s *MemoryStore) CreateShiftAssignments(ctx context.Context, assignments []model.ShiftAssignment) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShiftAssignments", assignments)()
+
+	// Check each new assignment against the existing ones plus those
+	// already accepted from the list, and only update the store if
+	// they're all OK.
+	updated := slices.Clone(s.assignments)
+	for _, a := range assignments {
+		var err error
+		updated, _, err = s.addShiftAssignment(updated, a.Worker, a.Shift, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.assignments = updated
+	return nil
+}
+
+// Check a new shift assignment against a list of assignments and
+// return the list with the new assignment added, plus any soft rule
+// violations, which are only allowed if allowSoft is set. The caller
+// must hold the store lock.
+func (s *MemoryStore) addShiftAssignment(assignments []model.ShiftAssignment,
+	workerId model.WorkerID, shiftId model.ShiftID,
+	allowSoft bool) ([]model.ShiftAssignment, []*domain.Violation, error) {
+	if _, exists := s.workers[workerId]; !exists {
+		return nil, nil, ErrWorkerNotFound
+	}
+	shift, exists := s.shifts[shiftId]
+	if !exists {
+		return nil, nil, ErrShiftNotFound
+	}
+	if !s.workers[workerId].InTeam(shift.Team) {
+		return nil, nil, ErrNotTeamMember
+	}
+
+	// Checked before the rules, which may not stop it.
+	for _, a := range assignments {
+		if a.Worker == workerId && a.Shift == shiftId {
+			return nil, nil, ErrAlreadyAssigned
+		}
+	}
+
+	staff := []*model.Worker{s.workers[workerId]}
+	shifts := []*model.Shift{}
+	for _, a := range assignments {
 		if a.Shift == shiftId {
-			existing++
+			w, ok := s.workers[a.Worker]
+			if !ok {
+				w = &model.Worker{ID: a.Worker}
+			}
+			staff = append(staff, w)
+		}
+		if a.Worker == workerId {
+			if sh, ok := s.shifts[a.Shift]; ok {
+				shifts = append(shifts, sh)
+			}
 		}
 	}
-	if existing >= shift.Capacity {
-		return ErrShiftAtCapacity
+	if len(staff) > shift.Capacity {
+		return nil, nil, ErrShiftAtCapacity
+	}
+	if !domain.Staffable(shift, staff) {
+		return nil, nil, ErrRequirementsUnmet
 	}
 
-	shifts, err := s.GetShifts(nil, WeekSpan, &workerId)
+	for _, t := range s.timeOff {
+		if t.Worker == workerId && t.Status == model.TimeOffApproved && t.Overlaps(shift) {
+			return nil, nil, ErrWorkerOnLeave
+		}
+	}
+
+	rules := domain.DefaultRules
+	if ruleSet := s.ruleSetAt(s.now()); ruleSet != nil {
+		var err error
+		rules, err = domain.NewRuleSet(ruleSet.Rules)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	soft := []*domain.Violation{}
+	for _, v := range rules.Violations(shifts, shift) {
+		if v.Hard || !allowSoft {
+			return nil, nil, &RuleViolationError{Violation: v}
+		}
+		soft = append(soft, v)
+	}
+
+	return append(assignments, model.ShiftAssignment{Worker: workerId, Shift: shiftId}), soft, nil
+}
+
+// Record an admin override if any soft rules were broken. The caller
+// must hold the store lock.
+func (s *MemoryStore) recordOverride(override *model.RuleOverride,
+	workerId model.WorkerID, shiftId model.ShiftID, soft []*domain.Violation) {
+	if override == nil || len(soft) == 0 {
+		return
+	}
+
+	s.lastOverrideID++
+	override.ID = s.lastOverrideID
+	override.Worker = workerId
+	override.Shift = shiftId
+	override.Violations = describeViolations(soft)
+	override.CreatedAt = s.now()
+	stored := *override
+	s.overrides = append(s.overrides, &stored)
+}
+
+func (s *MemoryStore) DeleteShiftAssignment(ctx context.Context,
+	workerId model.WorkerID, shiftId model.ShiftID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteShiftAssignment", workerId, shiftId)()
+
+	assignments, err := removeShiftAssignment(slices.Clone(s.assignments), workerId, shiftId)
 	if err != nil {
-		return ErrRetrievingWorkerShifts
+		return err
 	}
 
-	if !domain.NewShiftAssignmentOK(shifts, shift) {
-		return ErrTwoShiftsSameDay
+	s.assignments = s.promoteWaitlist(assignments, shiftId)
+	return nil
+}
+
+func (s *MemoryStore) MoveShiftAssignment(ctx context.Context, workerId model.WorkerID,
+	from model.ShiftID, to model.ShiftID, override *model.RuleOverride) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "MoveShiftAssignment", workerId, from, to, override)()
+
+	updated, err := removeShiftAssignment(slices.Clone(s.assignments), workerId, from)
+	if err != nil {
+		return err
+	}
+	updated, soft, err := s.addShiftAssignment(updated, workerId, to, override != nil)
+	if err != nil {
+		return err
 	}
 
-	s.assignments = append(s.assignments, model.ShiftAssignment{Worker: workerId, Shift: shiftId})
+	s.assignments = s.promoteWaitlist(updated, from)
+	s.recordOverride(override, workerId, to, soft)
 	return nil
 }
 
-func (s *MemoryStore) DeleteShiftAssignment(
-	workerId model.WorkerID, shiftId model.ShiftID) error {
+func (s *MemoryStore) ReplaceShiftAssignments(ctx context.Context,
+	remove []model.ShiftAssignment, add []model.ShiftAssignment) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "ReplaceShiftAssignments", remove, add)()
+
+	// As for CreateShiftAssignments, work on a copy so that nothing
+	// changes unless all the removals and additions are OK.
+	updated := slices.Clone(s.assignments)
+	for _, a := range remove {
+		var err error
+		updated, err = removeShiftAssignment(updated, a.Worker, a.Shift)
+		if err != nil {
+			return err
+		}
+	}
+	for _, a := range add {
+		var err error
+		updated, _, err = s.addShiftAssignment(updated, a.Worker, a.Shift, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The additions get first pick of the places freed by the removals,
+	// and waitlisted workers get the rest.
+	for _, a := range remove {
+		updated = s.promoteWaitlist(updated, a.Shift)
+	}
+
+	s.assignments = updated
+	return nil
+}
+
+func (s *MemoryStore) GetRuleOverrides(ctx context.Context, shiftId *model.ShiftID) ([]*model.RuleOverride, error) {
 	s.RLock()
 	defer s.RUnlock()
 
-	pos := slices.Index(s.assignments, model.ShiftAssignment{Worker: workerId, Shift: shiftId})
+	overrides := []*model.RuleOverride{}
+	for _, o := range s.overrides {
+		if shiftId == nil || o.Shift == *shiftId {
+			ro := *o
+			overrides = append(overrides, &ro)
+		}
+	}
+
+	return overrides, nil
+}
+
+// Return a list of assignments with one assignment removed.
+func removeShiftAssignment(assignments []model.ShiftAssignment,
+	workerId model.WorkerID, shiftId model.ShiftID) ([]model.ShiftAssignment, error) {
+	pos := slices.Index(assignments, model.ShiftAssignment{Worker: workerId, Shift: shiftId})
 	if pos == -1 {
+		return nil, ErrShiftAssignmentNotFound
+	}
+
+	return slices.Delete(assignments, pos, pos+1), nil
+}
+
+func (s *MemoryStore) GetShiftPreferences(ctx context.Context, workerId *model.WorkerID) ([]*model.ShiftPreference, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	prefs := []*model.ShiftPreference{}
+	for _, p := range s.preferences {
+		if workerId == nil || p.Worker == *workerId {
+			rpref := *p
+			prefs = append(prefs, &rpref)
+		}
+	}
+
+	slices.SortFunc(prefs, func(a, b *model.ShiftPreference) bool { return a.ID < b.ID })
+	return prefs, nil
+}
+
+func (s *MemoryStore) GetShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) (*model.ShiftPreference, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	pref, exists := s.preferences[id]
+	if !exists {
+		return nil, ErrShiftPreferenceNotFound
+	}
+
+	rpref := *pref
+	return &rpref, nil
+}
+
+func (s *MemoryStore) CreateShiftPreference(ctx context.Context, pref *model.ShiftPreference) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateShiftPreference", pref)()
+
+	if _, exists := s.workers[pref.Worker]; !exists {
+		return ErrWorkerNotFound
+	}
+	if pref.Shift != nil {
+		if _, exists := s.shifts[*pref.Shift]; !exists {
+			return ErrShiftNotFound
+		}
+	}
+
+	stored := *pref
+	s.lastPreferenceID++
+	stored.ID = s.lastPreferenceID
+	setEntry(s, s.preferences, stored.ID, &stored)
+
+	pref.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) UpdateShiftPreference(ctx context.Context, pref *model.ShiftPreference) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UpdateShiftPreference", pref)()
+
+	if _, exists := s.preferences[pref.ID]; !exists {
+		return ErrShiftPreferenceNotFound
+	}
+	if pref.Shift != nil {
+		if _, exists := s.shifts[*pref.Shift]; !exists {
+			return ErrShiftNotFound
+		}
+	}
+
+	stored := *pref
+	setEntry(s, s.preferences, stored.ID, &stored)
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteShiftPreferenceById", id)()
+
+	if _, exists := s.preferences[id]; !exists {
+		return ErrShiftPreferenceNotFound
+	}
+
+	deleteEntry(s, s.preferences, id)
+
+	return nil
+}
+
+func (s *MemoryStore) GetTimeOff(ctx context.Context, workerId *model.WorkerID, status *model.TimeOffStatus) ([]*model.TimeOff, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	timeOff := []*model.TimeOff{}
+	for _, t := range s.timeOff {
+		if workerId != nil && t.Worker != *workerId {
+			continue
+		}
+		if status != nil && t.Status != *status {
+			continue
+		}
+		rt := *t
+		timeOff = append(timeOff, &rt)
+	}
+
+	slices.SortFunc(timeOff, func(a, b *model.TimeOff) bool {
+		return a.StartTime.Before(b.StartTime)
+	})
+	return timeOff, nil
+}
+
+func (s *MemoryStore) GetTimeOffById(ctx context.Context, id model.TimeOffID) (*model.TimeOff, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	t, exists := s.timeOff[id]
+	if !exists {
+		return nil, ErrTimeOffNotFound
+	}
+
+	rt := *t
+	return &rt, nil
+}
+
+func (s *MemoryStore) CreateTimeOff(ctx context.Context, timeOff *model.TimeOff) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateTimeOff", timeOff)()
+
+	if _, exists := s.workers[timeOff.Worker]; !exists {
+		return ErrWorkerNotFound
+	}
+
+	stored := *timeOff
+	s.lastTimeOffID++
+	stored.ID = s.lastTimeOffID
+	setEntry(s, s.timeOff, stored.ID, &stored)
+
+	timeOff.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) UpdateTimeOffStatus(ctx context.Context, id model.TimeOffID, status model.TimeOffStatus) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UpdateTimeOffStatus", id, status)()
+
+	t, exists := s.timeOff[id]
+	if !exists {
+		return ErrTimeOffNotFound
+	}
+
+	saveValue(s, t)
+	t.Status = status
+	return nil
+}
+
+func (s *MemoryStore) DeleteTimeOffById(ctx context.Context, id model.TimeOffID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteTimeOffById", id)()
+
+	if _, exists := s.timeOff[id]; !exists {
+		return ErrTimeOffNotFound
+	}
+
+	deleteEntry(s, s.timeOff, id)
+
+	return nil
+}
+
+func (s *MemoryStore) GetRuleSets(ctx context.Context) ([]*model.RuleSet, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	ruleSets := []*model.RuleSet{}
+	for _, rs := range s.ruleSets {
+		ruleSets = append(ruleSets, copyRuleSet(rs))
+	}
+
+	slices.SortFunc(ruleSets, ruleSetBefore)
+	return ruleSets, nil
+}
+
+func (s *MemoryStore) GetRuleSetById(ctx context.Context, id model.RuleSetID) (*model.RuleSet, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	rs, exists := s.ruleSets[id]
+	if !exists {
+		return nil, ErrRuleSetNotFound
+	}
+
+	return copyRuleSet(rs), nil
+}
+
+func (s *MemoryStore) GetRuleSetAt(ctx context.Context, t time.Time) (*model.RuleSet, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	rs := s.ruleSetAt(t)
+	if rs == nil {
+		return nil, ErrRuleSetNotFound
+	}
+
+	return copyRuleSet(rs), nil
+}
+
+// Find the rule set in effect at a given time, or nil if there isn't
+// one. The caller must hold the store lock.
+func (s *MemoryStore) ruleSetAt(t time.Time) *model.RuleSet {
+	var current *model.RuleSet
+	for _, rs := range s.ruleSets {
+		if rs.EffectiveFrom.After(t) {
+			continue
+		}
+		if current == nil || ruleSetBefore(current, rs) {
+			current = rs
+		}
+	}
+	return current
+}
+
+func (s *MemoryStore) CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateRuleSet", ruleSet)()
+
+	stored := copyRuleSet(ruleSet)
+	s.lastRuleSetID++
+	stored.ID = s.lastRuleSetID
+	setEntry(s, s.ruleSets, stored.ID, stored)
+
+	ruleSet.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) DeleteRuleSetById(ctx context.Context, id model.RuleSetID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteRuleSetById", id)()
+
+	if _, exists := s.ruleSets[id]; !exists {
+		return ErrRuleSetNotFound
+	}
+
+	deleteEntry(s, s.ruleSets, id)
+
+	return nil
+}
+
+// Copies of the records held by the store, so that callers can't
+// change them. Lists are always copied to non-nil lists, so that empty
+// lists look the same as those from the database stores.
+func copyList[T any](list []T) []T {
+	return append([]T{}, list...)
+}
+
+func copyWorker(w *model.Worker) *model.Worker {
+	c := *w
+	c.Skills = copyList(w.Skills)
+	c.Teams = copyList(w.Teams)
+	c.Roles = copyList(w.Roles)
+	return &c
+}
+
+func copyShift(sh *model.Shift) *model.Shift {
+	c := *sh
+	c.Requirements = copyList(sh.Requirements)
+	return &c
+}
+
+// Order shifts by start time, then by ID.
+func shiftBefore(a, b *model.Shift) bool {
+	if !a.StartTime.Equal(b.StartTime) {
+		return a.StartTime.Before(b.StartTime)
+	}
+	return a.ID < b.ID
+}
+
+func copyRuleSet(rs *model.RuleSet) *model.RuleSet {
+	c := *rs
+	c.Rules = copyList(rs.Rules)
+	return &c
+}
+
+// Order rule sets by effective date, then by ID.
+func ruleSetBefore(a, b *model.RuleSet) bool {
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+		return a.EffectiveFrom.Before(b.EffectiveFrom)
+	}
+	return a.ID < b.ID
+}
+
+func (s *MemoryStore) GetSwaps(ctx context.Context, workerId *model.WorkerID, status *model.SwapStatus) ([]*model.Swap, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	swaps := []*model.Swap{}
+	for _, sw := range s.swaps {
+		if workerId != nil && sw.Offerer != *workerId && (sw.Taker == nil || *sw.Taker != *workerId) {
+			continue
+		}
+		if status != nil && sw.Status != *status {
+			continue
+		}
+		rsw := *sw
+		swaps = append(swaps, &rsw)
+	}
+
+	slices.SortFunc(swaps, func(a, b *model.Swap) bool { return a.ID < b.ID })
+	return swaps, nil
+}
+
+func (s *MemoryStore) GetSwapById(ctx context.Context, id model.SwapID) (*model.Swap, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	sw, exists := s.swaps[id]
+	if !exists {
+		return nil, ErrSwapNotFound
+	}
+
+	rsw := *sw
+	return &rsw, nil
+}
+
+func (s *MemoryStore) CreateSwap(ctx context.Context, swap *model.Swap) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateSwap", swap)()
+
+	if !slices.Contains(s.assignments, model.ShiftAssignment{Worker: swap.Offerer, Shift: swap.Shift}) {
 		return ErrShiftAssignmentNotFound
 	}
 
-	s.assignments = slices.Delete(s.assignments, pos, pos)
+	stored := *swap
+	s.lastSwapID++
+	stored.ID = s.lastSwapID
+	setEntry(s, s.swaps, stored.ID, &stored)
+
+	swap.ID = stored.ID
+	return nil
+}
+
+func (s *MemoryStore) UpdateSwap(ctx context.Context, swap *model.Swap) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "UpdateSwap", swap)()
+
+	if _, exists := s.swaps[swap.ID]; !exists {
+		return ErrSwapNotFound
+	}
+
+	stored := *swap
+	setEntry(s, s.swaps, stored.ID, &stored)
 	return nil
 }
+
+func (s *MemoryStore) CheckSwap(ctx context.Context, swap *model.Swap) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	_, err := s.applySwap(swap)
+	return err
+}
+
+func (s *MemoryStore) ExecuteSwap(ctx context.Context, id model.SwapID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "ExecuteSwap", id)()
+
+	sw, exists := s.swaps[id]
+	if !exists {
+		return ErrSwapNotFound
+	}
+	if sw.Status != model.SwapProposed && sw.Status != model.SwapAccepted {
+		return ErrSwapNotReady
+	}
+
+	updated, err := s.applySwap(sw)
+	if err != nil {
+		return err
+	}
+
+	s.assignments = updated
+	saveValue(s, sw)
+	sw.Status = model.SwapCompleted
+	return nil
+}
+
+// Work out the shift assignments after a swap, without changing the
+// store. The offering worker is taken off their shift and the taker
+// put on it, with the reverse for the counter shift if there is one.
+// The caller must hold the store lock.
+func (s *MemoryStore) applySwap(swap *model.Swap) ([]model.ShiftAssignment, error) {
+	if swap.Taker == nil {
+		return nil, ErrSwapNotReady
+	}
+	taker := *swap.Taker
+
+	updated, err := removeShiftAssignment(slices.Clone(s.assignments), swap.Offerer, swap.Shift)
+	if err != nil {
+		return nil, err
+	}
+	if swap.CounterShift != nil {
+		updated, err = removeShiftAssignment(updated, taker, *swap.CounterShift)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updated, _, err = s.addShiftAssignment(updated, taker, swap.Shift, false)
+	if err != nil {
+		return nil, err
+	}
+	if swap.CounterShift != nil {
+		updated, _, err = s.addShiftAssignment(updated, swap.Offerer, *swap.CounterShift, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (s *MemoryStore) GetBids(ctx context.Context, workerId *model.WorkerID, shiftId *model.ShiftID) ([]*model.Bid, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	bids := []*model.Bid{}
+	for _, b := range s.bids {
+		if workerId != nil && b.Worker != *workerId {
+			continue
+		}
+		if shiftId != nil && b.Shift != *shiftId {
+			continue
+		}
+		rb := *b
+		bids = append(bids, &rb)
+	}
+
+	slices.SortFunc(bids, func(a, b *model.Bid) bool { return a.ID < b.ID })
+	return bids, nil
+}
+
+func (s *MemoryStore) GetBidById(ctx context.Context, id model.BidID) (*model.Bid, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	b, exists := s.bids[id]
+	if !exists {
+		return nil, ErrBidNotFound
+	}
+
+	rb := *b
+	return &rb, nil
+}
+
+func (s *MemoryStore) CreateBid(ctx context.Context, bid *model.Bid) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "CreateBid", bid)()
+
+	worker, exists := s.workers[bid.Worker]
+	if !exists {
+		return ErrWorkerNotFound
+	}
+	shift, exists := s.shifts[bid.Shift]
+	if !exists {
+		return ErrShiftNotFound
+	}
+	if !worker.InTeam(shift.Team) {
+		return ErrNotTeamMember
+	}
+	for _, b := range s.bids {
+		if b.Worker == bid.Worker && b.Shift == bid.Shift && b.Status != model.BidAwarded {
+			return ErrBidExists
+		}
+	}
+
+	existing := 0
+	for _, a := range s.assignments {
+		if a.Shift == bid.Shift {
+			if a.Worker == bid.Worker {
+				return ErrAlreadyAssigned
+			}
+			existing++
+		}
+	}
+
+	stored := *bid
+	stored.Status = model.BidPending
+	stored.Position = 0
+	if existing >= shift.Capacity {
+		stored.Status = model.BidWaitlisted
+		stored.Position = s.waitlistLength(bid.Shift) + 1
+	} else if shift.BiddingClosed {
+		return ErrBiddingClosed
+	}
+	stored.CreatedAt = s.now()
+	s.lastBidID++
+	stored.ID = s.lastBidID
+	setEntry(s, s.bids, stored.ID, &stored)
+
+	*bid = stored
+	return nil
+}
+
+func (s *MemoryStore) DeleteBidById(ctx context.Context, id model.BidID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "DeleteBidById", id)()
+
+	b, exists := s.bids[id]
+	if !exists {
+		return ErrBidNotFound
+	}
+
+	if b.Status == model.BidWaitlisted {
+		s.leaveWaitlist(b)
+	}
+	deleteEntry(s, s.bids, id)
+
+	return nil
+}
+
+func (s *MemoryStore) ResolveBids(ctx context.Context, shiftId model.ShiftID, order []model.BidID) (err error) {
+	s.Lock()
+	defer s.Unlock()
+	defer s.record(&err, "ResolveBids", shiftId, order)()
+
+	shift, exists := s.shifts[shiftId]
+	if !exists {
+		return ErrShiftNotFound
+	}
+	bids := make([]*model.Bid, len(order))
+	for i, id := range order {
+		b, exists := s.bids[id]
+		if !exists || b.Shift != shiftId || b.Status != model.BidPending {
+			return ErrBidNotFound
+		}
+		bids[i] = b
+	}
+
+	// Bidders who can't be assigned, because the shift is full or
+	// because of the scheduling rules, go to the end of the waitlist.
+	updated := slices.Clone(s.assignments)
+	position := s.waitlistLength(shiftId)
+	for _, b := range bids {
+		added, _, err := s.addShiftAssignment(updated, b.Worker, shiftId, false)
+		saveValue(s, b)
+		if err != nil {
+			position++
+			b.Status = model.BidWaitlisted
+			b.Position = position
+			continue
+		}
+		updated = added
+		b.Status = model.BidAwarded
+	}
+
+	s.assignments = updated
+	saveValue(s, shift)
+	shift.BiddingClosed = true
+	return nil
+}
+
+// Assign the first worker on a shift's waitlist who can take a place
+// on it, returning the updated list of assignments. The caller must
+// hold the store lock, and must use the returned assignments.
+func (s *MemoryStore) promoteWaitlist(assignments []model.ShiftAssignment,
+	shiftId model.ShiftID) []model.ShiftAssignment {
+	waitlist := []*model.Bid{}
+	for _, b := range s.bids {
+		if b.Shift == shiftId && b.Status == model.BidWaitlisted {
+			waitlist = append(waitlist, b)
+		}
+	}
+	slices.SortFunc(waitlist, func(a, b *model.Bid) bool { return a.Position < b.Position })
+
+	for _, b := range waitlist {
+		updated, _, err := s.addShiftAssignment(assignments, b.Worker, shiftId, false)
+		if err != nil {
+			continue
+		}
+		s.leaveWaitlist(b)
+		saveValue(s, b)
+		b.Status = model.BidAwarded
+		return updated
+	}
+	return assignments
+}
+
+// Number of bids on a shift's waitlist. The caller must hold the store
+// lock.
+func (s *MemoryStore) waitlistLength(shiftId model.ShiftID) int {
+	n := 0
+	for _, b := range s.bids {
+		if b.Shift == shiftId && b.Status == model.BidWaitlisted {
+			n++
+		}
+	}
+	return n
+}
+
+// Take a bid off its shift's waitlist, moving the bids behind it up a
+// place. The caller must hold the store lock.
+func (s *MemoryStore) leaveWaitlist(bid *model.Bid) {
+	for _, b := range s.bids {
+		if b.Shift == bid.Shift && b.Status == model.BidWaitlisted && b.Position > bid.Position {
+			saveValue(s, b)
+			b.Position--
+		}
+	}
+	saveValue(s, bid)
+	bid.Position = 0
+}
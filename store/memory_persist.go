@@ -0,0 +1,522 @@
+// Optional persistence for `MemoryStore`. The store's state is written
+// to a JSON snapshot file on a timer and when the store is closed, and
+// each successful mutation in between is appended to a write-ahead log
+// of method calls, so that the state can be recovered exactly after a
+// crash by loading the latest snapshot and replaying the log.
+
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	walFile      = "wal.jsonl"
+
+	defaultSnapshotInterval = 5 * time.Minute
+)
+
+// The files backing a persistent memory store, and the sequence number
+// of the last mutation written to the log.
+type persister struct {
+	dir  string
+	wal  *os.File
+	seq  int64
+	stop chan struct{}
+	done chan struct{}
+}
+
+// An entry in the write-ahead log: a call to one of the store's
+// mutating methods, with the time it was made, so that replaying it
+// gives the same result.
+type walEntry struct {
+	Seq    int64
+	Time   time.Time
+	Method string
+	Args   []json.RawMessage
+}
+
+// The state of a memory store, as written to a snapshot file. Seq is
+// the sequence number of the last log entry included in the snapshot.
+type memorySnapshot struct {
+	Seq              int64
+	LastWorkerID     model.WorkerID
+	LastShiftID      model.ShiftID
+	LastPreferenceID model.ShiftPreferenceID
+	LastTimeOffID    model.TimeOffID
+	LastRuleSetID    model.RuleSetID
+	LastOverrideID   model.RuleOverrideID
+	LastSwapID       model.SwapID
+	LastBidID        model.BidID
+	LastTemplateID   model.ShiftTemplateID
+	LastTeamID       model.TeamID
+	LastRoleID       model.RoleID
+	LastLockoutID    model.LockoutID
+	Workers          map[model.WorkerID]*model.Worker
+	Shifts           map[model.ShiftID]*model.Shift
+	Assignments      []model.ShiftAssignment
+	Preferences      map[model.ShiftPreferenceID]*model.ShiftPreference
+	TimeOff          map[model.TimeOffID]*model.TimeOff
+	RuleSets         map[model.RuleSetID]*model.RuleSet
+	Overrides        []*model.RuleOverride
+	Swaps            map[model.SwapID]*model.Swap
+	Bids             map[model.BidID]*model.Bid
+	Templates        map[model.ShiftTemplateID]*model.ShiftTemplate
+	Teams            map[model.TeamID]*model.Team
+	Roles            map[model.RoleID]*model.Role
+	RefreshTokens    map[string]*model.RefreshToken
+	PasswordTokens   map[string]*model.PasswordToken
+	TwoFactor        map[model.WorkerID]*model.TwoFactor
+	LoginAttempts    map[string]*model.LoginAttempts
+	Lockouts         []*model.Lockout
+}
+
+// NewMemoryStoreFromURL creates an in-memory store from a URL of the
+// form "memory?persist=/path/to/dir&snapshot=5m". Without a "persist"
+// directory the store is the same as one from `NewMemoryStore`. With
+// one, any state saved in the directory is recovered, and snapshots
+// are written at the "snapshot" interval (five minutes by default).
+func NewMemoryStoreFromURL(storeURL string) (Store, error) {
+	u, err := url.Parse(storeURL)
+	if err != nil || u.Path != "memory" {
+		return nil, fmt.Errorf("invalid memory store URL %q", storeURL)
+	}
+
+	db, err := NewMemoryStore()
+	if err != nil {
+		return nil, err
+	}
+	dir := u.Query().Get("persist")
+	if dir == "" {
+		return db, nil
+	}
+
+	interval := defaultSnapshotInterval
+	if v := u.Query().Get("snapshot"); v != "" {
+		interval, err = time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid snapshot interval %q", v)
+		}
+	}
+
+	s := db.(*MemoryStore)
+	err = s.recover(dir)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshotEvery(interval)
+
+	return s, nil
+}
+
+// Close stops the snapshot timer of a persistent store and writes a
+// final snapshot.
+func (s *MemoryStore) Close() error {
+	p := s.persist
+	if p == nil {
+		return nil
+	}
+	close(p.stop)
+	<-p.done
+
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.snapshot()
+	if cerr := p.wal.Close(); err == nil {
+		err = cerr
+	}
+	s.persist = nil
+	return err
+}
+
+// The time used by mutations: fixed for the duration of each mutation,
+// so that it can be recorded in the write-ahead log.
+func (s *MemoryStore) now() time.Time {
+	if !s.writeTime.IsZero() {
+		return s.writeTime
+	}
+	return time.Now()
+}
+
+// Passwords in the write-ahead log are already hashed.
+func (s *MemoryStore) hashPassword(password string) string {
+	if s.replaying {
+		return password
+	}
+	return hashPassword(password)
+}
+
+// Record a call to a mutating method in the write-ahead log. This is
+// deferred at the start of each mutating method, once the store lock
+// is held, as
+//
+//	defer s.record(&err, "Method", args...)()
+//
+// The arguments are captured as the method is called, and the returned
+// function writes the log entry when the method returns, but only if
+// it succeeded. A failure to write the log is returned as the method's
+// error. In a transaction, log entries are kept until the transaction
+// is done (see WithTx). The store's IDs and lists are saved so that
+// the method's changes to them can be undone, and if the method fails,
+// or its log entry can't be written, its changes are undone, so that
+// the store never has changes that a restart would lose.
+func (s *MemoryStore) record(err *error, method string, args ...interface{}) func() {
+	if s.writeTime.IsZero() {
+		s.writeTime = time.Now()
+	}
+	mark := len(s.undo)
+	saveValue(s, &s.memoryValues)
+
+	var entry *walEntry
+	var merr error
+	if s.persist != nil {
+		entry = &walEntry{Time: s.writeTime, Method: method}
+		for _, arg := range args {
+			raw, err := json.Marshal(arg)
+			if err != nil {
+				merr = err
+			}
+			entry.Args = append(entry.Args, raw)
+		}
+	}
+
+	return func() {
+		s.writeTime = time.Time{}
+		if entry != nil && *err == nil {
+			if merr == nil && s.inTx {
+				s.txLog = append(s.txLog, entry)
+			} else {
+				if merr == nil {
+					merr = s.persist.append(entry)
+				}
+				if merr != nil {
+					*err = fmt.Errorf("writing %s to write-ahead log: %w", method, merr)
+				}
+			}
+		}
+		switch {
+		case *err != nil:
+			s.undoTo(mark)
+		case !s.inTx:
+			s.undo = s.undo[:mark]
+		}
+	}
+}
+
+// Append entries to the write-ahead log, making sure that they're on
+// disk before returning. If they can't all be written, whatever was
+// written is taken back off the end of the log, so that it only has
+// changes that were kept.
+func (p *persister) append(entries ...*walEntry) error {
+	offset, err := p.wal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	seq := p.seq
+	data := []byte{}
+	for _, entry := range entries {
+		seq++
+		entry.Seq = seq
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	_, err = p.wal.Write(data)
+	if err == nil {
+		err = p.wal.Sync()
+	}
+	if err != nil {
+		p.wal.Truncate(offset)
+		p.wal.Seek(offset, io.SeekStart)
+		return err
+	}
+	p.seq = seq
+	return nil
+}
+
+// Recover the state saved in a directory, from the latest snapshot and
+// the write-ahead log, and start writing the log.
+func (s *MemoryStore) recover(dir string) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("creating store directory: %w", err)
+	}
+	p := &persister{dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	switch {
+	case err == nil:
+		snap := memorySnapshot{}
+		err = json.Unmarshal(data, &snap)
+		if err != nil {
+			return fmt.Errorf("reading snapshot: %w", err)
+		}
+		s.restore(&snap)
+		p.seq = snap.Seq
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("opening write-ahead log: %w", err)
+	}
+	end, err := s.replay(wal, &p.seq)
+	if err == nil {
+		// Anything after the last complete entry is a write that was cut
+		// off by a crash, so was never acknowledged.
+		err = wal.Truncate(end)
+	}
+	if err == nil {
+		_, err = wal.Seek(end, io.SeekStart)
+	}
+	if err != nil {
+		wal.Close()
+		return fmt.Errorf("recovering write-ahead log: %w", err)
+	}
+	p.wal = wal
+	s.persist = p
+
+	// Start from a fresh snapshot, so that the log doesn't grow across
+	// restarts.
+	s.Lock()
+	defer s.Unlock()
+	return s.snapshot()
+}
+
+// Replay the entries in the write-ahead log that came after the
+// snapshot, returning the offset of the end of the last complete
+// entry.
+func (s *MemoryStore) replay(wal io.Reader, seq *int64) (int64, error) {
+	s.replaying = true
+	defer func() { s.replaying = false }()
+
+	r := bufio.NewReader(wal)
+	end := int64(0)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return end, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		entry := walEntry{}
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			// Only the last entry can have been damaged by a crash.
+			if _, perr := r.Peek(1); perr == io.EOF {
+				return end, nil
+			}
+			return 0, fmt.Errorf("bad entry at offset %d: %w", end, err)
+		}
+		end += int64(len(line))
+
+		if entry.Seq <= *seq {
+			continue
+		}
+		err = s.apply(&entry)
+		if err != nil {
+			return 0, fmt.Errorf("replaying entry %d (%s): %w", entry.Seq, entry.Method, err)
+		}
+		*seq = entry.Seq
+	}
+}
+
+// Apply a write-ahead log entry by calling the method it records. The
+// context isn't recorded: replayed calls get a background context.
+func (s *MemoryStore) apply(entry *walEntry) error {
+	method := reflect.ValueOf(s).MethodByName(entry.Method)
+	if !method.IsValid() || method.Type().NumIn() != len(entry.Args)+1 {
+		return errors.New("unknown method")
+	}
+
+	args := []reflect.Value{reflect.ValueOf(context.Background())}
+	for i, raw := range entry.Args {
+		arg := reflect.New(method.Type().In(i + 1))
+		err := json.Unmarshal(raw, arg.Interface())
+		if err != nil {
+			return err
+		}
+		args = append(args, arg.Elem())
+	}
+
+	s.writeTime = entry.Time
+	results := method.Call(args)
+	err, _ := results[len(results)-1].Interface().(error)
+	return err
+}
+
+// Write snapshots of the store's state at regular intervals, until the
+// store is closed.
+func (s *MemoryStore) snapshotEvery(interval time.Duration) {
+	p := s.persist
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Lock()
+				err := s.snapshot()
+				s.Unlock()
+				if err != nil {
+					log.Println("Failed to write memory store snapshot: ", err)
+				}
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Write a snapshot of the store's state and empty the write-ahead log.
+// The snapshot replaces the previous one atomically, and if there's a
+// crash before the log is emptied, the entries already in the snapshot
+// are skipped when the log is replayed. The caller must hold the store
+// lock.
+func (s *MemoryStore) snapshot() error {
+	p := s.persist
+	data, err := json.Marshal(s.toSnapshot(p.seq))
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(p.dir, snapshotFile+".tmp")
+	err = writeFileSync(tmp, data)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, filepath.Join(p.dir, snapshotFile))
+	if err != nil {
+		return err
+	}
+	dir, err := os.Open(p.dir)
+	if err != nil {
+		return err
+	}
+	err = dir.Sync()
+	dir.Close()
+	if err != nil {
+		return err
+	}
+
+	err = p.wal.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = p.wal.Seek(0, io.SeekStart)
+	return err
+}
+
+func writeFileSync(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *MemoryStore) toSnapshot(seq int64) *memorySnapshot {
+	return &memorySnapshot{
+		Seq:              seq,
+		LastWorkerID:     s.lastWorkerID,
+		LastShiftID:      s.lastShiftID,
+		LastPreferenceID: s.lastPreferenceID,
+		LastTimeOffID:    s.lastTimeOffID,
+		LastRuleSetID:    s.lastRuleSetID,
+		LastOverrideID:   s.lastOverrideID,
+		LastSwapID:       s.lastSwapID,
+		LastBidID:        s.lastBidID,
+		LastTemplateID:   s.lastTemplateID,
+		LastTeamID:       s.lastTeamID,
+		LastRoleID:       s.lastRoleID,
+		LastLockoutID:    s.lastLockoutID,
+		Workers:          s.workers,
+		Shifts:           s.shifts,
+		Assignments:      s.assignments,
+		Preferences:      s.preferences,
+		TimeOff:          s.timeOff,
+		RuleSets:         s.ruleSets,
+		Overrides:        s.overrides,
+		Swaps:            s.swaps,
+		Bids:             s.bids,
+		Templates:        s.templates,
+		Teams:            s.teams,
+		Roles:            s.roles,
+		RefreshTokens:    s.refreshTokens,
+		PasswordTokens:   s.passwordTokens,
+		TwoFactor:        s.twoFactor,
+		LoginAttempts:    s.loginAttempts,
+		Lockouts:         s.lockouts,
+	}
+}
+
+func (s *MemoryStore) restore(snap *memorySnapshot) {
+	s.lastWorkerID = snap.LastWorkerID
+	s.lastShiftID = snap.LastShiftID
+	s.lastPreferenceID = snap.LastPreferenceID
+	s.lastTimeOffID = snap.LastTimeOffID
+	s.lastRuleSetID = snap.LastRuleSetID
+	s.lastOverrideID = snap.LastOverrideID
+	s.lastSwapID = snap.LastSwapID
+	s.lastBidID = snap.LastBidID
+	s.lastTemplateID = snap.LastTemplateID
+	s.lastTeamID = snap.LastTeamID
+	s.lastRoleID = snap.LastRoleID
+	s.lastLockoutID = snap.LastLockoutID
+	s.workers = snap.Workers
+	s.shifts = snap.Shifts
+	s.assignments = snap.Assignments
+	s.preferences = snap.Preferences
+	s.timeOff = snap.TimeOff
+	s.ruleSets = snap.RuleSets
+	s.overrides = snap.Overrides
+	s.swaps = snap.Swaps
+	s.bids = snap.Bids
+	s.templates = snap.Templates
+	s.teams = snap.Teams
+	s.roles = snap.Roles
+	s.refreshTokens = snap.RefreshTokens
+	s.passwordTokens = snap.PasswordTokens
+	s.twoFactor = snap.TwoFactor
+	s.loginAttempts = snap.LoginAttempts
+	s.lockouts = snap.Lockouts
+
+	s.workersByEmail = make(map[string]*model.Worker)
+	for _, w := range s.workers {
+		s.workersByEmail[w.Email] = w
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"skybluetrades.net/work-planning-demo/model"
+)
+
+func TestMemoryStoreRecovery(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	storeURL := "memory?persist=" + dir
+
+	db, err := NewMemoryStoreFromURL(storeURL)
+	assert.NoError(err)
+	db.Migrate()
+	worker := &model.Worker{Name: "New Person", Email: "new@example.com", Password: "secret"}
+	assert.NoError(db.CreateWorker(ctx, worker))
+	assert.NoError(db.DeleteShiftById(ctx, 1))
+
+	// Only transactions that succeed are logged.
+	assert.NoError(db.WithTx(ctx, func(tx Store) error {
+		return tx.DeleteShiftById(ctx, 2)
+	}))
+	assert.Error(db.WithTx(ctx, func(tx Store) error {
+		tx.DeleteShiftById(ctx, 3)
+		return errors.New("failed")
+	}))
+	before, _ := db.GetWorkers(ctx, nil)
+
+	// Crash without a final snapshot, part-way through writing a log
+	// entry.
+	s := db.(*MemoryStore)
+	close(s.persist.stop)
+	_, err = s.persist.wal.WriteString(`{"Seq":9999,"Method":"Cre`)
+	assert.NoError(err)
+	s.persist.wal.Close()
+
+	db, err = NewMemoryStoreFromURL(storeURL)
+	assert.NoError(err)
+	after, _ := db.GetWorkers(ctx, nil)
+	assert.ElementsMatch(before, after)
+	_, err = db.GetShiftById(ctx, 1)
+	assert.ErrorIs(err, ErrShiftNotFound)
+	_, err = db.GetShiftById(ctx, 2)
+	assert.ErrorIs(err, ErrShiftNotFound)
+	_, err = db.GetShiftById(ctx, 3)
+	assert.NoError(err)
+	_, err = db.Authenticate(ctx, "new@example.com", "secret")
+	assert.NoError(err)
+
+	// A clean shutdown leaves everything in the snapshot.
+	assert.NoError(db.Close())
+	wal, err := os.ReadFile(filepath.Join(dir, walFile))
+	assert.NoError(err)
+	assert.Empty(wal)
+	db, err = NewMemoryStoreFromURL(storeURL)
+	assert.NoError(err)
+	defer db.Close()
+	again, _ := db.GetWorkers(ctx, nil)
+	assert.ElementsMatch(before, again)
+}
+
+// Changes that can't be written to the write-ahead log are undone, so
+// that a restart doesn't lose anything that was seen.
+func TestMemoryStoreWALFailure(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	storeURL := "memory?persist=" + dir
+
+	db, err := NewMemoryStoreFromURL(storeURL)
+	assert.NoError(err)
+	db.Migrate()
+	s := db.(*MemoryStore)
+	before, _ := db.GetWorkers(ctx, nil)
+
+	// Swap the log for a read-only handle on it.
+	wal := s.persist.wal
+	s.persist.wal, err = os.Open(wal.Name())
+	assert.NoError(err)
+
+	worker := &model.Worker{Name: "Lost", Email: "lost@example.com", Password: "secret"}
+	assert.Error(db.CreateWorker(ctx, worker))
+	lostID := worker.ID
+	assert.Error(db.WithTx(ctx, func(tx Store) error {
+		return tx.DeleteShiftById(ctx, 1)
+	}))
+	assert.Error(db.DeleteWorkerById(ctx, before[0].ID))
+
+	check := func(db Store) {
+		_, err := db.GetWorkerByEmail(ctx, "lost@example.com")
+		assert.ErrorIs(err, ErrUnknownWorkerEmail)
+		_, err = db.GetShiftById(ctx, 1)
+		assert.NoError(err)
+		workers, _ := db.GetWorkers(ctx, nil)
+		assert.ElementsMatch(before, workers)
+	}
+	check(db)
+
+	// Once the log can be written again, the IDs used by the failed
+	// changes are used again, and a restart gets back the same state.
+	s.persist.wal.Close()
+	s.persist.wal = wal
+	worker = &model.Worker{Name: "Kept", Email: "kept@example.com", Password: "secret"}
+	assert.NoError(db.CreateWorker(ctx, worker))
+	assert.Equal(lostID, worker.ID)
+	close(s.persist.stop)
+	s.persist.wal.Close()
+
+	db, err = NewMemoryStoreFromURL(storeURL)
+	assert.NoError(err)
+	defer db.Close()
+	got, err := db.GetWorkerByEmail(ctx, "kept@example.com")
+	assert.NoError(err)
+	assert.Equal(worker.ID, got.ID)
+	assert.NoError(db.DeleteWorkerById(ctx, worker.ID))
+	check(db)
+}
@@ -1,72 +1,115 @@
 package store
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"skybluetrades.net/work-planning-demo/domain"
 	"skybluetrades.net/work-planning-demo/model"
 )
 
-func createTestWorker(s Store,
-	email string, name string, password string, isAdmin bool) *model.Worker {
+func createTestWorker(ctx context.Context, s Store, email string, name string,
+	password string, isAdmin bool, roles ...model.RoleID) *model.Worker {
 	worker := &model.Worker{
 		Email:    email,
 		Name:     name,
 		IsAdmin:  isAdmin,
 		Password: password,
+		Roles:    roles,
 	}
-	err := s.CreateWorker(worker)
+	err := s.CreateWorker(ctx, worker)
 	if err != nil {
 		log.Fatalln("Failed creating test data in createWorker: ", err)
 	}
 	return worker
 }
 
-func createTestShift(s Store,
-	day string, hourStart int, capacity int) *model.Shift {
-	dayDate, _ := time.Parse("2006-01-02", day)
-	start := dayDate.Add(time.Duration(hourStart) * time.Hour)
-	end := dayDate.Add(time.Duration(hourStart+8) * time.Hour)
-	shift := &model.Shift{
-		StartTime: start,
-		EndTime:   end,
+func createTestTeam(ctx context.Context, s Store, name string) *model.Team {
+	team := &model.Team{Name: name}
+	err := s.CreateTeam(ctx, team)
+	if err != nil {
+		log.Fatalln("Failed creating test data in createTeam: ", err)
+	}
+	return team
+}
+
+func createTestRole(ctx context.Context, s Store, name string, perms ...model.Permission) *model.Role {
+	role := &model.Role{Name: name, Permissions: perms}
+	err := s.CreateRole(ctx, role)
+	if err != nil {
+		log.Fatalln("Failed creating test data in createRole: ", err)
+	}
+	return role
+}
+
+func createTestTemplate(ctx context.Context, s Store, name string,
+	weekdays model.Weekdays, hourStart int, capacity int) *model.ShiftTemplate {
+	template := &model.ShiftTemplate{
+		Name:      name,
+		Weekdays:  weekdays,
+		StartHour: hourStart,
+		EndHour:   hourStart + 8,
 		Capacity:  capacity,
 	}
-	err := s.CreateShift(shift)
+	err := s.CreateShiftTemplate(ctx, template)
 	if err != nil {
-		log.Fatalln("Failed creating test data in createShift: ", err)
+		log.Fatalln("Failed creating test data in createShiftTemplate: ", err)
 	}
-	return shift
+	return template
 }
 
 func addTestData(s Store) {
+	ctx := context.Background()
 	fmt.Println()
 	fmt.Println("+---------------------+")
 	fmt.Println("| ADDING TEST DATA... |")
 	fmt.Println("+---------------------+")
 	fmt.Println()
 
+	// Worker 4 can look at everyone's schedules, but can't change
+	// anything.
+	createTestRole(ctx, s, "Scheduler", model.PermWorkersRead, model.PermShiftsRead,
+		model.PermShiftsWrite, model.PermAssignmentsWrite, model.PermScheduleRead)
+	viewer := createTestRole(ctx, s, "Schedule viewer", model.PermWorkersRead,
+		model.PermShiftsRead, model.PermScheduleRead)
+
 	workers := []*model.Worker{}
-	workers = append(workers, createTestWorker(s, "test1@example.com", "Tina Tester", "password1", true))
-	workers = append(workers, createTestWorker(s, "test2@example.com", "Tom Testerman", "password2", false))
-	workers = append(workers, createTestWorker(s, "test3@example.com", "Tammy Testino", "password3", false))
-	workers = append(workers, createTestWorker(s, "test4@example.com", "Todd Testa", "password4", false))
+	workers = append(workers, createTestWorker(ctx, s, "test1@example.com", "Tina Tester", "password1", true))
+	workers = append(workers, createTestWorker(ctx, s, "test2@example.com", "Tom Testerman", "password2", false))
+	workers = append(workers, createTestWorker(ctx, s, "test3@example.com", "Tammy Testino", "password3", false))
+	workers = append(workers, createTestWorker(ctx, s, "test4@example.com", "Todd Testa", "password4", false, viewer.ID))
 
-	shifts := []*model.Shift{}
-	for d := 1; d <= 31; d++ {
-		dt := fmt.Sprintf("2023-05-%02d", d)
-		ndaytime := 3
-		if d%7 == 6 || d%7 == 0 {
-			// Weekends
-			ndaytime = 2
-		}
-		shifts = append(shifts, createTestShift(s, dt, 0, 1))
-		shifts = append(shifts, createTestShift(s, dt, 8, ndaytime))
-		shifts = append(shifts, createTestShift(s, dt, 16, 2))
+	// Workers 2 and 3 are on the north site, with worker 3 as its
+	// admin, and worker 4 is on the south site. The generated shifts
+	// don't belong to either, so anyone can work them.
+	north := createTestTeam(ctx, s, "North site")
+	south := createTestTeam(ctx, s, "South site")
+	s.SetTeamMember(ctx, north.ID, workers[1].ID, false)
+	s.SetTeamMember(ctx, north.ID, workers[2].ID, true)
+	s.SetTeamMember(ctx, south.ID, workers[3].ID, false)
+
+	everyDay := model.NewWeekdays(time.Sunday, time.Monday, time.Tuesday,
+		time.Wednesday, time.Thursday, time.Friday, time.Saturday)
+	weekends := model.NewWeekdays(time.Saturday, time.Sunday)
+	templates := []*model.ShiftTemplate{}
+	templates = append(templates, createTestTemplate(ctx, s, "Night", everyDay, 0, 1))
+	templates = append(templates, createTestTemplate(ctx, s, "Weekday day", everyDay&^weekends, 8, 3))
+	templates = append(templates, createTestTemplate(ctx, s, "Weekend day", weekends, 8, 2))
+	templates = append(templates, createTestTemplate(ctx, s, "Evening", everyDay, 16, 2))
+
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	shifts, err := s.CreateShifts(ctx, domain.GenerateShifts(templates, start, start.AddDate(0, 0, 31)))
+	if err != nil {
+		log.Fatalln("Failed creating test data in CreateShifts: ", err)
 	}
 
+	// A week of day shifts breaks some of the default soft rules, so
+	// add these in bulk, which only checks the hard rules.
+	assignments := []model.ShiftAssignment{}
 	for i := 0; i < 7; i++ {
-		s.CreateShiftAssignment(workers[1].ID, shifts[i*3+1].ID)
+		assignments = append(assignments, model.ShiftAssignment{Worker: workers[1].ID, Shift: shifts[i*3+1].ID})
 	}
+	s.CreateShiftAssignments(ctx, assignments)
 }
@@ -1,9 +1,14 @@
 // `PGStore` is a Postgres-based implementation of the `Store`
 // interface, including embedded migrations (using `sql-migrate`).
+//
+// The SQLite store (in sqlite.go) uses the same queries, so they stick
+// to SQL that both databases understand: times come from Go rather
+// than from NOW(), and lists of IDs are expanded with `sqlx.In`.
 
 package store
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"errors"
@@ -18,16 +23,46 @@ import (
 	"skybluetrades.net/work-planning-demo/domain"
 	"skybluetrades.net/work-planning-demo/model"
 
-	// Import Postgres DB driver.
-	_ "github.com/lib/pq"
+	// Postgres DB driver.
+	"github.com/lib/pq"
 )
 
 // PGStore is a wrapper for the user database connection.
 type PGStore struct {
 	db *sqlx.DB
+
+	// Whether transactions lock the tables and rows they use. SQLite
+	// doesn't have locks like this, and doesn't need them, because its
+	// transactions run one at a time.
+	locking bool
+
+	// Whether the database is SQLite, which has its own query
+	// parameters and a single connection (see sqlite.go).
+	sqlite bool
+
+	// The transaction that everything runs in, in the view of the store
+	// that WithTx gives its function.
+	tx *pgTx
+}
+
+// A transaction, or a savepoint within the transaction of a WithTx
+// call, which methods that make several changes use in the same way.
+type pgTx struct {
+	*sqlx.Tx
+	locking   bool
+	sqlite    bool
+	savepoint bool
+}
+
+// The queries that run either directly or in a transaction.
+type pgConn interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
 }
 
-//go:embed postgres/*.sql
+//go:embed postgres/*.sql sqlite/*.sql
 var migrations embed.FS
 
 // NewPostgresStore creates a new user database connection.
@@ -45,10 +80,180 @@ func NewPostgresStore(dbURL string) (Store, error) {
 	// Limit maximum connections (default is unlimited).
 	db.SetMaxOpenConns(10)
 
-	return &PGStore{db: db}, nil
+	return &PGStore{db: db, locking: true}, nil
 }
 
 func (pg *PGStore) Migrate() {
+	err := runMigrations(pg.db, "postgres", "postgres")
+	if err != nil {
+		log.Fatalln("Failed to migrate PostgreSQL database: ", err)
+	}
+}
+
+func (pg *PGStore) Close() error {
+	return pg.db.Close()
+}
+
+// WithTx runs the function in a serializable transaction (SQLite's
+// transactions are serializable already), retrying it a few times if
+// it fails because of a concurrent transaction. Nested calls use
+// savepoints.
+func (pg *PGStore) WithTx(ctx context.Context, fn func(tx Store) error) error {
+	var opts *sql.TxOptions
+	if pg.locking {
+		opts = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	}
+	for attempt := 1; ; attempt++ {
+		err := pg.runTx(ctx, opts, fn)
+		if pg.tx != nil || attempt == maxTxAttempts || !retryable(err) {
+			return err
+		}
+	}
+}
+
+const maxTxAttempts = 10
+
+func (pg *PGStore) runTx(ctx context.Context, opts *sql.TxOptions,
+	fn func(tx Store) error) (err error) {
+	tx, err := pg.beginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	err = fn(&PGStore{db: pg.db, locking: pg.locking, sqlite: pg.sqlite, tx: tx})
+	return err
+}
+
+// Serialization failures and deadlocks between concurrent transactions
+// go away if the transaction is tried again.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
+
+// Check for a violation of a unique index, which Postgres reports by
+// the index's name and SQLite by the columns in it.
+func uniqueViolation(err error, index string, columns string) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" && pqErr.Constraint == index
+	}
+	return sqliteUniqueViolation(err, columns)
+}
+
+// Start a transaction, or a savepoint in a WithTx call's transaction.
+func (pg *PGStore) begin(ctx context.Context) (*pgTx, error) {
+	return pg.beginTx(ctx, nil)
+}
+
+func (pg *PGStore) beginTx(ctx context.Context, opts *sql.TxOptions) (*pgTx, error) {
+	if pg.tx != nil {
+		_, err := pg.tx.ExecContext(ctx, "SAVEPOINT store_tx")
+		if err != nil {
+			return nil, err
+		}
+		return &pgTx{Tx: pg.tx.Tx, locking: pg.locking, sqlite: pg.sqlite, savepoint: true}, nil
+	}
+
+	if pg.sqlite {
+		err := waitForSQLite(ctx, pg.db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tx, err := pg.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pgTx{Tx: tx, locking: pg.locking, sqlite: pg.sqlite}, nil
+}
+
+func (tx *pgTx) Commit() error {
+	if !tx.savepoint {
+		return tx.Tx.Commit()
+	}
+	_, err := tx.Exec("RELEASE SAVEPOINT store_tx")
+	return err
+}
+
+func (tx *pgTx) Rollback() error {
+	if !tx.savepoint {
+		return tx.Tx.Rollback()
+	}
+	_, err := tx.Exec("ROLLBACK TO SAVEPOINT store_tx")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("RELEASE SAVEPOINT store_tx")
+	return err
+}
+
+// Queries outside transactions go straight to the database, except in
+// a WithTx call.
+func (pg *PGStore) conn() pgConn {
+	if pg.tx != nil {
+		return pg.tx
+	}
+	if pg.sqlite {
+		return &sqliteDB{pg.db}
+	}
+	return pg.db
+}
+
+// Queries in transactions have their parameters changed for SQLite.
+func (tx *pgTx) bind(query string) string {
+	if !tx.sqlite {
+		return query
+	}
+	return sqliteQuery(query)
+}
+
+func (tx *pgTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, tx.bind(query), args...)
+}
+
+func (tx *pgTx) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return tx.Tx.GetContext(ctx, dest, tx.bind(query), args...)
+}
+
+func (tx *pgTx) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return tx.Tx.SelectContext(ctx, dest, tx.bind(query), args...)
+}
+
+func (tx *pgTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, tx.bind(query), args...)
+}
+
+func (tx *pgTx) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return tx.Tx.QueryxContext(ctx, tx.bind(query), args...)
+}
+
+func (tx *pgTx) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	return tx.Tx.QueryRowxContext(ctx, tx.bind(query), args...)
+}
+
+// Lock the rows read by a query within a transaction.
+func (tx *pgTx) forUpdate(query string) string {
+	if !tx.locking {
+		return query
+	}
+	return query + " FOR UPDATE"
+}
+
+// Run the embedded migrations from a directory, using an sql-migrate
+// dialect.
+func runMigrations(db *sqlx.DB, dir string, dialect string) error {
 	// Find embedded migrations.
 	m := &migrate.AssetMigrationSource{
 		Asset: migrations.ReadFile,
@@ -66,21 +271,19 @@ func (pg *PGStore) Migrate() {
 				return entries, nil
 			}
 		}(),
-		Dir: "postgres",
+		Dir: dir,
 	}
 
-	// Run and log database migrations.
-	_, err := migrate.Exec(pg.db.DB, "postgres", m, migrate.Up)
-	if err != nil {
-		log.Fatalln("Failed to migrate PostgreSQL database: ", err)
-	}
+	// Run database migrations.
+	_, err := migrate.Exec(db.DB, dialect, m, migrate.Up)
+	return err
 }
 
-func (pg *PGStore) Authenticate(email string, password string) (*model.Worker, error) {
+func (pg *PGStore) Authenticate(ctx context.Context, email string, password string) (*model.Worker, error) {
 	worker := &model.Worker{}
-	err := pg.db.Get(worker, workerByEmail, email)
+	err := pg.conn().GetContext(ctx, worker, workerByEmail, email)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("unknown worker email")
+		return nil, ErrUnknownWorkerEmail
 	}
 	if err != nil {
 		return nil, err
@@ -88,6 +291,10 @@ func (pg *PGStore) Authenticate(email string, password string) (*model.Worker, e
 	if err := bcrypt.CompareHashAndPassword([]byte(worker.Password), []byte(password)); err != nil {
 		return nil, err
 	}
+	err = loadWorkerDetails(ctx, pg.conn(), []*model.Worker{worker})
+	if err != nil {
+		return nil, err
+	}
 	return worker, nil
 }
 
@@ -96,39 +303,41 @@ SELECT id, email, name, is_admin, password
   FROM worker
  WHERE email = $1`
 
-func (pg *PGStore) GetWorkers() ([]*model.Worker, error) {
-	results := []*model.Worker{}
-	var err error
-	err = pg.db.Select(&results, getWorkers)
+func (pg *PGStore) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) (err error) {
+	tx, err := pg.begin(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return results, nil
-}
-
-const getWorkers = `SELECT id, email, name, is_admin FROM worker`
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
 
-func (pg *PGStore) GetWorkerById(id model.WorkerID) (*model.Worker, error) {
-	worker := &model.Worker{}
-	err := pg.db.Get(worker, workerById, id)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("unknown worker ID")
-	}
+	_, err = tx.ExecContext(ctx, deleteExpiredRefreshTokens, time.Now())
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return worker, nil
+	_, err = tx.NamedExecContext(ctx, createRefreshToken, token)
+	return err
 }
 
-const workerById = `
-SELECT id, email, name, is_admin, password
-  FROM worker
- WHERE id = $1`
+const deleteExpiredRefreshTokens = "DELETE FROM refresh_token WHERE expires_at < $1"
+
+const createRefreshToken = `
+INSERT INTO refresh_token (id, family, worker_id, expires_at, used, revoked)
+VALUES (:id, :family, :worker_id, :expires_at, :used, :revoked)`
 
-func (pg *PGStore) CreateWorker(worker *model.Worker) error {
-	tx, err := pg.db.Beginx()
+// The token row is locked while it's marked as used, so that if the
+// same token is used twice at once, one of the callers sees that it's
+// already been used.
+func (pg *PGStore) UseRefreshToken(ctx context.Context, id string) (token *model.RefreshToken, err error) {
+	tx, err := pg.begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		switch err {
@@ -139,40 +348,66 @@ func (pg *PGStore) CreateWorker(worker *model.Worker) error {
 		}
 	}()
 
-	check := &model.Worker{}
-	err = tx.Get(check, workerByEmail, worker.Email)
-	if err != sql.ErrNoRows {
-		return errors.New("non-unique worker email")
+	token = &model.RefreshToken{}
+	err = tx.GetContext(ctx, token, tx.forUpdate(refreshTokenById), id)
+	if err == sql.ErrNoRows {
+		err = ErrRefreshTokenNotFound
+		return nil, err
 	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, useRefreshToken, id)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
 
-	bcryptPassword, _ := bcrypt.GenerateFromPassword([]byte(worker.Password), 0)
-	stored := *worker
-	stored.Password = string(bcryptPassword)
+const refreshTokenById = `
+SELECT id, family, worker_id, expires_at, used, revoked
+  FROM refresh_token
+ WHERE id = $1`
+
+const useRefreshToken = "UPDATE refresh_token SET used = TRUE WHERE id = $1"
+
+func (pg *PGStore) RevokeRefreshTokenFamily(ctx context.Context, family string) error {
+	_, err := pg.conn().ExecContext(ctx, revokeRefreshTokenFamily, family)
+	return err
+}
 
-	rows, err := tx.NamedQuery(createWorker, &stored)
+const revokeRefreshTokenFamily = "UPDATE refresh_token SET revoked = TRUE WHERE family = $1"
+
+func (pg *PGStore) RevokeWorkerRefreshTokens(ctx context.Context, workerId model.WorkerID) error {
+	var exists bool
+	err := pg.conn().GetContext(ctx, &exists, workerExists, workerId)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		return sql.ErrNoRows
+	if !exists {
+		return ErrWorkerNotFound
 	}
 
-	err = rows.Scan(&worker.ID)
+	_, err = pg.conn().ExecContext(ctx, revokeWorkerRefreshTokens, workerId)
+	return err
+}
+
+const revokeWorkerRefreshTokens = "UPDATE refresh_token SET revoked = TRUE WHERE worker_id = $1"
+
+func (pg *PGStore) RefreshTokenFamilyRevoked(ctx context.Context, family string) (bool, error) {
+	var revoked bool
+	err := pg.conn().GetContext(ctx, &revoked, refreshTokenFamilyRevoked, family)
 	if err != nil {
-		return err
+		return false, err
 	}
-
-	return nil
+	return revoked, nil
 }
 
-const createWorker = `
-INSERT INTO worker (email, name, is_admin, password)
-     VALUES (:email, :name, :is_admin, :password)
-RETURNING id`
+const refreshTokenFamilyRevoked = `
+SELECT EXISTS (SELECT 1 FROM refresh_token WHERE family = $1 AND revoked)`
 
-func (pg *PGStore) UpdateWorker(worker *model.Worker) error {
-	tx, err := pg.db.Beginx()
+func (pg *PGStore) CreatePasswordToken(ctx context.Context, token *model.PasswordToken) (err error) {
+	tx, err := pg.begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -185,33 +420,42 @@ func (pg *PGStore) UpdateWorker(worker *model.Worker) error {
 		}
 	}()
 
-	bcryptPassword, _ := bcrypt.GenerateFromPassword([]byte(worker.Password), 0)
-	stored := *worker
-	stored.Password = string(bcryptPassword)
-
-	result, err := tx.NamedExec(updateWorker, &stored)
+	_, err = tx.ExecContext(ctx, deleteExpiredPasswordTokens, time.Now())
 	if err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	_, err = tx.NamedExecContext(ctx, createPasswordToken, token)
+	return err
+}
+
+const deleteExpiredPasswordTokens = "DELETE FROM password_token WHERE expires_at < $1"
+
+const createPasswordToken = `
+INSERT INTO password_token (id, worker_id, purpose, expires_at, used)
+VALUES (:id, :worker_id, :purpose, :expires_at, :used)`
+
+// Checking and marking the token in a single update means that a token
+// can't be used twice, even at the same time.
+func (pg *PGStore) UsePasswordToken(ctx context.Context, id string,
+	purpose model.PasswordTokenPurpose) (*model.PasswordToken, error) {
+	token := &model.PasswordToken{}
+	err := pg.conn().GetContext(ctx, token, usePasswordToken, id, purpose, time.Now())
+	if err == sql.ErrNoRows {
+		return nil, ErrPasswordTokenInvalid
 	}
-	if rows != 1 {
-		return errors.New("failed to update worker")
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return token, nil
 }
 
-const updateWorker = `
-UPDATE worker
-   SET email = :email, name = :name,
-       is_admin = :is_admin, password = :password
-WHERE id = :id`
+const usePasswordToken = `
+UPDATE password_token SET used = TRUE
+ WHERE id = $1 AND purpose = $2 AND NOT used AND expires_at > $3
+RETURNING id, worker_id, purpose, expires_at, used`
 
-func (pg *PGStore) DeleteWorkerById(id model.WorkerID) error {
-	result, err := pg.db.Exec(deleteWorker, id)
+func (pg *PGStore) SetWorkerPassword(ctx context.Context, id model.WorkerID, password string) error {
+	result, err := pg.conn().ExecContext(ctx, setWorkerPassword, id, hashPassword(password))
 	if err != nil {
 		return err
 	}
@@ -220,67 +464,38 @@ func (pg *PGStore) DeleteWorkerById(id model.WorkerID) error {
 		return err
 	}
 	if rows != 1 {
-		return errors.New("failed to delete worker")
+		return ErrWorkerNotFound
 	}
 	return nil
 }
 
-const deleteWorker = "DELETE FROM worker WHERE id = $1"
-
-func (pg *PGStore) GetShifts(date *time.Time, span TimeSpan, workerId *model.WorkerID) ([]*model.Shift, error) {
-	// Calculate interval start and end from date and span.
-	var intStart, intEnd time.Time
-	includeAll := date == nil
-	if !includeAll {
-		intStart, intEnd = getSpanRange(date, span)
-	}
+const setWorkerPassword = "UPDATE worker SET password = $2 WHERE id = $1"
 
-	conditions := []string{}
-	if workerId != nil {
-		cond := fmt.Sprintf("id = %d", *workerId)
-		conditions = append(conditions, cond)
-	}
-	if !includeAll {
-		sIntStart := intStart.Format(time.RFC3339)
-		sIntEnd := intEnd.Format(time.RFC3339)
-		cond := fmt.Sprintf("start_time < '%s' AND end_time > '%s'", sIntEnd, sIntStart)
-		conditions = append(conditions, cond)
-	}
-	q := getShifts
-	if len(conditions) > 0 {
-		q += " WHERE " + strings.Join(conditions, " AND ")
+func (pg *PGStore) GetTwoFactor(ctx context.Context, workerId model.WorkerID) (*model.TwoFactor, error) {
+	tf := &model.TwoFactor{}
+	err := pg.conn().GetContext(ctx, tf, twoFactorByWorker, workerId)
+	if err == sql.ErrNoRows {
+		return nil, ErrTwoFactorNotFound
 	}
-
-	results := []*model.Shift{}
-	var err error
-	err = pg.db.Select(&results, q)
 	if err != nil {
 		return nil, err
 	}
-	return results, nil
-}
-
-const getShifts = `SELECT id, start_time, end_time, capacity FROM shift`
-
-func (pg *PGStore) GetShiftById(id model.ShiftID) (*model.Shift, error) {
-	shift := &model.Shift{}
-	err := pg.db.Get(shift, shiftById, id)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("unknown shift ID")
-	}
+	tf.RecoveryCodes = []string{}
+	err = pg.conn().SelectContext(ctx, &tf.RecoveryCodes, getRecoveryCodes, workerId)
 	if err != nil {
 		return nil, err
 	}
-	return shift, nil
+	return tf, nil
 }
 
-const shiftById = `
-SELECT id, start_time, end_time, capacity
-  FROM shift
- WHERE id = $1`
+const twoFactorByWorker = `
+SELECT worker_id, secret, enabled, last_step FROM two_factor WHERE worker_id = $1`
+
+const getRecoveryCodes = `
+SELECT code_hash FROM recovery_code WHERE worker_id = $1 ORDER BY code_hash`
 
-func (pg *PGStore) CreateShift(shift *model.Shift) error {
-	tx, err := pg.db.Beginx()
+func (pg *PGStore) SetTwoFactor(ctx context.Context, tf *model.TwoFactor) (err error) {
+	tx, err := pg.begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -293,43 +508,47 @@ func (pg *PGStore) CreateShift(shift *model.Shift) error {
 		}
 	}()
 
-	rows, err := tx.NamedQuery(createWorker, shift)
+	var exists bool
+	err = tx.GetContext(ctx, &exists, workerExists, tf.Worker)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		return sql.ErrNoRows
+	if !exists {
+		err = ErrWorkerNotFound
+		return err
 	}
 
-	err = rows.Scan(&shift.ID)
+	_, err = tx.NamedExecContext(ctx, setTwoFactor, tf)
 	if err != nil {
 		return err
 	}
-
-	return nil
-}
-
-const createShift = `
-INSERT INTO shift (start_time, end_time, capacity)
-     VALUES (:start_time, :end_time, :capacity)
-RETURNING id`
-
-func (pg *PGStore) UpdateShift(shift *model.Shift) error {
-	tx, err := pg.db.Beginx()
+	_, err = tx.ExecContext(ctx, deleteRecoveryCodes, tf.Worker)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		switch err {
-		case nil:
-			err = tx.Commit()
-		default:
-			tx.Rollback()
+	for _, hash := range tf.RecoveryCodes {
+		_, err = tx.ExecContext(ctx, createRecoveryCode, tf.Worker, hash)
+		if err != nil {
+			return err
 		}
-	}()
+	}
+	return nil
+}
 
-	result, err := tx.NamedExec(updateShift, shift)
+const setTwoFactor = `
+INSERT INTO two_factor (worker_id, secret, enabled, last_step)
+VALUES (:worker_id, :secret, :enabled, :last_step)
+ON CONFLICT (worker_id) DO UPDATE
+  SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, last_step = EXCLUDED.last_step`
+
+const deleteRecoveryCodes = "DELETE FROM recovery_code WHERE worker_id = $1"
+
+const createRecoveryCode = `
+INSERT INTO recovery_code (worker_id, code_hash) VALUES ($1, $2)`
+
+// Deleting two-factor settings cascades to the recovery codes.
+func (pg *PGStore) DeleteTwoFactor(ctx context.Context, workerId model.WorkerID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteTwoFactor, workerId)
 	if err != nil {
 		return err
 	}
@@ -338,20 +557,17 @@ func (pg *PGStore) UpdateShift(shift *model.Shift) error {
 		return err
 	}
 	if rows != 1 {
-		return errors.New("failed to update shift")
+		return ErrTwoFactorNotFound
 	}
-
 	return nil
 }
 
-const updateShift = `
-UPDATE worker
-   SET start_time = :start_time, end_time = :end_time,
-       capacity = :capacity
-WHERE id = :id`
+const deleteTwoFactor = "DELETE FROM two_factor WHERE worker_id = $1"
 
-func (pg *PGStore) DeleteShiftById(id model.ShiftID) error {
-	result, err := pg.db.Exec(deleteShift, id)
+// The step is only recorded if it's later than the last one, in a
+// single update, so that a code can't be used twice at once.
+func (pg *PGStore) RecordTOTPStep(ctx context.Context, workerId model.WorkerID, step int64) error {
+	result, err := pg.conn().ExecContext(ctx, recordTOTPStep, workerId, step)
 	if err != nil {
 		return err
 	}
@@ -360,56 +576,95 @@ func (pg *PGStore) DeleteShiftById(id model.ShiftID) error {
 		return err
 	}
 	if rows != 1 {
-		return errors.New("failed to delete shift")
+		return ErrTwoFactorCodeInvalid
 	}
 	return nil
 }
 
-const deleteShift = "DELETE FROM shift WHERE id = $1"
+const recordTOTPStep = `
+UPDATE two_factor SET last_step = $2 WHERE worker_id = $1 AND last_step < $2`
 
-func (pg *PGStore) CreateShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error {
-	tx, err := pg.db.Beginx()
+func (pg *PGStore) UseRecoveryCode(ctx context.Context, workerId model.WorkerID, hash string) error {
+	result, err := pg.conn().ExecContext(ctx, useRecoveryCode, workerId, hash)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		switch err {
-		case nil:
-			err = tx.Commit()
-		default:
-			tx.Rollback()
-		}
-	}()
-
-	worker := &model.Worker{}
-	err = tx.Get(worker, workerById, workerId)
-	if err == sql.ErrNoRows {
-		return errors.New("unknown worker ID")
-	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows != 1 {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
 
-	shift := &model.Shift{}
-	err = tx.Get(shift, shiftById, shiftId)
+const useRecoveryCode = "DELETE FROM recovery_code WHERE worker_id = $1 AND code_hash = $2"
+
+func (pg *PGStore) GetLoginAttempts(ctx context.Context, key string) (*model.LoginAttempts, error) {
+	attempts := &model.LoginAttempts{}
+	err := pg.conn().GetContext(ctx, attempts, loginAttemptsByKey, key)
 	if err == sql.ErrNoRows {
-		return errors.New("unknown shift ID")
+		return &model.LoginAttempts{Key: key}, nil
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return attempts, nil
+}
 
-	shifts, err := pg.GetShifts(nil, WeekSpan, &workerId)
-	if err != nil {
-		return ErrRetrievingWorkerShifts
-	}
+const loginAttemptsByKey = `
+SELECT key, failures, last_failure, locked_until FROM login_attempt WHERE key = $1`
 
-	if !domain.NewShiftAssignmentOK(shifts, shift) {
-		return ErrTwoShiftsSameDay
+// The count is updated in a single statement, so that failures at the
+// same time are all counted.
+func (pg *PGStore) RecordLoginFailure(ctx context.Context, key string,
+	window time.Duration) (*model.LoginAttempts, error) {
+	attempts := &model.LoginAttempts{}
+	now := time.Now()
+	_, err := pg.conn().ExecContext(ctx, pruneLoginAttempts, now.Add(-window), now)
+	if err != nil {
+		return nil, err
 	}
-
-	assignment := &model.ShiftAssignment{Worker: workerId, Shift: shiftId}
-	rows, err := tx.NamedQuery(createShiftAssignment, assignment)
+	err = pg.conn().GetContext(ctx, attempts, recordLoginFailure, key, now, now.Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+const pruneLoginAttempts = `
+DELETE FROM login_attempt
+ WHERE last_failure < $1 AND (locked_until IS NULL OR locked_until <= $2)`
+
+const recordLoginFailure = `
+INSERT INTO login_attempt (key, failures, last_failure) VALUES ($1, 1, $2)
+ON CONFLICT (key) DO UPDATE
+  SET failures = CASE
+        WHEN login_attempt.last_failure < $3 THEN 1
+        ELSE login_attempt.failures + 1
+      END,
+      last_failure = $2
+RETURNING key, failures, last_failure, locked_until`
+
+func (pg *PGStore) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := pg.conn().ExecContext(ctx, lockLogin, key, until)
+	return err
+}
+
+const lockLogin = `
+INSERT INTO login_attempt (key, locked_until) VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE SET locked_until = EXCLUDED.locked_until`
+
+func (pg *PGStore) ClearLoginAttempts(ctx context.Context, key string) error {
+	_, err := pg.conn().ExecContext(ctx, clearLoginAttempts, key)
+	return err
+}
+
+const clearLoginAttempts = "DELETE FROM login_attempt WHERE key = $1"
+
+func (pg *PGStore) CreateLockout(ctx context.Context, lockout *model.Lockout) error {
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createLockout, lockout)
 	if err != nil {
 		return err
 	}
@@ -418,16 +673,207 @@ func (pg *PGStore) CreateShiftAssignment(workerId model.WorkerID, shiftId model.
 		return sql.ErrNoRows
 	}
 
-	return nil
+	return rows.Scan(&lockout.ID)
 }
 
-const createShiftAssignment = `
-INSERT INTO shift_assignment (worker_id, shift_id)
-     VALUES (:worker_id, :shift_id)
+const createLockout = `
+INSERT INTO lockout (kind, subject, worker_id, failures, locked_at, locked_until)
+VALUES (:kind, :subject, :worker_id, :failures, :locked_at, :locked_until)
+RETURNING id`
+
+func (pg *PGStore) GetLockouts(ctx context.Context) ([]*model.Lockout, error) {
+	results := []*model.Lockout{}
+	if err := pg.conn().SelectContext(ctx, &results, getLockouts); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getLockouts = `
+SELECT id, kind, subject, worker_id, failures, locked_at, locked_until
+  FROM lockout
+ ORDER BY id DESC`
+
+func (pg *PGStore) GetWorkers(ctx context.Context, teamId *model.TeamID) ([]*model.Worker, error) {
+	results := []*model.Worker{}
+	var err error
+	if teamId != nil {
+		err = pg.conn().SelectContext(ctx, &results, teamWorkers, *teamId)
+	} else {
+		err = pg.conn().SelectContext(ctx, &results, getWorkers+" ORDER BY id")
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadWorkerDetails(ctx, pg.conn(), results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getWorkers = `SELECT id, email, name, is_admin FROM worker`
+
+const teamWorkers = getWorkers + `
+ WHERE id IN (SELECT worker_id FROM team_member WHERE team_id = $1)
+ ORDER BY id`
+
+func (pg *PGStore) GetWorkerById(ctx context.Context, id model.WorkerID) (*model.Worker, error) {
+	worker := &model.Worker{}
+	err := pg.conn().GetContext(ctx, worker, workerById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrWorkerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadWorkerDetails(ctx, pg.conn(), []*model.Worker{worker})
+	if err != nil {
+		return nil, err
+	}
+	return worker, nil
+}
+
+const workerById = `
+SELECT id, email, name, is_admin, password
+  FROM worker
+ WHERE id = $1`
+
+func (pg *PGStore) GetWorkerByEmail(ctx context.Context, email string) (*model.Worker, error) {
+	worker := &model.Worker{}
+	err := pg.conn().GetContext(ctx, worker, workerByEmail, email)
+	if err == sql.ErrNoRows {
+		return nil, ErrUnknownWorkerEmail
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadWorkerDetails(ctx, pg.conn(), []*model.Worker{worker})
+	if err != nil {
+		return nil, err
+	}
+	return worker, nil
+}
+
+func (pg *PGStore) CreateWorker(ctx context.Context, worker *model.Worker) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	stored := *worker
+	stored.Password = hashPassword(worker.Password)
+
+	// The email's uniqueness is checked by the database, and SQLite
+	// only reports the failure when the new row is read.
+	rows, err := sqlx.NamedQueryContext(ctx, tx, createWorker, &stored)
+	if err == nil {
+		defer rows.Close()
+		if !rows.Next() {
+			err = rows.Err()
+			if err == nil {
+				err = sql.ErrNoRows
+			}
+		}
+	}
+	if uniqueViolation(err, "worker_email_idx", "worker.email") {
+		err = ErrWorkerEmailExists
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	err = rows.Scan(&worker.ID)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	err = saveWorkerDetails(ctx, tx, worker)
+	return err
+}
+
+const createWorker = `
+INSERT INTO worker (email, name, is_admin, password)
+     VALUES (:email, :name, :is_admin, :password)
 RETURNING id`
 
-func (pg *PGStore) DeleteShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error {
-	result, err := pg.db.Exec(deleteShiftAssignment, workerId, shiftId)
+func (pg *PGStore) UpdateWorker(ctx context.Context, worker *model.Worker) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	stored := *worker
+	stored.Password = hashPassword(worker.Password)
+
+	result, err := tx.NamedExecContext(ctx, updateWorker, &stored)
+	if uniqueViolation(err, "worker_email_idx", "worker.email") {
+		err = ErrWorkerEmailExists
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		err = ErrWorkerNotFound
+		return err
+	}
+
+	err = saveWorkerDetails(ctx, tx, worker)
+	return err
+}
+
+// An empty password keeps the current one.
+const updateWorker = `
+UPDATE worker
+   SET email = :email, name = :name, is_admin = :is_admin,
+       password = COALESCE(NULLIF(:password, ''), password)
+WHERE id = :id`
+
+func (pg *PGStore) DeleteWorkerById(ctx context.Context, id model.WorkerID) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	// The worker's places on shifts go to the shifts' waitlists.
+	freed := []model.ShiftID{}
+	err = tx.SelectContext(ctx, &freed, workerAssignedShifts, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, deleteWorker, id)
 	if err != nil {
 		return err
 	}
@@ -436,11 +882,1955 @@ func (pg *PGStore) DeleteShiftAssignment(workerId model.WorkerID, shiftId model.
 		return err
 	}
 	if rows != 1 {
-		return errors.New("failed to delete shift assignment")
+		err = ErrWorkerNotFound
+		return err
+	}
+
+	for _, shiftId := range freed {
+		err = promoteWaitlist(ctx, tx, shiftId)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-const deleteShiftAssignment = `
-DELETE FROM shift_assignment
- WHERE worker_id = $1 AND shift_id = $2`
+const workerAssignedShifts = `
+SELECT shift_id FROM shift_assignment WHERE worker_id = $1 ORDER BY shift_id`
+
+const deleteWorker = "DELETE FROM worker WHERE id = $1"
+
+// Select rows for a list of IDs, expanding the "IN (?)" in a query to
+// a parameter for each ID.
+func selectIn(ctx context.Context, q sqlx.ExtContext, dest interface{}, query string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return err
+	}
+	return sqlx.SelectContext(ctx, q, dest, q.Rebind(query), args...)
+}
+
+// Load the skills, team memberships and roles for a list of workers.
+func loadWorkerDetails(ctx context.Context, q sqlx.ExtContext, workers []*model.Worker) error {
+	ids := make([]int64, len(workers))
+	byId := make(map[model.WorkerID]*model.Worker, len(workers))
+	for i, w := range workers {
+		ids[i] = int64(w.ID)
+		byId[w.ID] = w
+		w.Skills = []string{}
+		w.Teams = []model.Membership{}
+		w.Roles = []model.RoleID{}
+	}
+
+	skills := []struct {
+		Worker model.WorkerID `db:"worker_id"`
+		Skill  string         `db:"skill"`
+	}{}
+	err := selectIn(ctx, q, &skills, getWorkerSkills, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range skills {
+		w := byId[r.Worker]
+		w.Skills = append(w.Skills, r.Skill)
+	}
+
+	teams := []struct {
+		Worker model.WorkerID `db:"worker_id"`
+		model.Membership
+	}{}
+	err = selectIn(ctx, q, &teams, getWorkerTeams, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range teams {
+		w := byId[r.Worker]
+		w.Teams = append(w.Teams, r.Membership)
+	}
+
+	roles := []struct {
+		Worker model.WorkerID `db:"worker_id"`
+		Role   model.RoleID   `db:"role_id"`
+	}{}
+	err = selectIn(ctx, q, &roles, getWorkerRoles, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		w := byId[r.Worker]
+		w.Roles = append(w.Roles, r.Role)
+	}
+	return nil
+}
+
+const getWorkerSkills = `
+SELECT worker_id, skill FROM worker_skill
+ WHERE worker_id IN (?)
+ ORDER BY worker_id, skill`
+
+const getWorkerTeams = `
+SELECT worker_id, team_id, is_admin FROM team_member
+ WHERE worker_id IN (?)
+ ORDER BY worker_id, team_id`
+
+const getWorkerRoles = `
+SELECT worker_id, role_id FROM worker_role
+ WHERE worker_id IN (?)
+ ORDER BY worker_id, role_id`
+
+// Replace a worker's skills, team memberships and roles within a
+// transaction.
+func saveWorkerDetails(ctx context.Context, tx *pgTx, worker *model.Worker) error {
+	_, err := tx.ExecContext(ctx, deleteWorkerSkills, worker.ID)
+	if err != nil {
+		return err
+	}
+	for _, skill := range worker.Skills {
+		_, err = tx.ExecContext(ctx, createWorkerSkill, worker.ID, skill)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, deleteWorkerTeams, worker.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range worker.Teams {
+		_, err = tx.ExecContext(ctx, setTeamMember, m.Team, worker.ID, m.IsAdmin)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, deleteWorkerRoles, worker.ID)
+	if err != nil {
+		return err
+	}
+	for _, r := range worker.Roles {
+		_, err = tx.ExecContext(ctx, createWorkerRole, worker.ID, r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const deleteWorkerSkills = "DELETE FROM worker_skill WHERE worker_id = $1"
+
+const createWorkerSkill = `
+INSERT INTO worker_skill (worker_id, skill) VALUES ($1, $2)`
+
+const deleteWorkerTeams = "DELETE FROM team_member WHERE worker_id = $1"
+
+const deleteWorkerRoles = "DELETE FROM worker_role WHERE worker_id = $1"
+
+const createWorkerRole = `
+INSERT INTO worker_role (worker_id, role_id) VALUES ($1, $2)`
+
+func (pg *PGStore) GetShifts(ctx context.Context, date *time.Time, span TimeSpan,
+	workerId *model.WorkerID, teamId *model.TeamID) ([]*model.Shift, error) {
+	// Calculate interval start and end from date and span.
+	var intStart, intEnd time.Time
+	includeAll := date == nil
+	if !includeAll {
+		intStart, intEnd = SpanRange(date, span)
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	if workerId != nil {
+		args = append(args, *workerId)
+		cond := fmt.Sprintf("id IN (SELECT shift_id FROM shift_assignment WHERE worker_id = $%d)", len(args))
+		conditions = append(conditions, cond)
+	}
+	if !includeAll {
+		args = append(args, intEnd, intStart)
+		cond := fmt.Sprintf("start_time < $%d AND end_time > $%d", len(args)-1, len(args))
+		conditions = append(conditions, cond)
+	}
+	if teamId != nil {
+		args = append(args, *teamId)
+		cond := fmt.Sprintf("team_id = $%d", len(args))
+		conditions = append(conditions, cond)
+	}
+	q := getShifts
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	q += " ORDER BY start_time, id"
+
+	results := []*model.Shift{}
+	var err error
+	err = pg.conn().SelectContext(ctx, &results, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	err = loadShiftRequirements(ctx, pg.conn(), results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getShifts = `SELECT id, start_time, end_time, capacity, team_id, bidding_closed FROM shift`
+
+func (pg *PGStore) GetShiftsInRange(ctx context.Context, start time.Time, end time.Time) ([]*model.Shift, error) {
+	results := []*model.Shift{}
+	err := pg.conn().SelectContext(ctx, &results, shiftsInRange, start, end)
+	if err != nil {
+		return nil, err
+	}
+	err = loadShiftRequirements(ctx, pg.conn(), results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const shiftsInRange = getShifts + `
+ WHERE start_time < $2 AND end_time > $1
+ ORDER BY start_time, id`
+
+func (pg *PGStore) GetShiftById(ctx context.Context, id model.ShiftID) (*model.Shift, error) {
+	shift := &model.Shift{}
+	err := pg.conn().GetContext(ctx, shift, shiftById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadShiftRequirements(ctx, pg.conn(), []*model.Shift{shift})
+	if err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+const shiftById = `
+SELECT id, start_time, end_time, capacity, team_id, bidding_closed
+  FROM shift
+ WHERE id = $1`
+
+func (pg *PGStore) CreateShift(ctx context.Context, shift *model.Shift) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	rows, err := sqlx.NamedQueryContext(ctx, tx, createShift, shift)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	err = rows.Scan(&shift.ID)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	err = saveShiftRequirements(ctx, tx, shift)
+	return err
+}
+
+const createShift = `
+INSERT INTO shift (start_time, end_time, capacity, team_id)
+     VALUES (:start_time, :end_time, :capacity, :team_id)
+RETURNING id`
+
+func (pg *PGStore) CreateShifts(ctx context.Context, shifts []*model.Shift) (_ []*model.Shift, err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	// Stop concurrent updates creating the same shifts.
+	if pg.locking {
+		_, err = tx.ExecContext(ctx, "LOCK TABLE shift IN SHARE ROW EXCLUSIVE MODE")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	created := []*model.Shift{}
+	for _, shift := range shifts {
+		err = tx.GetContext(ctx, &shift.ID, shiftByTimes, shift.StartTime, shift.EndTime, shift.Team)
+		if err == nil {
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		err = tx.QueryRowxContext(ctx, createShiftRow,
+			shift.StartTime, shift.EndTime, shift.Capacity, shift.Team).Scan(&shift.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = saveShiftRequirements(ctx, tx, shift)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, shift)
+	}
+
+	return created, nil
+}
+
+const shiftByTimes = `
+SELECT id FROM shift
+ WHERE start_time = $1 AND end_time = $2 AND team_id IS NOT DISTINCT FROM $3
+ LIMIT 1`
+
+const createShiftRow = `
+INSERT INTO shift (start_time, end_time, capacity, team_id) VALUES ($1, $2, $3, $4)
+RETURNING id`
+
+func (pg *PGStore) UpdateShift(ctx context.Context, shift *model.Shift) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.NamedExecContext(ctx, updateShift, shift)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		err = ErrShiftNotFound
+		return err
+	}
+
+	err = saveShiftRequirements(ctx, tx, shift)
+	return err
+}
+
+const updateShift = `
+UPDATE shift
+   SET start_time = :start_time, end_time = :end_time,
+       capacity = :capacity, team_id = :team_id
+WHERE id = :id`
+
+func (pg *PGStore) DeleteShiftById(ctx context.Context, id model.ShiftID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteShift, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrShiftNotFound
+	}
+	return nil
+}
+
+const deleteShift = "DELETE FROM shift WHERE id = $1"
+
+// Load the skill requirements for a list of shifts.
+func loadShiftRequirements(ctx context.Context, q sqlx.ExtContext, shifts []*model.Shift) error {
+	ids := make([]int64, len(shifts))
+	byId := make(map[model.ShiftID]*model.Shift, len(shifts))
+	for i, s := range shifts {
+		ids[i] = int64(s.ID)
+		byId[s.ID] = s
+		s.Requirements = []model.Requirement{}
+	}
+
+	rows := []struct {
+		Shift model.ShiftID `db:"shift_id"`
+		model.Requirement
+	}{}
+	err := selectIn(ctx, q, &rows, getShiftRequirements, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		s := byId[r.Shift]
+		s.Requirements = append(s.Requirements, r.Requirement)
+	}
+	return nil
+}
+
+const getShiftRequirements = `
+SELECT shift_id, skill, count FROM shift_requirement
+ WHERE shift_id IN (?)
+ ORDER BY shift_id, skill`
+
+// Replace a shift's skill requirements within a transaction.
+func saveShiftRequirements(ctx context.Context, tx *pgTx, shift *model.Shift) error {
+	_, err := tx.ExecContext(ctx, deleteShiftRequirements, shift.ID)
+	if err != nil {
+		return err
+	}
+	for _, r := range shift.Requirements {
+		_, err = tx.ExecContext(ctx, createShiftRequirement, shift.ID, r.Skill, r.Count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const deleteShiftRequirements = "DELETE FROM shift_requirement WHERE shift_id = $1"
+
+const createShiftRequirement = `
+INSERT INTO shift_requirement (shift_id, skill, count) VALUES ($1, $2, $3)`
+
+func (pg *PGStore) GetTeams(ctx context.Context) ([]*model.Team, error) {
+	results := []*model.Team{}
+	if err := pg.conn().SelectContext(ctx, &results, getTeams+" ORDER BY id"); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getTeams = `SELECT id, name FROM team`
+
+func (pg *PGStore) GetTeamById(ctx context.Context, id model.TeamID) (*model.Team, error) {
+	team := &model.Team{}
+	err := pg.conn().GetContext(ctx, team, teamById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+const teamById = getTeams + " WHERE id = $1"
+
+func (pg *PGStore) CreateTeam(ctx context.Context, team *model.Team) error {
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createTeam, team)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	return rows.Scan(&team.ID)
+}
+
+const createTeam = `
+INSERT INTO team (name) VALUES (:name)
+RETURNING id`
+
+// Deleting a team cascades to its shifts, shift templates and
+// memberships.
+func (pg *PGStore) DeleteTeamById(ctx context.Context, id model.TeamID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteTeam, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+const deleteTeam = "DELETE FROM team WHERE id = $1"
+
+func (pg *PGStore) SetTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID, isAdmin bool) error {
+	var exists bool
+	err := pg.conn().GetContext(ctx, &exists, teamExists, teamId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrTeamNotFound
+	}
+	err = pg.conn().GetContext(ctx, &exists, workerExists, workerId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWorkerNotFound
+	}
+
+	_, err = pg.conn().ExecContext(ctx, setTeamMember, teamId, workerId, isAdmin)
+	return err
+}
+
+const teamExists = "SELECT EXISTS (SELECT 1 FROM team WHERE id = $1)"
+
+const workerExists = "SELECT EXISTS (SELECT 1 FROM worker WHERE id = $1)"
+
+const setTeamMember = `
+INSERT INTO team_member (team_id, worker_id, is_admin) VALUES ($1, $2, $3)
+ON CONFLICT (team_id, worker_id) DO UPDATE SET is_admin = EXCLUDED.is_admin`
+
+func (pg *PGStore) DeleteTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteTeamMember, teamId, workerId)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+const deleteTeamMember = "DELETE FROM team_member WHERE team_id = $1 AND worker_id = $2"
+
+func (pg *PGStore) GetRoles(ctx context.Context) ([]*model.Role, error) {
+	results := []*model.Role{}
+	if err := pg.conn().SelectContext(ctx, &results, getRoles+" ORDER BY id"); err != nil {
+		return nil, err
+	}
+	err := loadRolePermissions(ctx, pg.conn(), results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getRoles = `SELECT id, name FROM role`
+
+func (pg *PGStore) GetRoleById(ctx context.Context, id model.RoleID) (*model.Role, error) {
+	role := &model.Role{}
+	err := pg.conn().GetContext(ctx, role, roleById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = loadRolePermissions(ctx, pg.conn(), []*model.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+const roleById = getRoles + " WHERE id = $1"
+
+func (pg *PGStore) CreateRole(ctx context.Context, role *model.Role) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.GetContext(ctx, &role.ID, createRole, role.Name)
+	if err != nil {
+		return err
+	}
+	err = saveRolePermissions(ctx, tx, role)
+	return err
+}
+
+const createRole = `
+INSERT INTO role (name) VALUES ($1)
+RETURNING id`
+
+func (pg *PGStore) UpdateRole(ctx context.Context, role *model.Role) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, updateRole, role.ID, role.Name)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		err = ErrRoleNotFound
+		return err
+	}
+	err = saveRolePermissions(ctx, tx, role)
+	return err
+}
+
+const updateRole = "UPDATE role SET name = $2 WHERE id = $1"
+
+// Deleting a role cascades to its permissions and to workers' roles.
+func (pg *PGStore) DeleteRoleById(ctx context.Context, id model.RoleID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteRole, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+const deleteRole = "DELETE FROM role WHERE id = $1"
+
+// Load the permissions for a list of roles.
+func loadRolePermissions(ctx context.Context, q sqlx.ExtContext, roles []*model.Role) error {
+	ids := make([]int64, len(roles))
+	byId := make(map[model.RoleID]*model.Role, len(roles))
+	for i, r := range roles {
+		ids[i] = int64(r.ID)
+		byId[r.ID] = r
+		r.Permissions = []model.Permission{}
+	}
+
+	rows := []struct {
+		Role       model.RoleID     `db:"role_id"`
+		Permission model.Permission `db:"permission"`
+	}{}
+	err := selectIn(ctx, q, &rows, getRolePermissions, ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		role := byId[r.Role]
+		role.Permissions = append(role.Permissions, r.Permission)
+	}
+	return nil
+}
+
+const getRolePermissions = `
+SELECT role_id, permission FROM role_permission
+ WHERE role_id IN (?)
+ ORDER BY role_id, permission`
+
+// Replace a role's permissions within a transaction.
+func saveRolePermissions(ctx context.Context, tx *pgTx, role *model.Role) error {
+	_, err := tx.ExecContext(ctx, deleteRolePermissions, role.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range role.Permissions {
+		_, err = tx.ExecContext(ctx, createRolePermission, role.ID, p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const deleteRolePermissions = "DELETE FROM role_permission WHERE role_id = $1"
+
+const createRolePermission = `
+INSERT INTO role_permission (role_id, permission) VALUES ($1, $2)`
+
+func (pg *PGStore) GetShiftTemplates(ctx context.Context) ([]*model.ShiftTemplate, error) {
+	results := []*model.ShiftTemplate{}
+	if err := pg.conn().SelectContext(ctx, &results, getShiftTemplates+" ORDER BY id"); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getShiftTemplates = `
+SELECT id, name, weekdays, start_hour, end_hour, capacity, team_id
+  FROM shift_template`
+
+func (pg *PGStore) GetShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) (*model.ShiftTemplate, error) {
+	template := &model.ShiftTemplate{}
+	err := pg.conn().GetContext(ctx, template, shiftTemplateById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+const shiftTemplateById = getShiftTemplates + " WHERE id = $1"
+
+func (pg *PGStore) CreateShiftTemplate(ctx context.Context, template *model.ShiftTemplate) error {
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createShiftTemplate, template)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	return rows.Scan(&template.ID)
+}
+
+const createShiftTemplate = `
+INSERT INTO shift_template (name, weekdays, start_hour, end_hour, capacity, team_id)
+     VALUES (:name, :weekdays, :start_hour, :end_hour, :capacity, :team_id)
+RETURNING id`
+
+func (pg *PGStore) DeleteShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteShiftTemplate, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrShiftTemplateNotFound
+	}
+	return nil
+}
+
+const deleteShiftTemplate = "DELETE FROM shift_template WHERE id = $1"
+
+func (pg *PGStore) GetShiftAssignmentsInRange(ctx context.Context,
+	start time.Time, end time.Time) ([]model.ShiftAssignment, error) {
+	results := []model.ShiftAssignment{}
+	err := pg.conn().SelectContext(ctx, &results, shiftAssignmentsInRange, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const shiftAssignmentsInRange = `
+SELECT a.worker_id, a.shift_id
+  FROM shift_assignment a JOIN shift s ON a.shift_id = s.id
+ WHERE s.start_time < $2 AND s.end_time > $1`
+
+func (pg *PGStore) CreateShiftAssignment(ctx context.Context, workerId model.WorkerID,
+	shiftId model.ShiftID, override *model.RuleOverride) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	soft, err := addShiftAssignment(ctx, tx, workerId, shiftId, override != nil)
+	if err != nil {
+		return err
+	}
+
+	err = recordOverride(ctx, tx, override, workerId, shiftId, soft)
+	return err
+}
+
+func (pg *PGStore) CreateShiftAssignments(ctx context.Context, assignments []model.ShiftAssignment) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	for _, a := range assignments {
+		_, err = addShiftAssignment(ctx, tx, a.Worker, a.Shift, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check and insert a new shift assignment within a transaction,
+// returning any soft rule violations, which are only allowed if
+// allowSoft is set.
+func addShiftAssignment(ctx context.Context, tx *pgTx, workerId model.WorkerID,
+	shiftId model.ShiftID, allowSoft bool) ([]*domain.Violation, error) {
+	// Locking the worker and the shift stops concurrent assignments
+	// getting past the capacity and scheduling rule checks together.
+	worker := &model.Worker{}
+	err := tx.GetContext(ctx, worker, tx.forUpdate(workerById), workerId)
+	if err == sql.ErrNoRows {
+		return nil, ErrWorkerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shift := &model.Shift{}
+	err = tx.GetContext(ctx, shift, tx.forUpdate(shiftById), shiftId)
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadWorkerDetails(ctx, tx, []*model.Worker{worker})
+	if err != nil {
+		return nil, err
+	}
+	if !worker.InTeam(shift.Team) {
+		return nil, ErrNotTeamMember
+	}
+
+	// Checked before the rules, which may not stop it.
+	var assigned int
+	err = tx.GetContext(ctx, &assigned, workerAssignmentCount, workerId, shiftId)
+	if err != nil {
+		return nil, err
+	}
+	if assigned > 0 {
+		return nil, ErrAlreadyAssigned
+	}
+
+	staff := []*model.Worker{}
+	err = tx.SelectContext(ctx, &staff, shiftWorkers, shiftId)
+	if err != nil {
+		return nil, err
+	}
+	if len(staff) >= shift.Capacity {
+		return nil, ErrShiftAtCapacity
+	}
+	err = loadWorkerDetails(ctx, tx, staff)
+	if err != nil {
+		return nil, err
+	}
+	staff = append(staff, worker)
+	err = loadShiftRequirements(ctx, tx, []*model.Shift{shift})
+	if err != nil {
+		return nil, err
+	}
+	if !domain.Staffable(shift, staff) {
+		return nil, ErrRequirementsUnmet
+	}
+
+	shifts := []*model.Shift{}
+	err = tx.SelectContext(ctx, &shifts, workerShifts, workerId)
+	if err != nil {
+		return nil, ErrRetrievingWorkerShifts
+	}
+
+	var onLeave int
+	err = tx.GetContext(ctx, &onLeave, approvedTimeOffCount, workerId, shift.StartTime, shift.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if onLeave > 0 {
+		return nil, ErrWorkerOnLeave
+	}
+
+	rules := domain.DefaultRules
+	ruleSet, err := ruleSetAt(ctx, tx, time.Now())
+	if err != nil && err != ErrRuleSetNotFound {
+		return nil, err
+	}
+	if ruleSet != nil {
+		rules, err = domain.NewRuleSet(ruleSet.Rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	soft := []*domain.Violation{}
+	for _, v := range rules.Violations(shifts, shift) {
+		if v.Hard || !allowSoft {
+			return nil, &RuleViolationError{Violation: v}
+		}
+		soft = append(soft, v)
+	}
+
+	_, err = tx.ExecContext(ctx, createShiftAssignment, workerId, shiftId)
+	if err != nil {
+		return nil, err
+	}
+	return soft, nil
+}
+
+// Record an admin override within a transaction if any soft rules
+// were broken.
+func recordOverride(ctx context.Context, tx *pgTx, override *model.RuleOverride,
+	workerId model.WorkerID, shiftId model.ShiftID, soft []*domain.Violation) error {
+	if override == nil || len(soft) == 0 {
+		return nil
+	}
+
+	override.Worker = workerId
+	override.Shift = shiftId
+	override.Violations = describeViolations(soft)
+	rows, err := sqlx.NamedQueryContext(ctx, tx, createRuleOverride, override)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	return rows.Scan(&override.ID, &override.CreatedAt)
+}
+
+const createRuleOverride = `
+INSERT INTO rule_override (worker_id, shift_id, admin_id, reason, violations)
+     VALUES (:worker_id, :shift_id, :admin_id, :reason, :violations)
+RETURNING id, created_at`
+
+const shiftAssignmentCount = `
+SELECT COUNT(*) FROM shift_assignment WHERE shift_id = $1`
+
+const shiftWorkers = `
+SELECT w.id, w.email, w.name, w.is_admin
+  FROM worker w JOIN shift_assignment a ON a.worker_id = w.id
+ WHERE a.shift_id = $1`
+
+const workerShifts = `
+SELECT s.id, s.start_time, s.end_time, s.capacity, s.team_id
+  FROM shift s JOIN shift_assignment a ON a.shift_id = s.id
+ WHERE a.worker_id = $1`
+
+const approvedTimeOffCount = `
+SELECT COUNT(*) FROM time_off
+ WHERE worker_id = $1 AND status = 'approved'
+   AND start_time < $3 AND end_time > $2`
+
+const createShiftAssignment = `
+INSERT INTO shift_assignment (worker_id, shift_id) VALUES ($1, $2)`
+
+func (pg *PGStore) DeleteShiftAssignment(ctx context.Context, workerId model.WorkerID, shiftId model.ShiftID) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	err = deleteShiftAssignmentTx(ctx, tx, workerId, shiftId)
+	if err != nil {
+		return err
+	}
+
+	err = promoteWaitlist(ctx, tx, shiftId)
+	return err
+}
+
+const deleteShiftAssignment = `
+DELETE FROM shift_assignment
+ WHERE worker_id = $1 AND shift_id = $2`
+
+func (pg *PGStore) MoveShiftAssignment(ctx context.Context, workerId model.WorkerID,
+	from model.ShiftID, to model.ShiftID, override *model.RuleOverride) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	err = deleteShiftAssignmentTx(ctx, tx, workerId, from)
+	if err != nil {
+		return err
+	}
+
+	soft, err := addShiftAssignment(ctx, tx, workerId, to, override != nil)
+	if err != nil {
+		return err
+	}
+
+	err = recordOverride(ctx, tx, override, workerId, to, soft)
+	if err != nil {
+		return err
+	}
+
+	err = promoteWaitlist(ctx, tx, from)
+	return err
+}
+
+func (pg *PGStore) ReplaceShiftAssignments(ctx context.Context,
+	remove []model.ShiftAssignment, add []model.ShiftAssignment) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	for _, a := range remove {
+		err = deleteShiftAssignmentTx(ctx, tx, a.Worker, a.Shift)
+		if err != nil {
+			return err
+		}
+	}
+	for _, a := range add {
+		_, err = addShiftAssignment(ctx, tx, a.Worker, a.Shift, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The additions get first pick of the places freed by the removals,
+	// and waitlisted workers get the rest.
+	for _, a := range remove {
+		err = promoteWaitlist(ctx, tx, a.Shift)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete a shift assignment within a transaction.
+func deleteShiftAssignmentTx(ctx context.Context, tx *pgTx, workerId model.WorkerID, shiftId model.ShiftID) error {
+	result, err := tx.ExecContext(ctx, deleteShiftAssignment, workerId, shiftId)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrShiftAssignmentNotFound
+	}
+	return nil
+}
+
+func (pg *PGStore) GetRuleOverrides(ctx context.Context, shiftId *model.ShiftID) ([]*model.RuleOverride, error) {
+	results := []*model.RuleOverride{}
+	var err error
+	if shiftId == nil {
+		err = pg.conn().SelectContext(ctx, &results, getRuleOverrides+" ORDER BY id")
+	} else {
+		err = pg.conn().SelectContext(ctx, &results, getRuleOverrides+" WHERE shift_id = $1 ORDER BY id", *shiftId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getRuleOverrides = `
+SELECT id, worker_id, shift_id, admin_id, reason, violations, created_at
+  FROM rule_override`
+
+func (pg *PGStore) GetShiftPreferences(ctx context.Context, workerId *model.WorkerID) ([]*model.ShiftPreference, error) {
+	results := []*model.ShiftPreference{}
+	var err error
+	if workerId == nil {
+		err = pg.conn().SelectContext(ctx, &results, getShiftPreferences+" ORDER BY id")
+	} else {
+		err = pg.conn().SelectContext(ctx, &results, getShiftPreferences+" WHERE worker_id = $1 ORDER BY id", *workerId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getShiftPreferences = `
+SELECT id, worker_id, shift_id, weekday, start_hour, weight
+  FROM shift_preference`
+
+func (pg *PGStore) GetShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) (*model.ShiftPreference, error) {
+	pref := &model.ShiftPreference{}
+	err := pg.conn().GetContext(ctx, pref, shiftPreferenceById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrShiftPreferenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+const shiftPreferenceById = getShiftPreferences + " WHERE id = $1"
+
+func (pg *PGStore) CreateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error {
+	var exists bool
+	err := pg.conn().GetContext(ctx, &exists, workerExists, pref.Worker)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWorkerNotFound
+	}
+	err = pg.checkPreferenceShift(ctx, pref)
+	if err != nil {
+		return err
+	}
+
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createShiftPreference, pref)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	return rows.Scan(&pref.ID)
+}
+
+const createShiftPreference = `
+INSERT INTO shift_preference (worker_id, shift_id, weekday, start_hour, weight)
+     VALUES (:worker_id, :shift_id, :weekday, :start_hour, :weight)
+RETURNING id`
+
+func (pg *PGStore) UpdateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error {
+	err := pg.checkPreferenceShift(ctx, pref)
+	if err != nil {
+		return err
+	}
+
+	result, err := pg.conn().NamedExecContext(ctx, updateShiftPreference, pref)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrShiftPreferenceNotFound
+	}
+	return nil
+}
+
+func (pg *PGStore) checkPreferenceShift(ctx context.Context, pref *model.ShiftPreference) error {
+	if pref.Shift == nil {
+		return nil
+	}
+	var exists bool
+	err := pg.conn().GetContext(ctx, &exists, shiftExists, *pref.Shift)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrShiftNotFound
+	}
+	return nil
+}
+
+const shiftExists = "SELECT EXISTS (SELECT 1 FROM shift WHERE id = $1)"
+
+const updateShiftPreference = `
+UPDATE shift_preference
+   SET shift_id = :shift_id, weekday = :weekday,
+       start_hour = :start_hour, weight = :weight
+ WHERE id = :id`
+
+func (pg *PGStore) DeleteShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteShiftPreference, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrShiftPreferenceNotFound
+	}
+	return nil
+}
+
+const deleteShiftPreference = "DELETE FROM shift_preference WHERE id = $1"
+
+func (pg *PGStore) GetTimeOff(ctx context.Context, workerId *model.WorkerID, status *model.TimeOffStatus) ([]*model.TimeOff, error) {
+	conds := []string{}
+	args := []interface{}{}
+	if workerId != nil {
+		args = append(args, *workerId)
+		conds = append(conds, fmt.Sprintf("worker_id = $%d", len(args)))
+	}
+	if status != nil {
+		args = append(args, *status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	q := getTimeOff
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	results := []*model.TimeOff{}
+	if err := pg.conn().SelectContext(ctx, &results, q+" ORDER BY start_time", args...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getTimeOff = `
+SELECT id, worker_id, start_time, end_time, reason, status
+  FROM time_off`
+
+func (pg *PGStore) GetTimeOffById(ctx context.Context, id model.TimeOffID) (*model.TimeOff, error) {
+	timeOff := &model.TimeOff{}
+	err := pg.conn().GetContext(ctx, timeOff, timeOffById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrTimeOffNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return timeOff, nil
+}
+
+const timeOffById = getTimeOff + " WHERE id = $1"
+
+func (pg *PGStore) CreateTimeOff(ctx context.Context, timeOff *model.TimeOff) error {
+	var exists bool
+	err := pg.conn().GetContext(ctx, &exists, workerExists, timeOff.Worker)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWorkerNotFound
+	}
+
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createTimeOff, timeOff)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return sql.ErrNoRows
+	}
+
+	return rows.Scan(&timeOff.ID)
+}
+
+const createTimeOff = `
+INSERT INTO time_off (worker_id, start_time, end_time, reason, status)
+     VALUES (:worker_id, :start_time, :end_time, :reason, :status)
+RETURNING id`
+
+func (pg *PGStore) UpdateTimeOffStatus(ctx context.Context, id model.TimeOffID, status model.TimeOffStatus) error {
+	result, err := pg.conn().ExecContext(ctx, updateTimeOffStatus, id, status)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrTimeOffNotFound
+	}
+	return nil
+}
+
+const updateTimeOffStatus = "UPDATE time_off SET status = $2 WHERE id = $1"
+
+func (pg *PGStore) DeleteTimeOffById(ctx context.Context, id model.TimeOffID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteTimeOff, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrTimeOffNotFound
+	}
+	return nil
+}
+
+const deleteTimeOff = "DELETE FROM time_off WHERE id = $1"
+
+func (pg *PGStore) GetRuleSets(ctx context.Context) ([]*model.RuleSet, error) {
+	ruleSets := []*model.RuleSet{}
+	err := pg.conn().SelectContext(ctx, &ruleSets, getRuleSets+" ORDER BY effective_from, id")
+	if err != nil {
+		return nil, err
+	}
+
+	configs := []ruleConfigRow{}
+	err = pg.conn().SelectContext(ctx, &configs, getRuleConfigs+" ORDER BY rule_set_id, position")
+	if err != nil {
+		return nil, err
+	}
+	byId := map[model.RuleSetID]*model.RuleSet{}
+	for _, rs := range ruleSets {
+		rs.Rules = []model.RuleConfig{}
+		byId[rs.ID] = rs
+	}
+	for _, c := range configs {
+		if rs, ok := byId[c.RuleSet]; ok {
+			rs.Rules = append(rs.Rules, c.RuleConfig)
+		}
+	}
+
+	return ruleSets, nil
+}
+
+const getRuleSets = "SELECT id, effective_from FROM rule_set"
+
+const getRuleConfigs = `
+SELECT rule_set_id, position, name, limit_value, soft FROM rule_config`
+
+// A row from the rule_config table.
+type ruleConfigRow struct {
+	RuleSet  model.RuleSetID `db:"rule_set_id"`
+	Position int             `db:"position"`
+	model.RuleConfig
+}
+
+func (pg *PGStore) GetRuleSetById(ctx context.Context, id model.RuleSetID) (*model.RuleSet, error) {
+	ruleSet := &model.RuleSet{}
+	err := pg.conn().GetContext(ctx, ruleSet, getRuleSets+" WHERE id = $1", id)
+	if err == sql.ErrNoRows {
+		return nil, ErrRuleSetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadRuleConfigs(ctx, pg.conn(), ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+func (pg *PGStore) GetRuleSetAt(ctx context.Context, t time.Time) (*model.RuleSet, error) {
+	return ruleSetAt(ctx, pg.conn(), t)
+}
+
+// Get the rule set in effect at a given time, either directly or
+// within a transaction.
+func ruleSetAt(ctx context.Context, q sqlx.QueryerContext, t time.Time) (*model.RuleSet, error) {
+	ruleSet := &model.RuleSet{}
+	err := sqlx.GetContext(ctx, q, ruleSet, ruleSetAtTime, t)
+	if err == sql.ErrNoRows {
+		return nil, ErrRuleSetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadRuleConfigs(ctx, q, ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+const ruleSetAtTime = getRuleSets + `
+ WHERE effective_from <= $1
+ ORDER BY effective_from DESC, id DESC
+ LIMIT 1`
+
+func loadRuleConfigs(ctx context.Context, q sqlx.QueryerContext, ruleSet *model.RuleSet) error {
+	configs := []ruleConfigRow{}
+	err := sqlx.SelectContext(ctx, q, &configs, getRuleConfigs+" WHERE rule_set_id = $1 ORDER BY position", ruleSet.ID)
+	if err != nil {
+		return err
+	}
+
+	ruleSet.Rules = []model.RuleConfig{}
+	for _, c := range configs {
+		ruleSet.Rules = append(ruleSet.Rules, c.RuleConfig)
+	}
+	return nil
+}
+
+func (pg *PGStore) CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.GetContext(ctx, &ruleSet.ID, createRuleSet, ruleSet.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range ruleSet.Rules {
+		_, err = tx.ExecContext(ctx, createRuleConfig, ruleSet.ID, i, r.Name, r.Limit, r.Soft)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const createRuleSet = `
+INSERT INTO rule_set (effective_from) VALUES ($1) RETURNING id`
+
+const createRuleConfig = `
+INSERT INTO rule_config (rule_set_id, position, name, limit_value, soft)
+     VALUES ($1, $2, $3, $4, $5)`
+
+func (pg *PGStore) DeleteRuleSetById(ctx context.Context, id model.RuleSetID) error {
+	result, err := pg.conn().ExecContext(ctx, deleteRuleSet, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrRuleSetNotFound
+	}
+	return nil
+}
+
+const deleteRuleSet = "DELETE FROM rule_set WHERE id = $1"
+
+func (pg *PGStore) GetSwaps(ctx context.Context, workerId *model.WorkerID, status *model.SwapStatus) ([]*model.Swap, error) {
+	conds := []string{}
+	args := []interface{}{}
+	if workerId != nil {
+		args = append(args, *workerId)
+		conds = append(conds, fmt.Sprintf("(offerer_id = $%d OR taker_id = $%d)", len(args), len(args)))
+	}
+	if status != nil {
+		args = append(args, *status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	q := getSwaps
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	results := []*model.Swap{}
+	if err := pg.conn().SelectContext(ctx, &results, q+" ORDER BY id", args...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getSwaps = `
+SELECT id, offerer_id, shift_id, taker_id, counter_shift_id, status
+  FROM swap`
+
+func (pg *PGStore) GetSwapById(ctx context.Context, id model.SwapID) (*model.Swap, error) {
+	swap := &model.Swap{}
+	err := pg.conn().GetContext(ctx, swap, swapById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrSwapNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return swap, nil
+}
+
+const swapById = getSwaps + " WHERE id = $1"
+
+func (pg *PGStore) CreateSwap(ctx context.Context, swap *model.Swap) error {
+	rows, err := sqlx.NamedQueryContext(ctx, pg.conn(), createSwap, swap)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		// Nothing is inserted unless the offering worker is assigned to
+		// the shift.
+		return ErrShiftAssignmentNotFound
+	}
+
+	return rows.Scan(&swap.ID)
+}
+
+const createSwap = `
+INSERT INTO swap (offerer_id, shift_id, taker_id, counter_shift_id, status)
+SELECT :offerer_id, :shift_id, :taker_id, :counter_shift_id, :status
+ WHERE EXISTS (SELECT 1 FROM shift_assignment
+                WHERE worker_id = :offerer_id AND shift_id = :shift_id)
+RETURNING id`
+
+func (pg *PGStore) UpdateSwap(ctx context.Context, swap *model.Swap) error {
+	result, err := pg.conn().NamedExecContext(ctx, updateSwap, swap)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return ErrSwapNotFound
+	}
+	return nil
+}
+
+const updateSwap = `
+UPDATE swap
+   SET taker_id = :taker_id, counter_shift_id = :counter_shift_id, status = :status
+ WHERE id = :id`
+
+func (pg *PGStore) CheckSwap(ctx context.Context, swap *model.Swap) error {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Carry out the swap, then throw the changes away.
+	defer tx.Rollback()
+	return applySwap(ctx, tx, swap)
+}
+
+func (pg *PGStore) ExecuteSwap(ctx context.Context, id model.SwapID) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	swap := &model.Swap{}
+	err = tx.GetContext(ctx, swap, tx.forUpdate(swapById), id)
+	if err == sql.ErrNoRows {
+		err = ErrSwapNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if swap.Status != model.SwapProposed && swap.Status != model.SwapAccepted {
+		err = ErrSwapNotReady
+		return err
+	}
+
+	err = applySwap(ctx, tx, swap)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, completeSwap, id, model.SwapCompleted)
+	return err
+}
+
+const completeSwap = "UPDATE swap SET status = $2 WHERE id = $1"
+
+// Exchange the shift assignments for a swap within a transaction: the
+// offering worker is taken off their shift and the taker put on it,
+// with the reverse for the counter shift if there is one.
+func applySwap(ctx context.Context, tx *pgTx, swap *model.Swap) error {
+	if swap.Taker == nil {
+		return ErrSwapNotReady
+	}
+	taker := *swap.Taker
+
+	err := deleteShiftAssignmentTx(ctx, tx, swap.Offerer, swap.Shift)
+	if err != nil {
+		return err
+	}
+	if swap.CounterShift != nil {
+		err = deleteShiftAssignmentTx(ctx, tx, taker, *swap.CounterShift)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = addShiftAssignment(ctx, tx, taker, swap.Shift, false)
+	if err != nil {
+		return err
+	}
+	if swap.CounterShift != nil {
+		_, err = addShiftAssignment(ctx, tx, swap.Offerer, *swap.CounterShift, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pg *PGStore) GetBids(ctx context.Context, workerId *model.WorkerID, shiftId *model.ShiftID) ([]*model.Bid, error) {
+	conds := []string{}
+	args := []interface{}{}
+	if workerId != nil {
+		args = append(args, *workerId)
+		conds = append(conds, fmt.Sprintf("worker_id = $%d", len(args)))
+	}
+	if shiftId != nil {
+		args = append(args, *shiftId)
+		conds = append(conds, fmt.Sprintf("shift_id = $%d", len(args)))
+	}
+	q := getBids
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	results := []*model.Bid{}
+	if err := pg.conn().SelectContext(ctx, &results, q+" ORDER BY id", args...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+const getBids = `
+SELECT id, worker_id, shift_id, status, position, created_at
+  FROM bid`
+
+func (pg *PGStore) GetBidById(ctx context.Context, id model.BidID) (*model.Bid, error) {
+	bid := &model.Bid{}
+	err := pg.conn().GetContext(ctx, bid, bidById, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrBidNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+const bidById = getBids + " WHERE id = $1"
+
+func (pg *PGStore) CreateBid(ctx context.Context, bid *model.Bid) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	worker := &model.Worker{}
+	err = tx.GetContext(ctx, worker, workerById, bid.Worker)
+	if err == sql.ErrNoRows {
+		err = ErrWorkerNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Locking the shift keeps waitlist positions consistent between
+	// concurrent bids.
+	shift := &model.Shift{}
+	err = tx.GetContext(ctx, shift, tx.forUpdate(shiftById), bid.Shift)
+	if err == sql.ErrNoRows {
+		err = ErrShiftNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	err = loadWorkerDetails(ctx, tx, []*model.Worker{worker})
+	if err != nil {
+		return err
+	}
+	if !worker.InTeam(shift.Team) {
+		err = ErrNotTeamMember
+		return err
+	}
+
+	var active int
+	err = tx.GetContext(ctx, &active, activeBidCount, bid.Worker, bid.Shift)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		err = ErrBidExists
+		return err
+	}
+	var assigned int
+	err = tx.GetContext(ctx, &assigned, workerAssignmentCount, bid.Worker, bid.Shift)
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		err = ErrAlreadyAssigned
+		return err
+	}
+
+	var existing int
+	err = tx.GetContext(ctx, &existing, shiftAssignmentCount, bid.Shift)
+	if err != nil {
+		return err
+	}
+	bid.Status = model.BidPending
+	bid.Position = 0
+	if existing >= shift.Capacity {
+		bid.Status = model.BidWaitlisted
+		err = tx.GetContext(ctx, &bid.Position, waitlistLength, bid.Shift)
+		if err != nil {
+			return err
+		}
+		bid.Position++
+	} else if shift.BiddingClosed {
+		err = ErrBiddingClosed
+		return err
+	}
+
+	err = tx.QueryRowxContext(ctx, createBid, bid.Worker, bid.Shift, bid.Status, bid.Position).
+		Scan(&bid.ID, &bid.CreatedAt)
+	return err
+}
+
+const activeBidCount = `
+SELECT COUNT(*) FROM bid
+ WHERE worker_id = $1 AND shift_id = $2 AND status <> 'awarded'`
+
+const workerAssignmentCount = `
+SELECT COUNT(*) FROM shift_assignment WHERE worker_id = $1 AND shift_id = $2`
+
+const waitlistLength = `
+SELECT COUNT(*) FROM bid WHERE shift_id = $1 AND status = 'waitlisted'`
+
+const createBid = `
+INSERT INTO bid (worker_id, shift_id, status, position)
+     VALUES ($1, $2, $3, $4)
+RETURNING id, created_at`
+
+func (pg *PGStore) DeleteBidById(ctx context.Context, id model.BidID) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	bid := &model.Bid{}
+	err = tx.GetContext(ctx, bid, tx.forUpdate(bidById), id)
+	if err == sql.ErrNoRows {
+		err = ErrBidNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if bid.Status == model.BidWaitlisted {
+		err = leaveWaitlist(ctx, tx, bid)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, deleteBid, id)
+	return err
+}
+
+const deleteBid = "DELETE FROM bid WHERE id = $1"
+
+func (pg *PGStore) ResolveBids(ctx context.Context, shiftId model.ShiftID, order []model.BidID) (err error) {
+	tx, err := pg.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			tx.Rollback()
+		}
+	}()
+
+	shift := &model.Shift{}
+	err = tx.GetContext(ctx, shift, tx.forUpdate(shiftById), shiftId)
+	if err == sql.ErrNoRows {
+		err = ErrShiftNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.GetContext(ctx, &position, waitlistLength, shiftId)
+	if err != nil {
+		return err
+	}
+
+	// Bidders who can't be assigned, because the shift is full or
+	// because of the scheduling rules, go to the end of the waitlist.
+	for _, id := range order {
+		bid := &model.Bid{}
+		err = tx.GetContext(ctx, bid, tx.forUpdate(bidById), id)
+		if err == sql.ErrNoRows || err == nil && (bid.Shift != shiftId || bid.Status != model.BidPending) {
+			err = ErrBidNotFound
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		var added bool
+		added, err = tryShiftAssignment(ctx, tx, bid.Worker, shiftId)
+		if err != nil {
+			return err
+		}
+		if added {
+			_, err = tx.ExecContext(ctx, updateBidStatus, id, model.BidAwarded, 0)
+		} else {
+			position++
+			_, err = tx.ExecContext(ctx, updateBidStatus, id, model.BidWaitlisted, position)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, closeBidding, shiftId)
+	return err
+}
+
+const updateBidStatus = "UPDATE bid SET status = $2, position = $3 WHERE id = $1"
+
+const closeBidding = "UPDATE shift SET bidding_closed = TRUE WHERE id = $1"
+
+// Assign the first worker on a shift's waitlist who can take a place
+// on it, within a transaction.
+func promoteWaitlist(ctx context.Context, tx *pgTx, shiftId model.ShiftID) error {
+	waitlist := []*model.Bid{}
+	err := tx.SelectContext(ctx, &waitlist, getBids+" WHERE shift_id = $1 AND status = 'waitlisted' ORDER BY position", shiftId)
+	if err != nil {
+		return err
+	}
+
+	for _, bid := range waitlist {
+		added, err := tryShiftAssignment(ctx, tx, bid.Worker, shiftId)
+		if err != nil {
+			return err
+		}
+		if !added {
+			continue
+		}
+		err = leaveWaitlist(ctx, tx, bid)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, updateBidStatus, bid.ID, model.BidAwarded, 0)
+		return err
+	}
+	return nil
+}
+
+// Take a bid off its shift's waitlist within a transaction, moving the
+// bids behind it up a place.
+func leaveWaitlist(ctx context.Context, tx *pgTx, bid *model.Bid) error {
+	_, err := tx.ExecContext(ctx, moveUpWaitlist, bid.Shift, bid.Position)
+	return err
+}
+
+const moveUpWaitlist = `
+UPDATE bid SET position = position - 1
+ WHERE shift_id = $1 AND status = 'waitlisted' AND position > $2`
+
+// Try to add a shift assignment within a transaction, satisfying all
+// the scheduling rules. A savepoint keeps the transaction usable if
+// the assignment fails, in which case false is returned.
+func tryShiftAssignment(ctx context.Context, tx *pgTx, workerId model.WorkerID, shiftId model.ShiftID) (bool, error) {
+	_, err := tx.ExecContext(ctx, "SAVEPOINT try_assignment")
+	if err != nil {
+		return false, err
+	}
+	_, err = addShiftAssignment(ctx, tx, workerId, shiftId, false)
+	if err != nil {
+		_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT try_assignment")
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT try_assignment")
+	return err == nil, err
+}
@@ -0,0 +1,17 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS shift_preference (
+  id          SERIAL   PRIMARY KEY,
+  worker_id   INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER  REFERENCES shift(id) ON DELETE CASCADE,
+  weekday     INTEGER  CHECK (weekday BETWEEN 0 AND 6),
+  start_hour  INTEGER  CHECK (start_hour BETWEEN 0 AND 23),
+  weight      INTEGER  NOT NULL CHECK (weight BETWEEN -2 AND 2)
+);
+
+CREATE INDEX shift_preference_worker_idx ON shift_preference(worker_id);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS shift_preference;
@@ -0,0 +1,18 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS time_off (
+  id          SERIAL       PRIMARY KEY,
+  worker_id   INTEGER      NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  start_time  TIMESTAMPTZ  NOT NULL,
+  end_time    TIMESTAMPTZ  NOT NULL CHECK (end_time > start_time),
+  reason      TEXT         NOT NULL DEFAULT '',
+  status      TEXT         NOT NULL DEFAULT 'pending'
+                           CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX time_off_worker_idx ON time_off(worker_id);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS time_off;
@@ -0,0 +1,21 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS rule_set (
+  id              SERIAL       PRIMARY KEY,
+  effective_from  TIMESTAMPTZ  NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS rule_config (
+  rule_set_id  INTEGER           NOT NULL REFERENCES rule_set(id) ON DELETE CASCADE,
+  position     INTEGER           NOT NULL,
+  name         TEXT              NOT NULL,
+  limit_value  DOUBLE PRECISION  NOT NULL DEFAULT 0,
+  soft         BOOLEAN           NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (rule_set_id, position)
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS rule_config;
+DROP TABLE IF EXISTS rule_set;
@@ -0,0 +1,18 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS rule_override (
+  id          SERIAL       PRIMARY KEY,
+  worker_id   INTEGER      NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER      NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  admin_id    INTEGER      NOT NULL,
+  reason      TEXT         NOT NULL,
+  violations  TEXT         NOT NULL,
+  created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX rule_override_shift_idx ON rule_override(shift_id);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS rule_override;
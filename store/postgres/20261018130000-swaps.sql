@@ -0,0 +1,19 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS swap (
+  id                SERIAL   PRIMARY KEY,
+  offerer_id        INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id          INTEGER  NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  taker_id          INTEGER  REFERENCES worker(id) ON DELETE SET NULL,
+  counter_shift_id  INTEGER  REFERENCES shift(id) ON DELETE SET NULL,
+  status            TEXT     NOT NULL DEFAULT 'open'
+                             CHECK (status IN ('open', 'proposed', 'accepted',
+                                               'completed', 'rejected', 'cancelled'))
+);
+
+CREATE INDEX swap_status_idx ON swap(status);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS swap;
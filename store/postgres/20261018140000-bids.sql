@@ -0,0 +1,19 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS bid (
+  id          SERIAL       PRIMARY KEY,
+  worker_id   INTEGER      NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER      NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  status      TEXT         NOT NULL DEFAULT 'pending'
+                           CHECK (status IN ('pending', 'waitlisted', 'awarded')),
+  position    INTEGER      NOT NULL DEFAULT 0,
+  created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX bid_shift_idx ON bid(shift_id);
+CREATE UNIQUE INDEX bid_active_idx ON bid(worker_id, shift_id) WHERE status <> 'awarded';
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS bid;
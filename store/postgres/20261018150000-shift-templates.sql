@@ -0,0 +1,18 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS shift_template (
+  id          SERIAL   PRIMARY KEY,
+  name        TEXT     NOT NULL DEFAULT '',
+  weekdays    INTEGER  NOT NULL CHECK (weekdays > 0 AND weekdays < 128),
+  start_hour  INTEGER  NOT NULL CHECK (start_hour >= 0 AND start_hour < 24),
+  end_hour    INTEGER  NOT NULL CHECK (end_hour > 0 AND end_hour <= 24),
+  capacity    INTEGER  NOT NULL CHECK (capacity > 0)
+);
+
+CREATE INDEX shift_times_idx ON shift(start_time, end_time);
+
+
+-- +migrate Down
+
+DROP INDEX IF EXISTS shift_times_idx;
+DROP TABLE IF EXISTS shift_template;
@@ -0,0 +1,22 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS worker_skill (
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  skill      TEXT     NOT NULL,
+
+  PRIMARY KEY (worker_id, skill)
+);
+
+CREATE TABLE IF NOT EXISTS shift_requirement (
+  shift_id  INTEGER  NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  skill     TEXT     NOT NULL,
+  count     INTEGER  NOT NULL CHECK (count > 0),
+
+  PRIMARY KEY (shift_id, skill)
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS shift_requirement;
+DROP TABLE IF EXISTS worker_skill;
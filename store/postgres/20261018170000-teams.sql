@@ -0,0 +1,35 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS team (
+  id    SERIAL  PRIMARY KEY,
+  name  TEXT    NOT NULL
+);
+
+
+CREATE TABLE IF NOT EXISTS team_member (
+  team_id    INTEGER  NOT NULL REFERENCES team(id) ON DELETE CASCADE,
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  is_admin   BOOLEAN  NOT NULL DEFAULT FALSE,
+
+  PRIMARY KEY (team_id, worker_id)
+);
+
+CREATE INDEX team_member_worker_idx ON team_member(worker_id);
+
+
+ALTER TABLE shift
+  ADD COLUMN team_id INTEGER REFERENCES team(id) ON DELETE CASCADE;
+
+CREATE INDEX shift_team_idx ON shift(team_id);
+
+ALTER TABLE shift_template
+  ADD COLUMN team_id INTEGER REFERENCES team(id) ON DELETE CASCADE;
+
+
+-- +migrate Down
+
+ALTER TABLE shift_template DROP COLUMN IF EXISTS team_id;
+DROP INDEX IF EXISTS shift_team_idx;
+ALTER TABLE shift DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS team_member;
+DROP TABLE IF EXISTS team;
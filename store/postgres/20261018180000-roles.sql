@@ -0,0 +1,29 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS role (
+  id    SERIAL  PRIMARY KEY,
+  name  TEXT    NOT NULL
+);
+
+
+CREATE TABLE IF NOT EXISTS role_permission (
+  role_id     INTEGER  NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+  permission  TEXT     NOT NULL,
+
+  PRIMARY KEY (role_id, permission)
+);
+
+
+CREATE TABLE IF NOT EXISTS worker_role (
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  role_id    INTEGER  NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+
+  PRIMARY KEY (worker_id, role_id)
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS worker_role;
+DROP TABLE IF EXISTS role_permission;
+DROP TABLE IF EXISTS role;
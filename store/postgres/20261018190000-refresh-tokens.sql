@@ -0,0 +1,20 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS refresh_token (
+  id          TEXT         PRIMARY KEY,
+  family      TEXT         NOT NULL,
+  worker_id   INTEGER      NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  expires_at  TIMESTAMPTZ  NOT NULL,
+  used        BOOLEAN      NOT NULL DEFAULT FALSE,
+  revoked     BOOLEAN      NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX refresh_token_family_idx ON refresh_token(family);
+CREATE INDEX refresh_token_worker_idx ON refresh_token(worker_id);
+
+
+-- +migrate Down
+
+DROP INDEX IF EXISTS refresh_token_worker_idx;
+DROP INDEX IF EXISTS refresh_token_family_idx;
+DROP TABLE IF EXISTS refresh_token;
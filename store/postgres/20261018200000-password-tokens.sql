@@ -0,0 +1,14 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS password_token (
+  id          TEXT         PRIMARY KEY,
+  worker_id   INTEGER      NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  purpose     TEXT         NOT NULL,
+  expires_at  TIMESTAMPTZ  NOT NULL,
+  used        BOOLEAN      NOT NULL DEFAULT FALSE
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS password_token;
@@ -0,0 +1,22 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS two_factor (
+  worker_id  INTEGER  PRIMARY KEY REFERENCES worker(id) ON DELETE CASCADE,
+  secret     TEXT     NOT NULL,
+  enabled    BOOLEAN  NOT NULL DEFAULT FALSE,
+  last_step  BIGINT   NOT NULL DEFAULT 0
+);
+
+
+CREATE TABLE IF NOT EXISTS recovery_code (
+  worker_id  INTEGER  NOT NULL REFERENCES two_factor(worker_id) ON DELETE CASCADE,
+  code_hash  TEXT     NOT NULL,
+
+  PRIMARY KEY (worker_id, code_hash)
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS recovery_code;
+DROP TABLE IF EXISTS two_factor;
@@ -0,0 +1,25 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS login_attempt (
+  key           TEXT         PRIMARY KEY,
+  failures      INTEGER      NOT NULL DEFAULT 0,
+  last_failure  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+  locked_until  TIMESTAMPTZ
+);
+
+
+CREATE TABLE IF NOT EXISTS lockout (
+  id            SERIAL       PRIMARY KEY,
+  kind          TEXT         NOT NULL,
+  subject       TEXT         NOT NULL,
+  worker_id     INTEGER      REFERENCES worker(id) ON DELETE SET NULL,
+  failures      INTEGER      NOT NULL,
+  locked_at     TIMESTAMPTZ  NOT NULL,
+  locked_until  TIMESTAMPTZ  NOT NULL
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS lockout;
+DROP TABLE IF EXISTS login_attempt;
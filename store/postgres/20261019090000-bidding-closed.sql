@@ -0,0 +1,8 @@
+-- +migrate Up
+
+ALTER TABLE shift ADD COLUMN bidding_closed BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+-- +migrate Down
+
+ALTER TABLE shift DROP COLUMN bidding_closed;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+DROP INDEX worker_email_idx;
+CREATE UNIQUE INDEX worker_email_idx ON worker(email);
+
+
+-- +migrate Down
+
+DROP INDEX worker_email_idx;
+CREATE INDEX worker_email_idx ON worker(email);
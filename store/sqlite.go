@@ -0,0 +1,237 @@
+// `SQLiteStore` is an SQLite-based implementation of the `Store`
+// interface, for single-node deployments and for tests that need a
+// real database. It shares its queries with `PGStore`, and has its own
+// embedded migrations.
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	// SQLite DB driver.
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore is a wrapper for an SQLite database connection.
+type SQLiteStore struct {
+	*PGStore
+}
+
+// Name of the SQLite driver adapted for the Postgres store's queries.
+const sqliteDriver = "sqlite3-store"
+
+func init() {
+	sql.Register(sqliteDriver, &sqliteStoreDriver{})
+	sqlx.BindDriver(sqliteDriver, sqlx.QUESTION)
+}
+
+// How long to wait for the database connection, which is also how long
+// SQLite waits for locks held by other processes.
+var sqliteTimeout = 5 * time.Second
+
+// NewSQLiteStore creates a new SQLite database connection from a URL
+// of the form "sqlite:///path/to/file.db" ("sqlite://:memory:" gives a
+// database that's thrown away when the store is closed).
+func NewSQLiteStore(dbURL string) (Store, error) {
+	path := strings.TrimPrefix(dbURL, "sqlite://")
+	if path == "" {
+		return nil, fmt.Errorf("no database path in %q", dbURL)
+	}
+
+	// Foreign key constraints are off in SQLite unless they're asked
+	// for.
+	dsn := fmt.Sprintf("%s?_foreign_keys=on&_busy_timeout=%d", path, sqliteTimeout.Milliseconds())
+	db, err := sqlx.Open(sqliteDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	err = db.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("pinging database: %w", err)
+	}
+
+	// SQLite only allows one writer at a time, so we use a single
+	// connection: this also serialises transactions, so they don't need
+	// to lock rows. A transaction holds the connection until it ends,
+	// so calls to the store from inside its own WithTx function (rather
+	// than to the store that the function is given) can never run: see
+	// waitForSQLite.
+	db.SetMaxOpenConns(1)
+
+	return &SQLiteStore{&PGStore{db: db, sqlite: true}}, nil
+}
+
+func (s *SQLiteStore) Migrate() {
+	err := runMigrations(s.db, "sqlite", "sqlite3")
+	if err != nil {
+		log.Fatalln("Failed to migrate SQLite database: ", err)
+	}
+}
+
+// ErrSQLiteBusy is returned when the SQLite store's connection stays
+// in use for too long, most likely because the store is being called
+// from inside a WithTx function that's holding it.
+var ErrSQLiteBusy = errors.New("timed out waiting for SQLite connection")
+
+// Wait for the database connection to be free, failing after a while
+// rather than waiting for ever for a connection that a transaction in
+// the same goroutine is holding.
+func waitForSQLite(ctx context.Context, db *sqlx.DB) error {
+	wait, cancel := context.WithTimeout(ctx, sqliteTimeout)
+	defer cancel()
+	conn, err := db.Conn(wait)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return ErrSQLiteBusy
+		}
+		return err
+	}
+	return conn.Close()
+}
+
+// The Postgres store's queries use "$1"-style parameters, which are
+// changed into SQLite's numbered "?1" parameters as they're run
+// (parameters from sqlx.In and named parameters are bound for SQLite
+// already, as plain "?" parameters). Quoted strings and identifiers
+// and comments are left alone.
+func sqliteQuery(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		var start, end string
+		switch {
+		case query[i] == '\'' || query[i] == '"':
+			start, end = query[i:i+1], query[i:i+1]
+		case strings.HasPrefix(query[i:], "--"):
+			start, end = "--", "\n"
+		case strings.HasPrefix(query[i:], "/*"):
+			start, end = "/*", "*/"
+		case query[i] == '$' && i+1 < len(query) && '0' <= query[i+1] && query[i+1] <= '9':
+			b.WriteByte('?')
+			continue
+		default:
+			b.WriteByte(query[i])
+			continue
+		}
+
+		// Copy quoted text and comments through to their end (the end
+		// of the query if they aren't closed).
+		n := len(query)
+		if j := strings.Index(query[i+len(start):], end); j >= 0 {
+			n = i + len(start) + j + len(end)
+		}
+		b.WriteString(query[i:n])
+		i = n - 1
+	}
+	return b.String()
+}
+
+// Queries outside transactions wait for the connection and have their
+// parameters changed in the same way as queries in transactions.
+type sqliteDB struct {
+	*sqlx.DB
+}
+
+func (db *sqliteDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return nil, err
+	}
+	return db.DB.ExecContext(ctx, sqliteQuery(query), args...)
+}
+
+func (db *sqliteDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return err
+	}
+	return db.DB.GetContext(ctx, dest, sqliteQuery(query), args...)
+}
+
+func (db *sqliteDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return err
+	}
+	return db.DB.SelectContext(ctx, dest, sqliteQuery(query), args...)
+}
+
+func (db *sqliteDB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return nil, err
+	}
+	return db.DB.NamedExecContext(ctx, query, arg)
+}
+
+func (db *sqliteDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return nil, err
+	}
+	return db.DB.QueryContext(ctx, sqliteQuery(query), args...)
+}
+
+func (db *sqliteDB) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		return nil, err
+	}
+	return db.DB.QueryxContext(ctx, sqliteQuery(query), args...)
+}
+
+func (db *sqliteDB) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	if err := waitForSQLite(ctx, db.DB); err != nil {
+		// A cancelled context makes the query fail with the context's
+		// error, which is the nearest to ErrSQLiteBusy that a row can
+		// give.
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		return db.DB.QueryRowxContext(cancelled, query, args...)
+	}
+	return db.DB.QueryRowxContext(ctx, sqliteQuery(query), args...)
+}
+
+// The SQLite driver is wrapped so that times are stored in UTC, and
+// compare correctly as text.
+type sqliteStoreDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+func (d *sqliteStoreDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStoreConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+type sqliteStoreConn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (c *sqliteStoreConn) CheckNamedValue(nv *driver.NamedValue) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	if t, ok := v.(time.Time); ok {
+		v = t.UTC()
+	}
+	nv.Value = v
+	return nil
+}
+
+// Check for a violation of a unique index on the columns given, in
+// the "table.column, ..." form SQLite uses in its error messages.
+func sqliteUniqueViolation(err error, columns string) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique &&
+		strings.HasSuffix(sqliteErr.Error(), "constraint failed: "+columns)
+}
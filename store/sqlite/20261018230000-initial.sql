@@ -0,0 +1,274 @@
+-- SQLite schema, matching the Postgres migrations up to
+-- 20261018220000-login-attempts.sql. Times are stored as UTC text,
+-- which sorts in time order.
+
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS worker (
+  id        INTEGER  PRIMARY KEY AUTOINCREMENT,
+  email     TEXT     NOT NULL,
+  name      TEXT     NOT NULL,
+  is_admin  BOOLEAN  NOT NULL,
+  password  TEXT     NOT NULL
+);
+
+CREATE INDEX worker_email_idx ON worker(email);
+
+
+CREATE TABLE IF NOT EXISTS team (
+  id    INTEGER  PRIMARY KEY AUTOINCREMENT,
+  name  TEXT     NOT NULL
+);
+
+
+CREATE TABLE IF NOT EXISTS team_member (
+  team_id    INTEGER  NOT NULL REFERENCES team(id) ON DELETE CASCADE,
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  is_admin   BOOLEAN  NOT NULL DEFAULT FALSE,
+
+  PRIMARY KEY (team_id, worker_id)
+);
+
+CREATE INDEX team_member_worker_idx ON team_member(worker_id);
+
+
+CREATE TABLE IF NOT EXISTS shift (
+  id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+  start_time  TIMESTAMP  NOT NULL,
+  end_time    TIMESTAMP  NOT NULL,
+  capacity    INTEGER    NOT NULL,
+  team_id     INTEGER    REFERENCES team(id) ON DELETE CASCADE
+);
+
+CREATE INDEX shift_start_time_idx ON shift(start_time);
+CREATE INDEX shift_times_idx ON shift(start_time, end_time);
+CREATE INDEX shift_team_idx ON shift(team_id);
+
+
+CREATE TABLE IF NOT EXISTS shift_assignment (
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id   INTEGER  NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+
+  CONSTRAINT shift_assignment_unique UNIQUE (worker_id, shift_id)
+);
+
+CREATE INDEX shift_assignment_worker_idx ON shift_assignment(worker_id);
+CREATE INDEX shift_assignment_shift_idx ON shift_assignment(shift_id);
+
+
+CREATE TABLE IF NOT EXISTS shift_preference (
+  id          INTEGER  PRIMARY KEY AUTOINCREMENT,
+  worker_id   INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER  REFERENCES shift(id) ON DELETE CASCADE,
+  weekday     INTEGER  CHECK (weekday BETWEEN 0 AND 6),
+  start_hour  INTEGER  CHECK (start_hour BETWEEN 0 AND 23),
+  weight      INTEGER  NOT NULL CHECK (weight BETWEEN -2 AND 2)
+);
+
+CREATE INDEX shift_preference_worker_idx ON shift_preference(worker_id);
+
+
+CREATE TABLE IF NOT EXISTS time_off (
+  id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+  worker_id   INTEGER    NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  start_time  TIMESTAMP  NOT NULL,
+  end_time    TIMESTAMP  NOT NULL CHECK (end_time > start_time),
+  reason      TEXT       NOT NULL DEFAULT '',
+  status      TEXT       NOT NULL DEFAULT 'pending'
+                         CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX time_off_worker_idx ON time_off(worker_id);
+
+
+CREATE TABLE IF NOT EXISTS rule_set (
+  id              INTEGER    PRIMARY KEY AUTOINCREMENT,
+  effective_from  TIMESTAMP  NOT NULL
+);
+
+
+CREATE TABLE IF NOT EXISTS rule_config (
+  rule_set_id  INTEGER  NOT NULL REFERENCES rule_set(id) ON DELETE CASCADE,
+  position     INTEGER  NOT NULL,
+  name         TEXT     NOT NULL,
+  limit_value  REAL     NOT NULL DEFAULT 0,
+  soft         BOOLEAN  NOT NULL DEFAULT FALSE,
+
+  PRIMARY KEY (rule_set_id, position)
+);
+
+
+CREATE TABLE IF NOT EXISTS rule_override (
+  id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+  worker_id   INTEGER    NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER    NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  admin_id    INTEGER    NOT NULL,
+  reason      TEXT       NOT NULL,
+  violations  TEXT       NOT NULL,
+  created_at  TIMESTAMP  NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX rule_override_shift_idx ON rule_override(shift_id);
+
+
+CREATE TABLE IF NOT EXISTS swap (
+  id                INTEGER  PRIMARY KEY AUTOINCREMENT,
+  offerer_id        INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id          INTEGER  NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  taker_id          INTEGER  REFERENCES worker(id) ON DELETE SET NULL,
+  counter_shift_id  INTEGER  REFERENCES shift(id) ON DELETE SET NULL,
+  status            TEXT     NOT NULL DEFAULT 'open'
+                             CHECK (status IN ('open', 'proposed', 'accepted',
+                                               'completed', 'rejected', 'cancelled'))
+);
+
+CREATE INDEX swap_status_idx ON swap(status);
+
+
+CREATE TABLE IF NOT EXISTS bid (
+  id          INTEGER    PRIMARY KEY AUTOINCREMENT,
+  worker_id   INTEGER    NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  shift_id    INTEGER    NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  status      TEXT       NOT NULL DEFAULT 'pending'
+                         CHECK (status IN ('pending', 'waitlisted', 'awarded')),
+  position    INTEGER    NOT NULL DEFAULT 0,
+  created_at  TIMESTAMP  NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX bid_shift_idx ON bid(shift_id);
+CREATE UNIQUE INDEX bid_active_idx ON bid(worker_id, shift_id) WHERE status <> 'awarded';
+
+
+CREATE TABLE IF NOT EXISTS shift_template (
+  id          INTEGER  PRIMARY KEY AUTOINCREMENT,
+  name        TEXT     NOT NULL DEFAULT '',
+  weekdays    INTEGER  NOT NULL CHECK (weekdays > 0 AND weekdays < 128),
+  start_hour  INTEGER  NOT NULL CHECK (start_hour >= 0 AND start_hour < 24),
+  end_hour    INTEGER  NOT NULL CHECK (end_hour > 0 AND end_hour <= 24),
+  capacity    INTEGER  NOT NULL CHECK (capacity > 0),
+  team_id     INTEGER  REFERENCES team(id) ON DELETE CASCADE
+);
+
+
+CREATE TABLE IF NOT EXISTS worker_skill (
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  skill      TEXT     NOT NULL,
+
+  PRIMARY KEY (worker_id, skill)
+);
+
+
+CREATE TABLE IF NOT EXISTS shift_requirement (
+  shift_id  INTEGER  NOT NULL REFERENCES shift(id) ON DELETE CASCADE,
+  skill     TEXT     NOT NULL,
+  count     INTEGER  NOT NULL CHECK (count > 0),
+
+  PRIMARY KEY (shift_id, skill)
+);
+
+
+CREATE TABLE IF NOT EXISTS role (
+  id    INTEGER  PRIMARY KEY AUTOINCREMENT,
+  name  TEXT     NOT NULL
+);
+
+
+CREATE TABLE IF NOT EXISTS role_permission (
+  role_id     INTEGER  NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+  permission  TEXT     NOT NULL,
+
+  PRIMARY KEY (role_id, permission)
+);
+
+
+CREATE TABLE IF NOT EXISTS worker_role (
+  worker_id  INTEGER  NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  role_id    INTEGER  NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+
+  PRIMARY KEY (worker_id, role_id)
+);
+
+
+CREATE TABLE IF NOT EXISTS refresh_token (
+  id          TEXT       PRIMARY KEY,
+  family      TEXT       NOT NULL,
+  worker_id   INTEGER    NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  expires_at  TIMESTAMP  NOT NULL,
+  used        BOOLEAN    NOT NULL DEFAULT FALSE,
+  revoked     BOOLEAN    NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX refresh_token_family_idx ON refresh_token(family);
+CREATE INDEX refresh_token_worker_idx ON refresh_token(worker_id);
+
+
+CREATE TABLE IF NOT EXISTS password_token (
+  id          TEXT       PRIMARY KEY,
+  worker_id   INTEGER    NOT NULL REFERENCES worker(id) ON DELETE CASCADE,
+  purpose     TEXT       NOT NULL,
+  expires_at  TIMESTAMP  NOT NULL,
+  used        BOOLEAN    NOT NULL DEFAULT FALSE
+);
+
+
+CREATE TABLE IF NOT EXISTS two_factor (
+  worker_id  INTEGER  PRIMARY KEY REFERENCES worker(id) ON DELETE CASCADE,
+  secret     TEXT     NOT NULL,
+  enabled    BOOLEAN  NOT NULL DEFAULT FALSE,
+  last_step  BIGINT   NOT NULL DEFAULT 0
+);
+
+
+CREATE TABLE IF NOT EXISTS recovery_code (
+  worker_id  INTEGER  NOT NULL REFERENCES two_factor(worker_id) ON DELETE CASCADE,
+  code_hash  TEXT     NOT NULL,
+
+  PRIMARY KEY (worker_id, code_hash)
+);
+
+
+CREATE TABLE IF NOT EXISTS login_attempt (
+  key           TEXT       PRIMARY KEY,
+  failures      INTEGER    NOT NULL DEFAULT 0,
+  last_failure  TIMESTAMP  NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+  locked_until  TIMESTAMP
+);
+
+
+CREATE TABLE IF NOT EXISTS lockout (
+  id            INTEGER    PRIMARY KEY AUTOINCREMENT,
+  kind          TEXT       NOT NULL,
+  subject       TEXT       NOT NULL,
+  worker_id     INTEGER    REFERENCES worker(id) ON DELETE SET NULL,
+  failures      INTEGER    NOT NULL,
+  locked_at     TIMESTAMP  NOT NULL,
+  locked_until  TIMESTAMP  NOT NULL
+);
+
+
+-- +migrate Down
+
+DROP TABLE IF EXISTS lockout;
+DROP TABLE IF EXISTS login_attempt;
+DROP TABLE IF EXISTS recovery_code;
+DROP TABLE IF EXISTS two_factor;
+DROP TABLE IF EXISTS password_token;
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS worker_role;
+DROP TABLE IF EXISTS role_permission;
+DROP TABLE IF EXISTS role;
+DROP TABLE IF EXISTS shift_requirement;
+DROP TABLE IF EXISTS worker_skill;
+DROP TABLE IF EXISTS shift_template;
+DROP TABLE IF EXISTS bid;
+DROP TABLE IF EXISTS swap;
+DROP TABLE IF EXISTS rule_override;
+DROP TABLE IF EXISTS rule_config;
+DROP TABLE IF EXISTS rule_set;
+DROP TABLE IF EXISTS time_off;
+DROP TABLE IF EXISTS shift_preference;
+DROP TABLE IF EXISTS shift_assignment;
+DROP TABLE IF EXISTS shift;
+DROP TABLE IF EXISTS team_member;
+DROP TABLE IF EXISTS team;
+DROP TABLE IF EXISTS worker;
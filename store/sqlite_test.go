@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteQuery(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM worker WHERE id = $1":                  "SELECT * FROM worker WHERE id = ?1",
+		"UPDATE shift SET capacity = $2 WHERE id = $10":       "UPDATE shift SET capacity = ?2 WHERE id = ?10",
+		"SELECT '$1 and ''$2''' FROM x WHERE id = $3":         "SELECT '$1 and ''$2''' FROM x WHERE id = ?3",
+		`SELECT "col$1" FROM x WHERE id = $1`:                 `SELECT "col$1" FROM x WHERE id = ?1`,
+		"SELECT 1 -- costs $1\nWHERE id = $1":                 "SELECT 1 -- costs $1\nWHERE id = ?1",
+		"SELECT /* $1 */ id FROM x WHERE id = $1 /* $2":       "SELECT /* $1 */ id FROM x WHERE id = ?1 /* $2",
+		"SELECT 'unclosed $1":                                 "SELECT 'unclosed $1",
+		"SELECT id FROM x WHERE id IN (?, ?) AND price = '$'": "SELECT id FROM x WHERE id IN (?, ?) AND price = '$'",
+	}
+	for query, expected := range tests {
+		assert.Equal(t, expected, sqliteQuery(query), query)
+	}
+}
+
+// Calling the store, rather than the store a WithTx function is given,
+// from inside the function can't get the database connection, and
+// fails rather than waiting for ever.
+func TestSQLiteStoreOuterCallInTx(t *testing.T) {
+	defer func(timeout time.Duration) { sqliteTimeout = timeout }(sqliteTimeout)
+	sqliteTimeout = 100 * time.Millisecond
+
+	db, err := NewSQLiteStore("sqlite://" + t.TempDir() + "/test.db")
+	require.NoError(t, err)
+	defer db.Close()
+	db.Migrate()
+
+	ctx := context.Background()
+	err = db.WithTx(ctx, func(tx Store) error {
+		_, err := tx.GetWorkers(ctx, nil)
+		require.NoError(t, err)
+		_, err = db.GetWorkers(ctx, nil)
+		return err
+	})
+	assert.ErrorIs(t, err, ErrSQLiteBusy)
+
+	_, err = db.GetWorkers(ctx, nil)
+	assert.NoError(t, err)
+}
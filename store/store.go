@@ -1,9 +1,13 @@
 package store
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+	"skybluetrades.net/work-planning-demo/domain"
 	"skybluetrades.net/work-planning-demo/model"
 )
 
@@ -20,48 +24,281 @@ const (
 // Error return values from the store layer.
 var ErrWorkerNotFound = errors.New("unknown worker ID")
 var ErrUnknownWorkerEmail = errors.New("unknown worker email")
+var ErrWorkerEmailExists = errors.New("worker email already in use")
 var ErrShiftNotFound = errors.New("unknown shift ID")
 var ErrShiftAssignmentNotFound = errors.New("unknown shift assignment")
 var ErrShiftAtCapacity = errors.New("shift is already at capacity")
+var ErrRequirementsUnmet = errors.New("shift's free places are needed for workers with other skills")
 var ErrRetrievingWorkerShifts = errors.New("failed to retrieve shifts for worker")
 var ErrTwoShiftsSameDay = errors.New("new shift is on the same day as an existing shift")
+var ErrWorkerOnLeave = errors.New("worker has approved time off during shift")
+var ErrShiftPreferenceNotFound = errors.New("unknown shift preference ID")
+var ErrTimeOffNotFound = errors.New("unknown time off request ID")
+var ErrRuleSetNotFound = errors.New("unknown rule set")
+var ErrSwapNotFound = errors.New("unknown shift swap ID")
+var ErrSwapNotReady = errors.New("shift swap has no proposal to carry out")
+var ErrBidNotFound = errors.New("unknown shift bid ID")
+var ErrBidExists = errors.New("worker has already bid for shift")
+var ErrBiddingClosed = errors.New("bidding for shift is closed")
+var ErrAlreadyAssigned = errors.New("worker is already assigned to shift")
+var ErrShiftTemplateNotFound = errors.New("unknown shift template ID")
+var ErrTeamNotFound = errors.New("unknown team ID")
+var ErrNotTeamMember = errors.New("worker is not a member of shift's team")
+var ErrRoleNotFound = errors.New("unknown role ID")
+var ErrRefreshTokenNotFound = errors.New("unknown refresh token")
+var ErrPasswordTokenInvalid = errors.New("invalid or expired password token")
+var ErrTwoFactorNotFound = errors.New("two-factor authentication not set up")
+var ErrTwoFactorCodeInvalid = errors.New("invalid two-factor authentication code")
 
-// Store layer interface: we have in-memory and Postgres
-// implementations of this (plus a mock for testing).
+// RuleViolationError is returned when a new shift assignment would
+// break one of the hard scheduling rules, identifying the rule.
+type RuleViolationError struct {
+	Violation *domain.Violation
+}
+
+func (e *RuleViolationError) Error() string {
+	return e.Violation.Error()
+}
+
+// Explain a list of soft rule violations for an override record.
+func describeViolations(violations []*domain.Violation) string {
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is makes violations of the same-day rule match ErrTwoShiftsSameDay,
+// which is what the stores returned before there were other rules.
+func (e *RuleViolationError) Is(target error) bool {
+	return target == ErrTwoShiftsSameDay && e.Violation.Rule == domain.RuleSameDay
+}
+
+// Hash a password for storage, leaving empty passwords empty so that
+// they never match.
+func hashPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	hash, _ := bcrypt.GenerateFromPassword([]byte(password), 0)
+	return string(hash)
+}
+
+// LoginAttemptStore is the part of the store layer that keeps track
+// of failed logins, so that repeated failures for an account or a
+// client IP address can be slowed down and locked out. Keys identify
+// accounts or IP addresses. RecordLoginFailure adds a failure for a
+// key, starting the count again if the last failure was longer ago
+// than the window, and returns the updated record: it also clears out
+// the records for keys that aren't locked and whose last failure was
+// longer ago than the window. Clearing a key removes its failures and
+// any lock on it.
+type LoginAttemptStore interface {
+	GetLoginAttempts(ctx context.Context, key string) (*model.LoginAttempts, error)
+	RecordLoginFailure(ctx context.Context, key string, window time.Duration) (*model.LoginAttempts, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ClearLoginAttempts(ctx context.Context, key string) error
+
+	// Lockouts are audit records, listed newest first.
+	CreateLockout(ctx context.Context, lockout *model.Lockout) error
+	GetLockouts(ctx context.Context) ([]*model.Lockout, error)
+}
+
+// Store layer interface: we have in-memory, Postgres and SQLite
+// implementations of this (plus a mock for testing). Everything but
+// Migrate and Close takes a context: the database stores cancel their
+// queries when it's done, and the in-memory store ignores it.
 type Store interface {
+	LoginAttemptStore
+
 	Migrate()
 
-	Authenticate(email string, password string) (*model.Worker, error)
+	// Close releases the store's resources, and for a persistent
+	// in-memory store, writes a final snapshot of its state.
+	Close() error
+
+	// WithTx runs a function as a single unit of work, giving it a view
+	// of the store to make all of its calls through: nothing else sees
+	// its changes until it returns, and if it returns an error, they're
+	// all undone. The function must only use the store it's given, and
+	// may be run more than once if the database has to retry the
+	// transaction, so it shouldn't do anything else with side effects.
+	// Calls to WithTx within the function make nested units of work.
+	WithTx(ctx context.Context, fn func(tx Store) error) error
 
-	GetWorkers() ([]*model.Worker, error)
-	GetWorkerById(id model.WorkerID) (*model.Worker, error)
-	CreateWorker(worker *model.Worker) error
-	UpdateWorker(worker *model.Worker) error
-	DeleteWorkerById(id model.WorkerID) error
+	Authenticate(ctx context.Context, email string, password string) (*model.Worker, error)
 
-	GetShifts(date *time.Time, span TimeSpan, workerId *model.WorkerID) ([]*model.Shift, error)
-	GetShiftById(id model.ShiftID) (*model.Shift, error)
-	CreateShift(shift *model.Shift) error
-	UpdateShift(shift *model.Shift) error
-	DeleteShiftById(id model.ShiftID) error
+	// Refresh tokens are recorded when they're issued, and expired ones
+	// are cleared out as new ones are created. UseRefreshToken marks a
+	// token as used, returning it as it was before, so that callers can
+	// tell when a token is used twice. Revoking a token family also
+	// stops access tokens from the same login from being accepted.
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	UseRefreshToken(ctx context.Context, id string) (*model.RefreshToken, error)
+	RevokeRefreshTokenFamily(ctx context.Context, family string) error
+	RevokeWorkerRefreshTokens(ctx context.Context, workerId model.WorkerID) error
+	RefreshTokenFamilyRevoked(ctx context.Context, family string) (bool, error)
 
-	CreateShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error
-	DeleteShiftAssignment(workerId model.WorkerID, shiftId model.ShiftID) error
+	// Password tokens are single-use tokens for invitations and password
+	// resets. UsePasswordToken marks a token as used, failing if it's
+	// already been used, has expired, or is for something else.
+	// Workers who've been invited but haven't set a password yet have
+	// an empty password, which never matches when logging in.
+	CreatePasswordToken(ctx context.Context, token *model.PasswordToken) error
+	UsePasswordToken(ctx context.Context, id string, purpose model.PasswordTokenPurpose) (*model.PasswordToken, error)
+	SetWorkerPassword(ctx context.Context, id model.WorkerID, password string) error
+
+	// Two-factor authentication settings are kept apart from the rest
+	// of each worker's details. Saving them replaces the recovery codes
+	// too. RecordTOTPStep fails if a TOTP code from the same time step
+	// or a later one has already been used, and UseRecoveryCode uses up
+	// a recovery code, given its hash: both fail with
+	// ErrTwoFactorCodeInvalid.
+	GetTwoFactor(ctx context.Context, workerId model.WorkerID) (*model.TwoFactor, error)
+	SetTwoFactor(ctx context.Context, tf *model.TwoFactor) error
+	DeleteTwoFactor(ctx context.Context, workerId model.WorkerID) error
+	RecordTOTPStep(ctx context.Context, workerId model.WorkerID, step int64) error
+	UseRecoveryCode(ctx context.Context, workerId model.WorkerID, hash string) error
+
+	// Workers and shifts can be filtered by team: a nil team ID gets
+	// everything. Workers are listed in ID order and shifts by start
+	// time. Worker emails must be unique: creating or updating a worker
+	// to use an email that's already taken fails with
+	// ErrWorkerEmailExists. Updating a worker with an empty password
+	// keeps their current password. Deleting a worker or shift deletes
+	// everything that refers to it.
+	GetWorkers(ctx context.Context, teamId *model.TeamID) ([]*model.Worker, error)
+	GetWorkerById(ctx context.Context, id model.WorkerID) (*model.Worker, error)
+	GetWorkerByEmail(ctx context.Context, email string) (*model.Worker, error)
+	CreateWorker(ctx context.Context, worker *model.Worker) error
+	UpdateWorker(ctx context.Context, worker *model.Worker) error
+	DeleteWorkerById(ctx context.Context, id model.WorkerID) error
+
+	GetShifts(ctx context.Context, date *time.Time, span TimeSpan,
+		workerId *model.WorkerID, teamId *model.TeamID) ([]*model.Shift, error)
+	GetShiftsInRange(ctx context.Context, start time.Time, end time.Time) ([]*model.Shift, error)
+	GetShiftById(ctx context.Context, id model.ShiftID) (*model.Shift, error)
+	CreateShift(ctx context.Context, shift *model.Shift) error
+	// CreateShifts creates a list of shifts in a single update, skipping
+	// any with the same start and end time and team as an existing
+	// shift. It sets the ID of every shift in the list (to the existing
+	// shift's ID for skipped ones) and returns the shifts that were
+	// created.
+	CreateShifts(ctx context.Context, shifts []*model.Shift) ([]*model.Shift, error)
+	UpdateShift(ctx context.Context, shift *model.Shift) error
+	DeleteShiftById(ctx context.Context, id model.ShiftID) error
+
+	// Deleting a team deletes its shifts and shift templates too.
+	// Team memberships are held in the Teams field of each worker, and
+	// are saved along with the rest of the worker, but can also be
+	// changed one at a time.
+	GetTeams(ctx context.Context) ([]*model.Team, error)
+	GetTeamById(ctx context.Context, id model.TeamID) (*model.Team, error)
+	CreateTeam(ctx context.Context, team *model.Team) error
+	DeleteTeamById(ctx context.Context, id model.TeamID) error
+	SetTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID, isAdmin bool) error
+	DeleteTeamMember(ctx context.Context, teamId model.TeamID, workerId model.WorkerID) error
+
+	// Roles given to workers are held in the Roles field of each worker.
+	// Deleting a role takes it away from its workers.
+	GetRoles(ctx context.Context) ([]*model.Role, error)
+	GetRoleById(ctx context.Context, id model.RoleID) (*model.Role, error)
+	CreateRole(ctx context.Context, role *model.Role) error
+	UpdateRole(ctx context.Context, role *model.Role) error
+	DeleteRoleById(ctx context.Context, id model.RoleID) error
+
+	GetShiftTemplates(ctx context.Context) ([]*model.ShiftTemplate, error)
+	GetShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) (*model.ShiftTemplate, error)
+	CreateShiftTemplate(ctx context.Context, template *model.ShiftTemplate) error
+	DeleteShiftTemplateById(ctx context.Context, id model.ShiftTemplateID) error
+
+	// Single shift assignments must satisfy all the scheduling rules,
+	// unless an admin override is given: then soft rules may be broken,
+	// and the override is recorded if any are. Bulk assignments (from
+	// the solvers) only need to satisfy the hard rules.
+	GetShiftAssignmentsInRange(ctx context.Context, start time.Time, end time.Time) ([]model.ShiftAssignment, error)
+	CreateShiftAssignment(ctx context.Context, workerId model.WorkerID, shiftId model.ShiftID, override *model.RuleOverride) error
+	CreateShiftAssignments(ctx context.Context, assignments []model.ShiftAssignment) error
+	DeleteShiftAssignment(ctx context.Context, workerId model.WorkerID, shiftId model.ShiftID) error
+	MoveShiftAssignment(ctx context.Context, workerId model.WorkerID, from model.ShiftID, to model.ShiftID, override *model.RuleOverride) error
+	ReplaceShiftAssignments(ctx context.Context, remove []model.ShiftAssignment, add []model.ShiftAssignment) error
+	GetRuleOverrides(ctx context.Context, shiftId *model.ShiftID) ([]*model.RuleOverride, error)
+
+	GetShiftPreferences(ctx context.Context, workerId *model.WorkerID) ([]*model.ShiftPreference, error)
+	GetShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) (*model.ShiftPreference, error)
+	CreateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error
+	UpdateShiftPreference(ctx context.Context, pref *model.ShiftPreference) error
+	DeleteShiftPreferenceById(ctx context.Context, id model.ShiftPreferenceID) error
+
+	GetTimeOff(ctx context.Context, workerId *model.WorkerID, status *model.TimeOffStatus) ([]*model.TimeOff, error)
+	GetTimeOffById(ctx context.Context, id model.TimeOffID) (*model.TimeOff, error)
+	CreateTimeOff(ctx context.Context, timeOff *model.TimeOff) error
+	UpdateTimeOffStatus(ctx context.Context, id model.TimeOffID, status model.TimeOffStatus) error
+	DeleteTimeOffById(ctx context.Context, id model.TimeOffID) error
+
+	// Swaps are checked and carried out using the same rules as single
+	// shift assignments. CheckSwap doesn't change anything; ExecuteSwap
+	// exchanges the shift assignments and marks the swap completed in
+	// a single update.
+	GetSwaps(ctx context.Context, workerId *model.WorkerID, status *model.SwapStatus) ([]*model.Swap, error)
+	GetSwapById(ctx context.Context, id model.SwapID) (*model.Swap, error)
+	CreateSwap(ctx context.Context, swap *model.Swap) error
+	UpdateSwap(ctx context.Context, swap *model.Swap) error
+	CheckSwap(ctx context.Context, swap *model.Swap) error
+	ExecuteSwap(ctx context.Context, id model.SwapID) error
+
+	// Bids for shifts with free places are pending until resolved, and
+	// bids for full shifts join the shift's waitlist. ResolveBids tries
+	// to assign pending bidders in the order given, using the same
+	// rules as single shift assignments, and waitlists the ones it
+	// can't. It also closes bidding for the shift: after that, bids are
+	// refused with ErrBiddingClosed unless the shift is full and they
+	// can join the waitlist. New shifts are open for bidding, and
+	// UpdateShift leaves bidding as it was. When DeleteShiftAssignment,
+	// MoveShiftAssignment, ReplaceShiftAssignments or DeleteWorkerById
+	// frees a place, the first waitlisted worker who can take it is
+	// assigned.
+	GetBids(ctx context.Context, workerId *model.WorkerID, shiftId *model.ShiftID) ([]*model.Bid, error)
+	GetBidById(ctx context.Context, id model.BidID) (*model.Bid, error)
+	CreateBid(ctx context.Context, bid *model.Bid) error
+	DeleteBidById(ctx context.Context, id model.BidID) error
+	ResolveBids(ctx context.Context, shiftId model.ShiftID, order []model.BidID) error
+
+	GetRuleSets(ctx context.Context) ([]*model.RuleSet, error)
+	GetRuleSetById(ctx context.Context, id model.RuleSetID) (*model.RuleSet, error)
+	GetRuleSetAt(ctx context.Context, t time.Time) (*model.RuleSet, error)
+	CreateRuleSet(ctx context.Context, ruleSet *model.RuleSet) error
+	DeleteRuleSetById(ctx context.Context, id model.RuleSetID) error
+}
+
+// RulesAt gets the scheduling rules in effect at a given time, using
+// the default rules if no rule set has been configured.
+func RulesAt(ctx context.Context, db Store, t time.Time) (domain.RuleSet, error) {
+	ruleSet, err := db.GetRuleSetAt(ctx, t)
+	if err == ErrRuleSetNotFound {
+		return domain.DefaultRules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewRuleSet(ruleSet.Rules)
 }
 
-// Turn a date and a time span (either "week" or "day") into a (start
-// time, end time) pair. For example, if the span is "week" and you
-// pass in a date in the middle of the week, the start time and end
-// time will be the start and end of the week.
-func getSpanRange(date *time.Time, span TimeSpan) (time.Time, time.Time) {
+// SpanRange turns a date and a time span (either "week" or "day")
+// into a (start time, end time) pair. For example, if the span is
+// "week" and you pass in a date in the middle of the week, the start
+// time and end time will be the start and end of the week.
+func SpanRange(date *time.Time, span TimeSpan) (time.Time, time.Time) {
 	var intStart, intEnd time.Time
 	y, m, d := date.Date()
-	intStart = time.Date(y, m, d, 0, 0, 0, 0, nil)
+	intStart = time.Date(y, m, d, 0, 0, 0, 0, date.Location())
 	if span == DaySpan {
 		intEnd = intStart.AddDate(0, 0, 1)
 	} else {
+		// Weeks start on Monday.
 		wd := intStart.Weekday()
-		delta := (int(wd) - 1) % 7
+		delta := (int(wd) + 6) % 7
 		intStart = intStart.AddDate(0, 0, -delta)
 		intEnd = intStart.AddDate(0, 0, 7)
 	}
@@ -0,0 +1,108 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+	"skybluetrades.net/work-planning-demo/store/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		db, err := store.NewMemoryStore()
+		require.NoError(t, err)
+		return db
+	})
+}
+
+// The SQLite store runs the same queries as the Postgres store, so this
+// covers most of the Postgres store's code without needing a Postgres
+// server.
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Store {
+		db, err := store.NewSQLiteStore("sqlite://" + t.TempDir() + "/test.db")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		db.Migrate()
+		return db
+	})
+}
+
+func TestSQLiteStoreCancelled(t *testing.T) {
+	db, err := store.NewSQLiteStore("sqlite://" + t.TempDir() + "/test.db")
+	require.NoError(t, err)
+	defer db.Close()
+	db.Migrate()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = db.GetWorkers(ctx, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	err = db.CreateWorker(ctx, &model.Worker{Email: "one@example.com"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// The Postgres store is tested against the database given by
+// TEST_DATABASE_URL if it's set, or otherwise against an embedded
+// Postgres server (which has to download Postgres the first time, and
+// won't run as root). Everything in the database's public schema is
+// thrown away before each test. The test is only skipped if the
+// embedded server can't be started outside CI: in CI, where the CI
+// environment variable is set, that fails the test, so that the
+// Postgres store is always checked there.
+func TestPostgresStore(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		dbURL = startEmbeddedPostgres(t)
+	}
+
+	storetest.Run(t, func(t *testing.T) store.Store {
+		conn, err := sqlx.Open("postgres", dbURL)
+		require.NoError(t, err)
+		_, err = conn.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+		conn.Close()
+		require.NoError(t, err)
+
+		db, err := store.NewPostgresStore(dbURL)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		db.Migrate()
+		return db
+	})
+}
+
+// Start an embedded Postgres server for the duration of a test,
+// returning its URL.
+func startEmbeddedPostgres(t *testing.T) string {
+	// Find a free port for the server.
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	var logs bytes.Buffer
+	cfg := embeddedpostgres.DefaultConfig().
+		Port(uint32(port)).
+		RuntimePath(t.TempDir()).
+		Logger(&logs)
+	pg := embeddedpostgres.NewDatabase(cfg)
+	err = pg.Start()
+	if err != nil {
+		t.Log(logs.String())
+		if os.Getenv("CI") != "" {
+			t.Fatalf("can't start embedded Postgres (set TEST_DATABASE_URL to use another server): %v", err)
+		}
+		t.Skipf("can't start embedded Postgres: %v", err)
+	}
+	t.Cleanup(func() { pg.Stop() })
+
+	return cfg.GetConnectionURL() + "?sslmode=disable"
+}
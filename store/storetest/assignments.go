@@ -0,0 +1,518 @@
+package storetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/domain"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+func testShiftAssignments(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	s1 := createShift(t, db, 0, 8, 2)
+	s2 := createShift(t, db, 1, 8, 1)
+
+	assign(t, db, w1, s1)
+	assign(t, db, w2, s1)
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s1.ID},
+	}, weekAssignments(t, db))
+	assignments, err := db.GetShiftAssignmentsInRange(ctx, at(1, 0), at(2, 0))
+	require.NoError(t, err)
+	assert.Equal([]model.ShiftAssignment{}, assignments)
+
+	require.NoError(t, db.MoveShiftAssignment(ctx, w1.ID, s1.ID, s2.ID, nil))
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w2.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID},
+	}, weekAssignments(t, db))
+	err = db.MoveShiftAssignment(ctx, w1.ID, s1.ID, s2.ID, nil)
+	assert.ErrorIs(err, store.ErrShiftAssignmentNotFound)
+
+	// A move that fails leaves the worker where they were.
+	err = db.MoveShiftAssignment(ctx, w2.ID, s1.ID, s2.ID, nil)
+	assert.ErrorIs(err, store.ErrShiftAtCapacity)
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w2.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID},
+	}, weekAssignments(t, db))
+
+	require.NoError(t, db.DeleteShiftAssignment(ctx, w2.ID, s1.ID))
+	assert.Equal([]model.ShiftAssignment{{Worker: w1.ID, Shift: s2.ID}}, weekAssignments(t, db))
+	assert.ErrorIs(db.DeleteShiftAssignment(ctx, w2.ID, s1.ID), store.ErrShiftAssignmentNotFound)
+}
+
+// Single shift assignments are checked against all the scheduling
+// rules, and each rule has its own error.
+func testAssignmentRules(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	medic := createWorker(t, db, "medic@example.com", "first-aid")
+
+	full := createShift(t, db, 0, 8, 1)
+	assign(t, db, w2, full)
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w1.ID, full.ID, nil), store.ErrShiftAtCapacity)
+
+	sameDay := createShift(t, db, 0, 18, 2)
+	err := db.CreateShiftAssignment(ctx, w2.ID, sameDay.ID, nil)
+	assert.ErrorIs(err, store.ErrTwoShiftsSameDay)
+	var violation *store.RuleViolationError
+	require.True(t, errors.As(err, &violation))
+	assert.Equal(domain.RuleSameDay, violation.Violation.Rule)
+	assert.True(violation.Violation.Hard)
+
+	skilled := &model.Shift{StartTime: at(1, 8), EndTime: at(1, 16), Capacity: 2,
+		Requirements: []model.Requirement{{Skill: "first-aid", Count: 1}}}
+	require.NoError(t, db.CreateShift(ctx, skilled))
+	assign(t, db, w1, skilled)
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w2.ID, skilled.ID, nil), store.ErrRequirementsUnmet)
+	assign(t, db, medic, skilled)
+
+	teamShift := &model.Shift{StartTime: at(2, 8), EndTime: at(2, 16), Capacity: 2, Team: &team}
+	require.NoError(t, db.CreateShift(ctx, teamShift))
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w1.ID, teamShift.ID, nil), store.ErrNotTeamMember)
+	require.NoError(t, db.SetTeamMember(ctx, team, w1.ID, false))
+	assign(t, db, w1, teamShift)
+
+	leave := createShift(t, db, 3, 8, 2)
+	pending := &model.TimeOff{Worker: w1.ID, StartTime: at(3, 0), EndTime: at(4, 0),
+		Status: model.TimeOffPending}
+	require.NoError(t, db.CreateTimeOff(ctx, pending))
+	approved := &model.TimeOff{Worker: w2.ID, StartTime: at(3, 0), EndTime: at(4, 0),
+		Status: model.TimeOffApproved}
+	require.NoError(t, db.CreateTimeOff(ctx, approved))
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w2.ID, leave.ID, nil), store.ErrWorkerOnLeave)
+	assign(t, db, w1, leave)
+
+	// Without a rule set, only the same-day rule applies, so there's
+	// no minimum rest between shifts on different days.
+	late := createShift(t, db, 4, 16, 1)
+	early := createShift(t, db, 5, 0, 1)
+	assign(t, db, w2, late)
+	assign(t, db, w2, early)
+
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, medic.ID+100, leave.ID, nil), store.ErrWorkerNotFound)
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w2.ID, leave.ID+100, nil), store.ErrShiftNotFound)
+
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w2.ID, Shift: full.ID},
+		{Worker: w1.ID, Shift: skilled.ID},
+		{Worker: medic.ID, Shift: skilled.ID},
+		{Worker: w1.ID, Shift: teamShift.ID},
+		{Worker: w1.ID, Shift: leave.ID},
+		{Worker: w2.ID, Shift: late.ID},
+		{Worker: w2.ID, Shift: early.ID},
+	}, weekAssignments(t, db))
+}
+
+// Workers can't be assigned to a shift twice, even when no rule stops
+// it.
+func testDuplicateAssignments(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	require.NoError(t, db.CreateRuleSet(ctx, &model.RuleSet{
+		EffectiveFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rules:         []model.RuleConfig{},
+	}))
+	w1 := createWorker(t, db, "one@example.com")
+	s1 := createShift(t, db, 0, 8, 2)
+	s2 := createShift(t, db, 0, 18, 2)
+
+	assign(t, db, w1, s1)
+	assert.ErrorIs(db.CreateShiftAssignment(ctx, w1.ID, s1.ID, nil), store.ErrAlreadyAssigned)
+	err := db.CreateShiftAssignments(ctx, []model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s2.ID}, {Worker: w1.ID, Shift: s2.ID},
+	})
+	assert.ErrorIs(err, store.ErrAlreadyAssigned)
+
+	// Without the same-day rule, the same worker can do two shifts on
+	// one day, but can't be moved onto a shift they're already on.
+	assign(t, db, w1, s2)
+	assert.ErrorIs(db.MoveShiftAssignment(ctx, w1.ID, s1.ID, s2.ID, nil), store.ErrAlreadyAssigned)
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID},
+	}, weekAssignments(t, db))
+}
+
+// Bulk assignments happen all together or not at all.
+func testBulkAssignments(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	s1 := createShift(t, db, 0, 8, 1)
+	s2 := createShift(t, db, 1, 8, 1)
+
+	err := db.CreateShiftAssignments(ctx, []model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s1.ID},
+	})
+	assert.ErrorIs(err, store.ErrShiftAtCapacity)
+	assert.Empty(weekAssignments(t, db))
+
+	require.NoError(t, db.CreateShiftAssignments(ctx, []model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s2.ID},
+	}))
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s2.ID},
+	}, weekAssignments(t, db))
+
+	require.NoError(t, db.ReplaceShiftAssignments(ctx,
+		[]model.ShiftAssignment{{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s2.ID}},
+		[]model.ShiftAssignment{{Worker: w2.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID}},
+	))
+	want := []model.ShiftAssignment{{Worker: w2.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID}}
+	assert.ElementsMatch(want, weekAssignments(t, db))
+
+	err = db.ReplaceShiftAssignments(ctx,
+		[]model.ShiftAssignment{{Worker: w2.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s2.ID}},
+		[]model.ShiftAssignment{})
+	assert.ErrorIs(err, store.ErrShiftAssignmentNotFound)
+	assert.ElementsMatch(want, weekAssignments(t, db))
+
+	err = db.ReplaceShiftAssignments(ctx,
+		[]model.ShiftAssignment{{Worker: w2.ID, Shift: s1.ID}},
+		[]model.ShiftAssignment{{Worker: w2.ID + 100, Shift: s1.ID}})
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+	assert.ElementsMatch(want, weekAssignments(t, db))
+}
+
+func testRuleSets(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	utc := func(rs *model.RuleSet) *model.RuleSet {
+		c := *rs
+		c.EffectiveFrom = rs.EffectiveFrom.UTC()
+		return &c
+	}
+
+	_, err := db.GetRuleSetAt(ctx, date(2023, 6, 1))
+	assert.ErrorIs(err, store.ErrRuleSetNotFound)
+	ruleSets, err := db.GetRuleSets(ctx)
+	require.NoError(t, err)
+	assert.Equal([]*model.RuleSet{}, ruleSets)
+
+	rs1 := &model.RuleSet{EffectiveFrom: date(2023, 1, 1), Rules: []model.RuleConfig{
+		{Name: domain.RuleSameDay},
+		{Name: domain.RuleMinRest, Limit: 10, Soft: true},
+	}}
+	require.NoError(t, db.CreateRuleSet(ctx, rs1))
+	rs2 := &model.RuleSet{EffectiveFrom: date(2022, 1, 1), Rules: []model.RuleConfig{
+		{Name: domain.RuleMaxWeeklyHours, Limit: 40},
+	}}
+	require.NoError(t, db.CreateRuleSet(ctx, rs2))
+	rs3 := &model.RuleSet{EffectiveFrom: date(2023, 1, 1)}
+	require.NoError(t, db.CreateRuleSet(ctx, rs3))
+	rs3.Rules = []model.RuleConfig{}
+
+	// Rule sets are ordered by the date they take effect, then by ID,
+	// and the last one to take effect is the one in force.
+	ruleSets, err = db.GetRuleSets(ctx)
+	require.NoError(t, err)
+	got := []*model.RuleSet{}
+	for _, rs := range ruleSets {
+		got = append(got, utc(rs))
+	}
+	assert.Equal([]*model.RuleSet{rs2, rs1, rs3}, got)
+
+	check := func(want *model.RuleSet, at time.Time) {
+		t.Helper()
+		rs, err := db.GetRuleSetAt(ctx, at)
+		require.NoError(t, err)
+		assert.Equal(want, utc(rs))
+	}
+	check(rs2, date(2022, 6, 1))
+	check(rs3, date(2023, 1, 1))
+	check(rs3, date(2023, 6, 1))
+	_, err = db.GetRuleSetAt(ctx, date(2021, 6, 1))
+	assert.ErrorIs(err, store.ErrRuleSetNotFound)
+
+	rs, err := db.GetRuleSetById(ctx, rs1.ID)
+	require.NoError(t, err)
+	assert.Equal(rs1, utc(rs))
+	rs.Rules[0].Limit = 100
+	rs, err = db.GetRuleSetById(ctx, rs1.ID)
+	require.NoError(t, err)
+	assert.Equal(rs1, utc(rs))
+
+	require.NoError(t, db.DeleteRuleSetById(ctx, rs3.ID))
+	check(rs1, date(2023, 6, 1))
+	_, err = db.GetRuleSetById(ctx, rs3.ID)
+	assert.ErrorIs(err, store.ErrRuleSetNotFound)
+	assert.ErrorIs(db.DeleteRuleSetById(ctx, rs3.ID), store.ErrRuleSetNotFound)
+}
+
+// Soft rules can be broken by admins, and the stores keep a record of
+// when they are.
+func testRuleOverrides(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	ruleSet := &model.RuleSet{
+		EffectiveFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rules: []model.RuleConfig{
+			{Name: domain.RuleSameDay},
+			{Name: domain.RuleMaxWeeklyHours, Limit: 10, Soft: true},
+		},
+	}
+	require.NoError(t, db.CreateRuleSet(ctx, ruleSet))
+	admin := createWorker(t, db, "admin@example.com")
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	s1 := createShift(t, db, 0, 8, 2)
+	s2 := createShift(t, db, 2, 8, 2)
+	assign(t, db, w1, s1)
+
+	err := db.CreateShiftAssignment(ctx, w1.ID, s2.ID, nil)
+	var violation *store.RuleViolationError
+	require.True(t, errors.As(err, &violation))
+	assert.Equal(domain.RuleMaxWeeklyHours, violation.Violation.Rule)
+	assert.False(violation.Violation.Hard)
+	assert.NotErrorIs(err, store.ErrTwoShiftsSameDay)
+
+	override := &model.RuleOverride{Admin: admin.ID, Reason: "Short-staffed"}
+	require.NoError(t, db.CreateShiftAssignment(ctx, w1.ID, s2.ID, override))
+	assert.NotZero(override.ID)
+	assert.Equal(w1.ID, override.Worker)
+	assert.Equal(s2.ID, override.Shift)
+	assert.NotEmpty(override.Violations)
+	assert.False(override.CreatedAt.IsZero())
+
+	// Nothing is recorded when there's nothing to override, and hard
+	// rules can't be overridden.
+	require.NoError(t, db.CreateShiftAssignment(ctx, w2.ID, s1.ID,
+		&model.RuleOverride{Admin: admin.ID, Reason: "Not needed"}))
+	sameDay := createShift(t, db, 0, 18, 1)
+	err = db.CreateShiftAssignment(ctx, w2.ID, sameDay.ID, &model.RuleOverride{Admin: admin.ID})
+	assert.ErrorIs(err, store.ErrTwoShiftsSameDay)
+
+	overrides, err := db.GetRuleOverrides(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	got := overrides[0]
+	assert.Equal(override.ID, got.ID)
+	assert.Equal(w1.ID, got.Worker)
+	assert.Equal(s2.ID, got.Shift)
+	assert.Equal(admin.ID, got.Admin)
+	assert.Equal("Short-staffed", got.Reason)
+	assert.Equal(override.Violations, got.Violations)
+	overrides, err = db.GetRuleOverrides(ctx, &s2.ID)
+	require.NoError(t, err)
+	assert.Len(overrides, 1)
+	overrides, err = db.GetRuleOverrides(ctx, &s1.ID)
+	require.NoError(t, err)
+	assert.Equal([]*model.RuleOverride{}, overrides)
+
+	// Bulk assignments may break soft rules without an override.
+	require.NoError(t, db.CreateShiftAssignments(ctx, []model.ShiftAssignment{{Worker: w2.ID, Shift: s2.ID}}))
+}
+
+func testSwaps(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	w3 := createWorker(t, db, "three@example.com")
+	s1 := createShift(t, db, 0, 8, 1)
+	s2 := createShift(t, db, 1, 8, 1)
+	s3 := createShift(t, db, 0, 18, 1)
+	assign(t, db, w1, s1)
+	assign(t, db, w2, s2)
+	assign(t, db, w3, s3)
+
+	err := db.CreateSwap(ctx, &model.Swap{Offerer: w2.ID, Shift: s1.ID, Status: model.SwapOpen})
+	assert.ErrorIs(err, store.ErrShiftAssignmentNotFound)
+
+	swap := &model.Swap{Offerer: w1.ID, Shift: s1.ID, Status: model.SwapOpen}
+	require.NoError(t, db.CreateSwap(ctx, swap))
+	assert.NotZero(swap.ID)
+	got, err := db.GetSwapById(ctx, swap.ID)
+	require.NoError(t, err)
+	assert.Equal(swap, got)
+	assert.ErrorIs(db.CheckSwap(ctx, swap), store.ErrSwapNotReady)
+	assert.ErrorIs(db.ExecuteSwap(ctx, swap.ID), store.ErrSwapNotReady)
+
+	// Checking a swap doesn't change anything.
+	swap.Taker = &w2.ID
+	swap.CounterShift = &s2.ID
+	swap.Status = model.SwapProposed
+	require.NoError(t, db.UpdateSwap(ctx, swap))
+	require.NoError(t, db.CheckSwap(ctx, swap))
+	before := []model.ShiftAssignment{
+		{Worker: w1.ID, Shift: s1.ID}, {Worker: w2.ID, Shift: s2.ID}, {Worker: w3.ID, Shift: s3.ID},
+	}
+	assert.ElementsMatch(before, weekAssignments(t, db))
+
+	require.NoError(t, db.ExecuteSwap(ctx, swap.ID))
+	after := []model.ShiftAssignment{
+		{Worker: w2.ID, Shift: s1.ID}, {Worker: w1.ID, Shift: s2.ID}, {Worker: w3.ID, Shift: s3.ID},
+	}
+	assert.ElementsMatch(after, weekAssignments(t, db))
+	got, err = db.GetSwapById(ctx, swap.ID)
+	require.NoError(t, err)
+	assert.Equal(model.SwapCompleted, got.Status)
+	assert.ErrorIs(db.ExecuteSwap(ctx, swap.ID), store.ErrSwapNotReady)
+
+	// Swaps that break the rules fail, and change nothing.
+	bad := &model.Swap{Offerer: w2.ID, Shift: s1.ID, Taker: &w3.ID, Status: model.SwapProposed}
+	require.NoError(t, db.CreateSwap(ctx, bad))
+	assert.ErrorIs(db.CheckSwap(ctx, bad), store.ErrTwoShiftsSameDay)
+	assert.ErrorIs(db.ExecuteSwap(ctx, bad.ID), store.ErrTwoShiftsSameDay)
+	assert.ElementsMatch(after, weekAssignments(t, db))
+	got, err = db.GetSwapById(ctx, bad.ID)
+	require.NoError(t, err)
+	assert.Equal(model.SwapProposed, got.Status)
+
+	check := func(want []*model.Swap, workerId *model.WorkerID, status *model.SwapStatus) {
+		t.Helper()
+		swaps, err := db.GetSwaps(ctx, workerId, status)
+		require.NoError(t, err)
+		ids := []model.SwapID{}
+		for _, s := range swaps {
+			ids = append(ids, s.ID)
+		}
+		wantIds := []model.SwapID{}
+		for _, s := range want {
+			wantIds = append(wantIds, s.ID)
+		}
+		assert.Equal(wantIds, ids)
+	}
+	check([]*model.Swap{swap, bad}, nil, nil)
+	check([]*model.Swap{swap}, &w1.ID, nil)
+	check([]*model.Swap{bad}, &w3.ID, nil)
+	check([]*model.Swap{bad}, nil, ptr(model.SwapProposed))
+	check([]*model.Swap{}, &w1.ID, ptr(model.SwapOpen))
+
+	_, err = db.GetSwapById(ctx, bad.ID+100)
+	assert.ErrorIs(err, store.ErrSwapNotFound)
+	assert.ErrorIs(db.UpdateSwap(ctx, &model.Swap{ID: bad.ID + 100, Status: model.SwapCancelled}),
+		store.ErrSwapNotFound)
+	assert.ErrorIs(db.ExecuteSwap(ctx, bad.ID+100), store.ErrSwapNotFound)
+}
+
+func testBids(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	w3 := createWorker(t, db, "three@example.com")
+	w4 := createWorker(t, db, "four@example.com")
+	shift := createShift(t, db, 0, 8, 1)
+	other := createShift(t, db, 0, 18, 1)
+
+	bid := func(worker *model.Worker, shift *model.Shift) *model.Bid {
+		t.Helper()
+		b := &model.Bid{Worker: worker.ID, Shift: shift.ID}
+		require.NoError(t, db.CreateBid(ctx, b))
+		return b
+	}
+	check := func(b *model.Bid, status model.BidStatus, position int) {
+		t.Helper()
+		got, err := db.GetBidById(ctx, b.ID)
+		require.NoError(t, err)
+		assert.Equal(status, got.Status)
+		assert.Equal(position, got.Position)
+	}
+
+	// Bids for shifts with free places are pending.
+	b1 := bid(w1, shift)
+	assert.NotZero(b1.ID)
+	assert.Equal(model.BidPending, b1.Status)
+	assert.False(b1.CreatedAt.IsZero())
+	b2 := bid(w2, shift)
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w1.ID, Shift: shift.ID}), store.ErrBidExists)
+
+	require.NoError(t, db.ResolveBids(ctx, shift.ID, []model.BidID{b2.ID, b1.ID}))
+	check(b2, model.BidAwarded, 0)
+	check(b1, model.BidWaitlisted, 1)
+	assert.Equal([]model.ShiftAssignment{{Worker: w2.ID, Shift: shift.ID}}, weekAssignments(t, db))
+	assert.ErrorIs(db.ResolveBids(ctx, shift.ID, []model.BidID{b1.ID}), store.ErrBidNotFound)
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w2.ID, Shift: shift.ID}), store.ErrAlreadyAssigned)
+
+	// Bids for full shifts join the waitlist, and leaving the waitlist
+	// moves everyone behind up.
+	assign(t, db, w3, other)
+	b3 := bid(w3, shift)
+	assert.Equal(model.BidWaitlisted, b3.Status)
+	assert.Equal(2, b3.Position)
+	b4 := bid(w4, shift)
+	check(b4, model.BidWaitlisted, 3)
+	require.NoError(t, db.DeleteBidById(ctx, b1.ID))
+	check(b3, model.BidWaitlisted, 1)
+	check(b4, model.BidWaitlisted, 2)
+	_, err := db.GetBidById(ctx, b1.ID)
+	assert.ErrorIs(err, store.ErrBidNotFound)
+	assert.ErrorIs(db.DeleteBidById(ctx, b1.ID), store.ErrBidNotFound)
+
+	// A free place goes to the first worker on the waitlist who can
+	// take it: here, not the one with another shift on the same day.
+	require.NoError(t, db.DeleteShiftAssignment(ctx, w2.ID, shift.ID))
+	check(b3, model.BidWaitlisted, 1)
+	check(b4, model.BidAwarded, 0)
+	assert.ElementsMatch([]model.ShiftAssignment{
+		{Worker: w3.ID, Shift: other.ID}, {Worker: w4.ID, Shift: shift.ID},
+	}, weekAssignments(t, db))
+
+	bids, err := db.GetBids(ctx, nil, &shift.ID)
+	require.NoError(t, err)
+	ids := []model.BidID{}
+	for _, b := range bids {
+		ids = append(ids, b.ID)
+	}
+	assert.Equal([]model.BidID{b2.ID, b3.ID, b4.ID}, ids)
+	bids, err = db.GetBids(ctx, &w3.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, bids, 1)
+	assert.Equal(b3.ID, bids[0].ID)
+	bids, err = db.GetBids(ctx, &w1.ID, &shift.ID)
+	require.NoError(t, err)
+	assert.Equal([]*model.Bid{}, bids)
+
+	// Resolving bids happens all together or not at all.
+	later := createShift(t, db, 1, 8, 1)
+	b5 := bid(w1, later)
+	err = db.ResolveBids(ctx, later.ID, []model.BidID{b5.ID, b5.ID + 100})
+	assert.ErrorIs(err, store.ErrBidNotFound)
+	check(b5, model.BidPending, 0)
+	assert.ErrorIs(db.ResolveBids(ctx, later.ID+100, []model.BidID{}), store.ErrShiftNotFound)
+
+	// Resolving bids closes bidding, even when there aren't any, and
+	// updating the shift doesn't open it again. Closed shifts with free
+	// places take no more bids, but full ones still have waitlists (as
+	// for b3 and b4 above).
+	open := createShift(t, db, 3, 8, 2)
+	assert.False(open.BiddingClosed)
+	require.NoError(t, db.ResolveBids(ctx, open.ID, []model.BidID{}))
+	open.Capacity = 1
+	require.NoError(t, db.UpdateShift(ctx, open))
+	closed, err := db.GetShiftById(ctx, open.ID)
+	require.NoError(t, err)
+	assert.True(closed.BiddingClosed)
+	assert.Equal(1, closed.Capacity)
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w1.ID, Shift: open.ID}), store.ErrBiddingClosed)
+
+	// Places freed by replacing assignments or by deleting workers go
+	// to the waitlist too.
+	full := createShift(t, db, 4, 8, 1)
+	assign(t, db, w1, full)
+	b6 := bid(w2, full)
+	b7 := bid(w4, full)
+	require.NoError(t, db.ReplaceShiftAssignments(ctx,
+		[]model.ShiftAssignment{{Worker: w1.ID, Shift: full.ID}}, []model.ShiftAssignment{}))
+	check(b6, model.BidAwarded, 0)
+	check(b7, model.BidWaitlisted, 1)
+	require.NoError(t, db.DeleteWorkerById(ctx, w2.ID))
+	check(b7, model.BidAwarded, 0)
+	assignments, err := db.GetShiftAssignmentsInRange(ctx, at(4, 0), at(5, 0))
+	require.NoError(t, err)
+	assert.Equal([]model.ShiftAssignment{{Worker: w4.ID, Shift: full.ID}}, assignments)
+
+	teamShift := &model.Shift{StartTime: at(2, 8), EndTime: at(2, 16), Capacity: 1, Team: &team}
+	require.NoError(t, db.CreateShift(ctx, teamShift))
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w1.ID, Shift: teamShift.ID}), store.ErrNotTeamMember)
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w4.ID + 100, Shift: later.ID}), store.ErrWorkerNotFound)
+	assert.ErrorIs(db.CreateBid(ctx, &model.Bid{Worker: w1.ID, Shift: teamShift.ID + 100}), store.ErrShiftNotFound)
+}
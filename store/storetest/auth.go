@@ -0,0 +1,230 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Times stored by the database backends lose their sub-second parts
+// and time zones, so the tests use whole seconds in UTC.
+func now() time.Time {
+	return time.Now().Truncate(time.Second).UTC()
+}
+
+func testRefreshTokens(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	worker := createWorker(t, db, "one@example.com")
+	other := createWorker(t, db, "two@example.com")
+	expires := now().Add(time.Hour)
+
+	// Creating a token clears out expired ones.
+	expired := &model.RefreshToken{ID: "expired", Family: "f0", Worker: worker.ID,
+		ExpiresAt: now().Add(-time.Hour)}
+	require.NoError(t, db.CreateRefreshToken(ctx, expired))
+	t1 := &model.RefreshToken{ID: "t1", Family: "f1", Worker: worker.ID, ExpiresAt: expires}
+	require.NoError(t, db.CreateRefreshToken(ctx, t1))
+	require.NoError(t, db.CreateRefreshToken(ctx,
+		&model.RefreshToken{ID: "t2", Family: "f1", Worker: worker.ID, ExpiresAt: expires}))
+	require.NoError(t, db.CreateRefreshToken(ctx,
+		&model.RefreshToken{ID: "t3", Family: "f2", Worker: worker.ID, ExpiresAt: expires}))
+	require.NoError(t, db.CreateRefreshToken(ctx,
+		&model.RefreshToken{ID: "t4", Family: "f3", Worker: other.ID, ExpiresAt: expires}))
+	_, err := db.UseRefreshToken(ctx, "expired")
+	assert.ErrorIs(err, store.ErrRefreshTokenNotFound)
+
+	// Using a token gives it back as it was before.
+	got, err := db.UseRefreshToken(ctx, "t1")
+	require.NoError(t, err)
+	got.ExpiresAt = got.ExpiresAt.UTC()
+	assert.Equal(t1, got)
+	got, err = db.UseRefreshToken(ctx, "t1")
+	require.NoError(t, err)
+	assert.True(got.Used)
+	_, err = db.UseRefreshToken(ctx, "unknown")
+	assert.ErrorIs(err, store.ErrRefreshTokenNotFound)
+
+	revoked := func(family string) bool {
+		t.Helper()
+		r, err := db.RefreshTokenFamilyRevoked(ctx, family)
+		require.NoError(t, err)
+		return r
+	}
+	assert.False(revoked("f1"))
+	require.NoError(t, db.RevokeRefreshTokenFamily(ctx, "f1"))
+	assert.True(revoked("f1"))
+	assert.False(revoked("f2"))
+	got, err = db.UseRefreshToken(ctx, "t2")
+	require.NoError(t, err)
+	assert.True(got.Revoked)
+
+	require.NoError(t, db.RevokeWorkerRefreshTokens(ctx, worker.ID))
+	assert.True(revoked("f2"))
+	assert.False(revoked("f3"))
+	assert.ErrorIs(db.RevokeWorkerRefreshTokens(ctx, other.ID+100), store.ErrWorkerNotFound)
+}
+
+func testPasswordTokens(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	worker := createWorker(t, db, "one@example.com")
+	expires := now().Add(time.Hour)
+
+	invite := &model.PasswordToken{ID: "invite", Worker: worker.ID,
+		Purpose: model.PasswordInvitation, ExpiresAt: expires}
+	require.NoError(t, db.CreatePasswordToken(ctx, invite))
+	expired := &model.PasswordToken{ID: "expired", Worker: worker.ID,
+		Purpose: model.PasswordReset, ExpiresAt: now().Add(-time.Hour)}
+	require.NoError(t, db.CreatePasswordToken(ctx, expired))
+
+	// Tokens are only good once, for what they were made for.
+	_, err := db.UsePasswordToken(ctx, "invite", model.PasswordReset)
+	assert.ErrorIs(err, store.ErrPasswordTokenInvalid)
+	got, err := db.UsePasswordToken(ctx, "invite", model.PasswordInvitation)
+	require.NoError(t, err)
+	assert.Equal(worker.ID, got.Worker)
+	assert.Equal(model.PasswordInvitation, got.Purpose)
+	assert.True(got.Used)
+	_, err = db.UsePasswordToken(ctx, "invite", model.PasswordInvitation)
+	assert.ErrorIs(err, store.ErrPasswordTokenInvalid)
+	_, err = db.UsePasswordToken(ctx, "expired", model.PasswordReset)
+	assert.ErrorIs(err, store.ErrPasswordTokenInvalid)
+	_, err = db.UsePasswordToken(ctx, "unknown", model.PasswordReset)
+	assert.ErrorIs(err, store.ErrPasswordTokenInvalid)
+
+	require.NoError(t, db.SetWorkerPassword(ctx, worker.ID, "new-password"))
+	_, err = db.Authenticate(ctx, "one@example.com", "password")
+	assert.Error(err)
+	_, err = db.Authenticate(ctx, "one@example.com", "new-password")
+	assert.NoError(err)
+	assert.ErrorIs(db.SetWorkerPassword(ctx, worker.ID+100, "password"), store.ErrWorkerNotFound)
+
+	// Empty passwords never match.
+	require.NoError(t, db.SetWorkerPassword(ctx, worker.ID, ""))
+	_, err = db.Authenticate(ctx, "one@example.com", "")
+	assert.Error(err)
+}
+
+func testTwoFactor(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	worker := createWorker(t, db, "one@example.com")
+
+	_, err := db.GetTwoFactor(ctx, worker.ID)
+	assert.ErrorIs(err, store.ErrTwoFactorNotFound)
+	err = db.SetTwoFactor(ctx, &model.TwoFactor{Worker: worker.ID + 100, Secret: "secret"})
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+
+	tf := &model.TwoFactor{Worker: worker.ID, Secret: "secret"}
+	require.NoError(t, db.SetTwoFactor(ctx, tf))
+	got, err := db.GetTwoFactor(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal(&model.TwoFactor{Worker: worker.ID, Secret: "secret", RecoveryCodes: []string{}}, got)
+
+	// Saving the settings again replaces the recovery codes.
+	tf.Enabled = true
+	tf.RecoveryCodes = []string{"a", "b", "c"}
+	require.NoError(t, db.SetTwoFactor(ctx, tf))
+	got, err = db.GetTwoFactor(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal(tf, got)
+	got.RecoveryCodes[0] = "changed"
+	got, err = db.GetTwoFactor(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal(tf, got)
+
+	// Each TOTP time step and recovery code can only be used once.
+	require.NoError(t, db.RecordTOTPStep(ctx, worker.ID, 10))
+	assert.ErrorIs(db.RecordTOTPStep(ctx, worker.ID, 10), store.ErrTwoFactorCodeInvalid)
+	assert.ErrorIs(db.RecordTOTPStep(ctx, worker.ID, 9), store.ErrTwoFactorCodeInvalid)
+	require.NoError(t, db.RecordTOTPStep(ctx, worker.ID, 11))
+	require.NoError(t, db.UseRecoveryCode(ctx, worker.ID, "b"))
+	assert.ErrorIs(db.UseRecoveryCode(ctx, worker.ID, "b"), store.ErrTwoFactorCodeInvalid)
+	got, err = db.GetTwoFactor(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal(int64(11), got.LastStep)
+	assert.Equal([]string{"a", "c"}, got.RecoveryCodes)
+
+	require.NoError(t, db.DeleteTwoFactor(ctx, worker.ID))
+	_, err = db.GetTwoFactor(ctx, worker.ID)
+	assert.ErrorIs(err, store.ErrTwoFactorNotFound)
+	assert.ErrorIs(db.DeleteTwoFactor(ctx, worker.ID), store.ErrTwoFactorNotFound)
+	assert.ErrorIs(db.RecordTOTPStep(ctx, worker.ID, 12), store.ErrTwoFactorCodeInvalid)
+	assert.ErrorIs(db.UseRecoveryCode(ctx, worker.ID, "a"), store.ErrTwoFactorCodeInvalid)
+}
+
+func testLoginAttempts(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	worker := createWorker(t, db, "one@example.com")
+
+	attempts, err := db.GetLoginAttempts(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(&model.LoginAttempts{Key: "key"}, attempts)
+
+	attempts, err = db.RecordLoginFailure(ctx, "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(1, attempts.Failures)
+	attempts, err = db.RecordLoginFailure(ctx, "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(2, attempts.Failures)
+	assert.False(attempts.LastFailure.IsZero())
+
+	// The count starts again after a gap longer than the window.
+	time.Sleep(10 * time.Millisecond)
+	attempts, err = db.RecordLoginFailure(ctx, "key", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(1, attempts.Failures)
+
+	until := now().Add(time.Hour)
+	require.NoError(t, db.LockLogin(ctx, "key", until))
+	require.NoError(t, db.LockLogin(ctx, "other", until))
+	attempts, err = db.GetLoginAttempts(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(1, attempts.Failures)
+	assert.True(attempts.Locked(time.Now()))
+	assert.True(until.Equal(*attempts.LockedUntil))
+	attempts, err = db.GetLoginAttempts(ctx, "other")
+	require.NoError(t, err)
+	assert.True(attempts.Locked(time.Now()))
+
+	require.NoError(t, db.ClearLoginAttempts(ctx, "key"))
+	attempts, err = db.GetLoginAttempts(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(&model.LoginAttempts{Key: "key"}, attempts)
+
+	// Records of old failures are cleared out, unless they're locked.
+	_, err = db.RecordLoginFailure(ctx, "stale", time.Hour)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = db.RecordLoginFailure(ctx, "key", time.Millisecond)
+	require.NoError(t, err)
+	attempts, err = db.GetLoginAttempts(ctx, "stale")
+	require.NoError(t, err)
+	assert.Equal(&model.LoginAttempts{Key: "stale"}, attempts)
+	attempts, err = db.GetLoginAttempts(ctx, "other")
+	require.NoError(t, err)
+	assert.True(attempts.Locked(time.Now()))
+
+	// Lockouts are listed newest first, and outlive the workers they
+	// were for.
+	l1 := &model.Lockout{Kind: model.LockoutAccount, Subject: "one@example.com", Worker: &worker.ID,
+		Failures: 5, LockedAt: now(), LockedUntil: until}
+	require.NoError(t, db.CreateLockout(ctx, l1))
+	l2 := &model.Lockout{Kind: model.LockoutIP, Subject: "192.0.2.1",
+		Failures: 20, LockedAt: now(), LockedUntil: until}
+	require.NoError(t, db.CreateLockout(ctx, l2))
+	assert.NotEqual(l1.ID, l2.ID)
+
+	require.NoError(t, db.DeleteWorkerById(ctx, worker.ID))
+	lockouts, err := db.GetLockouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 2)
+	assert.Equal(l2.ID, lockouts[0].ID)
+	assert.Equal(l1.ID, lockouts[1].ID)
+	assert.Equal(model.LockoutAccount, lockouts[1].Kind)
+	assert.Equal("one@example.com", lockouts[1].Subject)
+	assert.Nil(lockouts[1].Worker)
+	assert.True(until.Equal(lockouts[1].LockedUntil))
+}
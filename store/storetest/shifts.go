@@ -0,0 +1,290 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+func testShifts(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+
+	shift := &model.Shift{
+		StartTime:    at(0, 8),
+		EndTime:      at(0, 16),
+		Capacity:     3,
+		Team:         &team,
+		Requirements: []model.Requirement{{Skill: "first-aid", Count: 1}, {Skill: "forklift", Count: 2}},
+	}
+	require.NoError(t, db.CreateShift(ctx, shift))
+	assert.NotZero(shift.ID)
+	other := createShift(t, db, 0, 16, 1)
+	assert.NotEqual(shift.ID, other.ID)
+
+	got, err := db.GetShiftById(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(shift, utcShift(got))
+	got, err = db.GetShiftById(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(other, utcShift(got))
+	_, err = db.GetShiftById(ctx, other.ID+100)
+	assert.ErrorIs(err, store.ErrShiftNotFound)
+
+	// Shifts handed out are copies.
+	got.Requirements = append(got.Requirements, model.Requirement{Skill: "x", Count: 1})
+	shifts, err := db.GetShifts(ctx, nil, store.WeekSpan, nil, nil)
+	require.NoError(t, err)
+	shifts[0].Requirements[0].Count = 100
+	got, err = db.GetShiftById(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(shift, utcShift(got))
+
+	shift.StartTime = at(1, 8)
+	shift.EndTime = at(1, 16)
+	shift.Capacity = 2
+	shift.Team = nil
+	shift.Requirements = []model.Requirement{{Skill: "forklift", Count: 1}}
+	require.NoError(t, db.UpdateShift(ctx, shift))
+	got, err = db.GetShiftById(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(shift, utcShift(got))
+
+	missing := *shift
+	missing.ID = other.ID + 100
+	assert.ErrorIs(db.UpdateShift(ctx, &missing), store.ErrShiftNotFound)
+}
+
+// Shifts can be filtered by the week or day they're in, by worker and
+// by team, and come back in order of start time.
+func testShiftSpans(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+	worker := createWorker(t, db, "one@example.com")
+
+	tuesday := createShift(t, db, 1, 8, 1)
+	monday := createShift(t, db, 0, 8, 1)
+	sunday := createShift(t, db, 6, 20, 1)
+	nextWeek := createShift(t, db, 7, 8, 1)
+	lastWeek := createShift(t, db, -1, 8, 1)
+	teamShift := &model.Shift{StartTime: at(0, 16), EndTime: at(1, 0), Capacity: 1, Team: &team}
+	require.NoError(t, db.CreateShift(ctx, teamShift))
+	assign(t, db, worker, tuesday)
+	assign(t, db, worker, nextWeek)
+
+	check := func(want []*model.Shift, date *time.Time, span store.TimeSpan,
+		workerId *model.WorkerID, teamId *model.TeamID) {
+		t.Helper()
+		shifts, err := db.GetShifts(ctx, date, span, workerId, teamId)
+		require.NoError(t, err)
+		assert.Equal(shiftIDs(want), shiftIDs(shifts))
+	}
+	wednesday := at(2, 12)
+	check([]*model.Shift{lastWeek, monday, teamShift, tuesday, sunday, nextWeek}, nil, store.WeekSpan, nil, nil)
+	check([]*model.Shift{monday, teamShift, tuesday, sunday}, &wednesday, store.WeekSpan, nil, nil)
+	check([]*model.Shift{tuesday}, ptr(at(1, 12)), store.DaySpan, nil, nil)
+	check([]*model.Shift{monday, teamShift}, ptr(at(0, 12)), store.DaySpan, nil, nil)
+	check([]*model.Shift{}, &wednesday, store.DaySpan, nil, nil)
+	check([]*model.Shift{tuesday}, &wednesday, store.WeekSpan, &worker.ID, nil)
+	check([]*model.Shift{tuesday, nextWeek}, nil, store.WeekSpan, &worker.ID, nil)
+	check([]*model.Shift{teamShift}, &wednesday, store.WeekSpan, nil, &team)
+	check([]*model.Shift{}, &wednesday, store.WeekSpan, &worker.ID, &team)
+
+	shifts, err := db.GetShiftsInRange(ctx, at(0, 12), at(1, 9))
+	require.NoError(t, err)
+	assert.Equal([]model.ShiftID{monday.ID, teamShift.ID, tuesday.ID}, shiftIDs(shifts))
+}
+
+func testCreateShifts(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+	existing := createShift(t, db, 0, 8, 1)
+
+	shifts := []*model.Shift{
+		{StartTime: at(0, 8), EndTime: at(0, 16), Capacity: 2},
+		{StartTime: at(0, 8), EndTime: at(0, 16), Capacity: 2, Team: &team},
+		{StartTime: at(1, 8), EndTime: at(1, 16), Capacity: 2,
+			Requirements: []model.Requirement{{Skill: "first-aid", Count: 1}}},
+	}
+	created, err := db.CreateShifts(ctx, shifts)
+	require.NoError(t, err)
+	assert.Equal(existing.ID, shifts[0].ID)
+	assert.Equal([]*model.Shift{shifts[1], shifts[2]}, created)
+	got, err := db.GetShiftById(ctx, shifts[2].ID)
+	require.NoError(t, err)
+	assert.Equal(shifts[2], utcShift(got))
+
+	// Making the same shifts again makes nothing new.
+	again := []*model.Shift{
+		{StartTime: at(0, 8), EndTime: at(0, 16), Capacity: 2, Team: &team},
+		{StartTime: at(1, 8), EndTime: at(1, 16), Capacity: 2},
+	}
+	created, err = db.CreateShifts(ctx, again)
+	require.NoError(t, err)
+	assert.Empty(created)
+	assert.Equal([]model.ShiftID{shifts[1].ID, shifts[2].ID}, shiftIDs(again))
+	all, err := db.GetShifts(ctx, nil, store.WeekSpan, nil, nil)
+	require.NoError(t, err)
+	assert.Len(all, 3)
+}
+
+// Deleting a shift deletes everything that refers to it.
+func testDeleteShift(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	w3 := createWorker(t, db, "three@example.com")
+	shift := createShift(t, db, 0, 8, 1)
+	other := createShift(t, db, 1, 8, 1)
+	assign(t, db, w1, shift)
+	assign(t, db, w2, other)
+	bid := &model.Bid{Worker: w3.ID, Shift: shift.ID}
+	require.NoError(t, db.CreateBid(ctx, bid))
+	pref := &model.ShiftPreference{Worker: w1.ID, Shift: &shift.ID, Weight: model.Prefer}
+	require.NoError(t, db.CreateShiftPreference(ctx, pref))
+	swap := &model.Swap{Offerer: w2.ID, Shift: other.ID, Taker: &w1.ID,
+		CounterShift: &shift.ID, Status: model.SwapProposed}
+	require.NoError(t, db.CreateSwap(ctx, swap))
+
+	require.NoError(t, db.DeleteShiftById(ctx, shift.ID))
+	_, err := db.GetShiftById(ctx, shift.ID)
+	assert.ErrorIs(err, store.ErrShiftNotFound)
+	assert.Equal([]model.ShiftAssignment{{Worker: w2.ID, Shift: other.ID}}, weekAssignments(t, db))
+	_, err = db.GetBidById(ctx, bid.ID)
+	assert.ErrorIs(err, store.ErrBidNotFound)
+	_, err = db.GetShiftPreferenceById(ctx, pref.ID)
+	assert.ErrorIs(err, store.ErrShiftPreferenceNotFound)
+	gotSwap, err := db.GetSwapById(ctx, swap.ID)
+	require.NoError(t, err)
+	assert.Nil(gotSwap.CounterShift)
+
+	assert.ErrorIs(db.DeleteShiftById(ctx, shift.ID), store.ErrShiftNotFound)
+}
+
+func testShiftTemplates(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+
+	t1 := &model.ShiftTemplate{
+		Name:      "Nights",
+		Weekdays:  model.NewWeekdays(time.Monday, time.Tuesday),
+		StartHour: 22,
+		EndHour:   6,
+		Capacity:  2,
+		Team:      &team,
+	}
+	require.NoError(t, db.CreateShiftTemplate(ctx, t1))
+	t2 := &model.ShiftTemplate{Name: "Days", Weekdays: model.NewWeekdays(time.Sunday),
+		StartHour: 8, EndHour: 16, Capacity: 1}
+	require.NoError(t, db.CreateShiftTemplate(ctx, t2))
+
+	got, err := db.GetShiftTemplateById(ctx, t1.ID)
+	require.NoError(t, err)
+	assert.Equal(t1, got)
+	templates, err := db.GetShiftTemplates(ctx)
+	require.NoError(t, err)
+	assert.Equal([]*model.ShiftTemplate{t1, t2}, templates)
+
+	require.NoError(t, db.DeleteShiftTemplateById(ctx, t1.ID))
+	_, err = db.GetShiftTemplateById(ctx, t1.ID)
+	assert.ErrorIs(err, store.ErrShiftTemplateNotFound)
+	assert.ErrorIs(db.DeleteShiftTemplateById(ctx, t1.ID), store.ErrShiftTemplateNotFound)
+}
+
+func testShiftPreferences(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+	shift := createShift(t, db, 0, 8, 1)
+
+	p1 := &model.ShiftPreference{Worker: w1.ID, Weekday: ptr(time.Monday), StartHour: ptr(8),
+		Weight: model.StronglyAvoid}
+	require.NoError(t, db.CreateShiftPreference(ctx, p1))
+	p2 := &model.ShiftPreference{Worker: w2.ID, Shift: &shift.ID, Weight: model.Prefer}
+	require.NoError(t, db.CreateShiftPreference(ctx, p2))
+	p3 := &model.ShiftPreference{Worker: w1.ID, StartHour: ptr(16), Weight: model.Avoid}
+	require.NoError(t, db.CreateShiftPreference(ctx, p3))
+
+	err := db.CreateShiftPreference(ctx, &model.ShiftPreference{Worker: w2.ID + 100, Weight: model.Prefer})
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+	err = db.CreateShiftPreference(ctx, &model.ShiftPreference{Worker: w2.ID, Shift: ptr(shift.ID + 100)})
+	assert.ErrorIs(err, store.ErrShiftNotFound)
+
+	got, err := db.GetShiftPreferenceById(ctx, p1.ID)
+	require.NoError(t, err)
+	assert.Equal(p1, got)
+	prefs, err := db.GetShiftPreferences(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal([]*model.ShiftPreference{p1, p2, p3}, prefs)
+	prefs, err = db.GetShiftPreferences(ctx, &w1.ID)
+	require.NoError(t, err)
+	assert.Equal([]*model.ShiftPreference{p1, p3}, prefs)
+
+	p1.Weekday = nil
+	p1.Weight = model.StronglyPrefer
+	require.NoError(t, db.UpdateShiftPreference(ctx, p1))
+	got, err = db.GetShiftPreferenceById(ctx, p1.ID)
+	require.NoError(t, err)
+	assert.Equal(p1, got)
+	p1.Shift = ptr(shift.ID + 100)
+	assert.ErrorIs(db.UpdateShiftPreference(ctx, p1), store.ErrShiftNotFound)
+	assert.ErrorIs(db.UpdateShiftPreference(ctx, &model.ShiftPreference{ID: p3.ID + 100, Worker: w1.ID}),
+		store.ErrShiftPreferenceNotFound)
+
+	require.NoError(t, db.DeleteShiftPreferenceById(ctx, p1.ID))
+	_, err = db.GetShiftPreferenceById(ctx, p1.ID)
+	assert.ErrorIs(err, store.ErrShiftPreferenceNotFound)
+	assert.ErrorIs(db.DeleteShiftPreferenceById(ctx, p1.ID), store.ErrShiftPreferenceNotFound)
+}
+
+func testTimeOff(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	w1 := createWorker(t, db, "one@example.com")
+	w2 := createWorker(t, db, "two@example.com")
+
+	later := &model.TimeOff{Worker: w1.ID, StartTime: at(3, 0), EndTime: at(5, 0),
+		Reason: "Holiday", Status: model.TimeOffPending}
+	require.NoError(t, db.CreateTimeOff(ctx, later))
+	earlier := &model.TimeOff{Worker: w2.ID, StartTime: at(1, 0), EndTime: at(2, 0),
+		Reason: "Dentist", Status: model.TimeOffApproved}
+	require.NoError(t, db.CreateTimeOff(ctx, earlier))
+	err := db.CreateTimeOff(ctx, &model.TimeOff{Worker: w2.ID + 100, StartTime: at(1, 0), EndTime: at(2, 0),
+		Status: model.TimeOffPending})
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+
+	utc := func(to *model.TimeOff) *model.TimeOff {
+		c := *to
+		c.StartTime = to.StartTime.UTC()
+		c.EndTime = to.EndTime.UTC()
+		return &c
+	}
+	check := func(want []*model.TimeOff, workerId *model.WorkerID, status *model.TimeOffStatus) {
+		t.Helper()
+		timeOff, err := db.GetTimeOff(ctx, workerId, status)
+		require.NoError(t, err)
+		got := []*model.TimeOff{}
+		for _, to := range timeOff {
+			got = append(got, utc(to))
+		}
+		assert.Equal(want, got)
+	}
+	check([]*model.TimeOff{earlier, later}, nil, nil)
+	check([]*model.TimeOff{later}, &w1.ID, nil)
+	check([]*model.TimeOff{earlier}, nil, ptr(model.TimeOffApproved))
+	check([]*model.TimeOff{}, &w1.ID, ptr(model.TimeOffApproved))
+
+	require.NoError(t, db.UpdateTimeOffStatus(ctx, later.ID, model.TimeOffRejected))
+	got, err := db.GetTimeOffById(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(model.TimeOffRejected, got.Status)
+	assert.ErrorIs(db.UpdateTimeOffStatus(ctx, later.ID+100, model.TimeOffApproved), store.ErrTimeOffNotFound)
+
+	require.NoError(t, db.DeleteTimeOffById(ctx, later.ID))
+	_, err = db.GetTimeOffById(ctx, later.ID)
+	assert.ErrorIs(err, store.ErrTimeOffNotFound)
+	assert.ErrorIs(db.DeleteTimeOffById(ctx, later.ID), store.ErrTimeOffNotFound)
+}
@@ -0,0 +1,146 @@
+// Package storetest is a conformance test suite for implementations of
+// the `store.Store` interface. The tests for each backend call `Run`
+// with a function that makes a new, empty store, and every backend is
+// held to the same contract: the same error values, the same ordering
+// of results, and results that are copies, which callers can change
+// without changing what's stored.
+
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+// Run runs the conformance tests, each against a new store made by
+// newStore. The stores must be empty, but migrated if they need to be.
+func Run(t *testing.T, newStore func(t *testing.T) store.Store) {
+	tests := []struct {
+		name string
+		test func(*testing.T, store.Store)
+	}{
+		{"Workers", testWorkers},
+		{"WorkerCopies", testWorkerCopies},
+		{"Teams", testTeams},
+		{"Roles", testRoles},
+		{"Shifts", testShifts},
+		{"ShiftSpans", testShiftSpans},
+		{"CreateShifts", testCreateShifts},
+		{"DeleteShift", testDeleteShift},
+		{"ShiftTemplates", testShiftTemplates},
+		{"ShiftPreferences", testShiftPreferences},
+		{"TimeOff", testTimeOff},
+		{"ShiftAssignments", testShiftAssignments},
+		{"AssignmentRules", testAssignmentRules},
+		{"DuplicateAssignments", testDuplicateAssignments},
+		{"BulkAssignments", testBulkAssignments},
+		{"RuleSets", testRuleSets},
+		{"RuleOverrides", testRuleOverrides},
+		{"Swaps", testSwaps},
+		{"Bids", testBids},
+		{"RefreshTokens", testRefreshTokens},
+		{"PasswordTokens", testPasswordTokens},
+		{"TwoFactor", testTwoFactor},
+		{"LoginAttempts", testLoginAttempts},
+		{"Transactions", testTransactions},
+		{"ConcurrentAssignments", testConcurrentAssignments},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+// None of the tests need to cancel anything.
+var ctx = context.Background()
+
+// The tests use the week starting on Monday 1 May 2023. Times are in
+// UTC, and results are compared in UTC, because the database backends
+// may return times in another time zone.
+var monday = time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+// Time on a day of the test week (0 is Monday).
+func at(day int, hour int) time.Time {
+	return monday.Add(time.Duration(day*24+hour) * time.Hour)
+}
+
+func createWorker(t *testing.T, db store.Store, email string, skills ...string) *model.Worker {
+	t.Helper()
+	worker := &model.Worker{
+		Email:    email,
+		Name:     email,
+		Password: "password",
+		Skills:   skills,
+	}
+	require.NoError(t, db.CreateWorker(ctx, worker))
+	return worker
+}
+
+func createTeam(t *testing.T, db store.Store, name string) model.TeamID {
+	t.Helper()
+	team := &model.Team{Name: name}
+	require.NoError(t, db.CreateTeam(ctx, team))
+	return team.ID
+}
+
+// Create an eight-hour shift starting at an hour of a day of the test
+// week.
+func createShift(t *testing.T, db store.Store, day int, hour int, capacity int) *model.Shift {
+	t.Helper()
+	shift := &model.Shift{
+		StartTime:    at(day, hour),
+		EndTime:      at(day, hour+8),
+		Capacity:     capacity,
+		Requirements: []model.Requirement{},
+	}
+	require.NoError(t, db.CreateShift(ctx, shift))
+	return shift
+}
+
+func assign(t *testing.T, db store.Store, worker *model.Worker, shift *model.Shift) {
+	t.Helper()
+	require.NoError(t, db.CreateShiftAssignment(ctx, worker.ID, shift.ID, nil))
+}
+
+// All the shift assignments in the test week.
+func weekAssignments(t *testing.T, db store.Store) []model.ShiftAssignment {
+	t.Helper()
+	assignments, err := db.GetShiftAssignmentsInRange(ctx, at(0, 0), at(7, 0))
+	require.NoError(t, err)
+	return assignments
+}
+
+func shiftIDs(shifts []*model.Shift) []model.ShiftID {
+	ids := []model.ShiftID{}
+	for _, s := range shifts {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func workerIDs(workers []*model.Worker) []model.WorkerID {
+	ids := []model.WorkerID{}
+	for _, w := range workers {
+		ids = append(ids, w.ID)
+	}
+	return ids
+}
+
+func utcShift(s *model.Shift) *model.Shift {
+	c := *s
+	c.StartTime = s.StartTime.UTC()
+	c.EndTime = s.EndTime.UTC()
+	return &c
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,191 @@
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+var errFailed = errors.New("failed")
+
+func testTransactions(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	worker := createWorker(t, db, "one@example.com")
+	shift := createShift(t, db, 0, 8, 1)
+
+	err := db.WithTx(ctx, func(tx store.Store) error {
+		createWorker(t, tx, "two@example.com")
+		return tx.CreateShiftAssignment(ctx, worker.ID, shift.ID, nil)
+	})
+	require.NoError(t, err)
+	_, err = db.GetWorkerByEmail(ctx, "two@example.com")
+	assert.NoError(err)
+	assert.Len(weekAssignments(t, db), 1)
+
+	// Everything is undone if the function fails, whether it's the
+	// function's own error or the store's.
+	err = db.WithTx(ctx, func(tx store.Store) error {
+		createWorker(t, tx, "three@example.com")
+		createShift(t, tx, 1, 8, 1)
+		return errFailed
+	})
+	assert.ErrorIs(err, errFailed)
+	err = db.WithTx(ctx, func(tx store.Store) error {
+		other := createWorker(t, tx, "three@example.com")
+		return tx.CreateShiftAssignment(ctx, other.ID, shift.ID, nil)
+	})
+	assert.ErrorIs(err, store.ErrShiftAtCapacity)
+	_, err = db.GetWorkerByEmail(ctx, "three@example.com")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+	shifts, err := db.GetShiftsInRange(ctx, at(0, 0), at(7, 0))
+	require.NoError(t, err)
+	assert.Equal([]model.ShiftID{shift.ID}, shiftIDs(shifts))
+
+	// A failed nested unit of work is undone on its own.
+	err = db.WithTx(ctx, func(tx store.Store) error {
+		createWorker(t, tx, "four@example.com")
+		err := tx.WithTx(ctx, func(tx store.Store) error {
+			createWorker(t, tx, "five@example.com")
+			return errFailed
+		})
+		assert.ErrorIs(err, errFailed)
+		_, err = tx.GetWorkerByEmail(ctx, "five@example.com")
+		assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = db.GetWorkerByEmail(ctx, "four@example.com")
+	assert.NoError(err)
+	_, err = db.GetWorkerByEmail(ctx, "five@example.com")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+
+	// Changes to existing records are undone as well as new ones.
+	other := createWorker(t, db, "six@example.com")
+	err = db.WithTx(ctx, func(tx store.Store) error {
+		renamed := *worker
+		renamed.Name = "renamed"
+		require.NoError(t, tx.UpdateWorker(ctx, &renamed))
+		require.NoError(t, tx.DeleteShiftAssignment(ctx, worker.ID, shift.ID))
+		require.NoError(t, tx.DeleteWorkerById(ctx, other.ID))
+		_, err := tx.RecordLoginFailure(ctx, "account:one@example.com", time.Hour)
+		require.NoError(t, err)
+		return errFailed
+	})
+	assert.ErrorIs(err, errFailed)
+	w, err := db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal(worker.Name, w.Name)
+	assert.Len(weekAssignments(t, db), 1)
+	_, err = db.GetWorkerById(ctx, other.ID)
+	assert.NoError(err)
+	attempts, err := db.GetLoginAttempts(ctx, "account:one@example.com")
+	require.NoError(t, err)
+	assert.Zero(attempts.Failures)
+}
+
+// Run a function for each of n new workers at the same time, returning
+// the errors. The workers' emails start with the name given.
+func concurrently(t *testing.T, db store.Store, name string, n int,
+	fn func(worker *model.Worker) error) []error {
+	t.Helper()
+	workers := []*model.Worker{}
+	for i := 0; i < n; i++ {
+		workers = append(workers, createWorker(t, db, fmt.Sprintf("%s%d@example.com", name, i)))
+	}
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i, w := range workers {
+		wg.Add(1)
+		go func(i int, w *model.Worker) {
+			defer wg.Done()
+			errs[i] = fn(w)
+		}(i, w)
+	}
+	wg.Wait()
+	return errs
+}
+
+func testConcurrentAssignments(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+
+	// The store's own capacity check holds up with lots of workers
+	// trying to take the same shift at once...
+	shift := createShift(t, db, 0, 8, 2)
+	errs := concurrently(t, db, "direct", 10, func(w *model.Worker) error {
+		return db.CreateShiftAssignment(ctx, w.ID, shift.ID, nil)
+	})
+	ok := 0
+	for _, err := range errs {
+		if err == nil {
+			ok++
+		} else {
+			assert.ErrorIs(err, store.ErrShiftAtCapacity)
+		}
+	}
+	assert.Equal(2, ok)
+	assert.Len(weekAssignments(t, db), 2)
+
+	// ...and so does a check made outside the store, in a unit of work
+	// with the assignment.
+	shift = createShift(t, db, 1, 8, 3)
+	errs = concurrently(t, db, "tx", 10, func(w *model.Worker) error {
+		return db.WithTx(ctx, func(tx store.Store) error {
+			got, err := tx.GetShiftById(ctx, shift.ID)
+			if err != nil {
+				return err
+			}
+			assignments, err := tx.GetShiftAssignmentsInRange(ctx, got.StartTime, got.EndTime)
+			if err != nil {
+				return err
+			}
+			taken := 0
+			for _, a := range assignments {
+				if a.Shift == shift.ID {
+					taken++
+				}
+			}
+			if taken >= got.Capacity {
+				return errFailed
+			}
+			return tx.CreateShiftAssignments(ctx,
+				[]model.ShiftAssignment{{Worker: w.ID, Shift: shift.ID}})
+		})
+	})
+	ok = 0
+	for _, err := range errs {
+		if err == nil {
+			ok++
+		} else {
+			assert.ErrorIs(err, errFailed)
+		}
+	}
+	assert.Equal(3, ok)
+	assert.Len(weekAssignments(t, db), 5)
+
+	// Updates made from what was read in a unit of work aren't lost.
+	shift = createShift(t, db, 2, 8, 1)
+	errs = concurrently(t, db, "update", 8, func(w *model.Worker) error {
+		return db.WithTx(ctx, func(tx store.Store) error {
+			got, err := tx.GetShiftById(ctx, shift.ID)
+			if err != nil {
+				return err
+			}
+			got.Capacity++
+			return tx.UpdateShift(ctx, got)
+		})
+	})
+	for _, err := range errs {
+		assert.NoError(err)
+	}
+	got, err := db.GetShiftById(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(9, got.Capacity)
+}
@@ -0,0 +1,253 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"skybluetrades.net/work-planning-demo/model"
+	"skybluetrades.net/work-planning-demo/store"
+)
+
+func testWorkers(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+	team := createTeam(t, db, "Team")
+	role := &model.Role{Name: "Role", Permissions: []model.Permission{model.PermShiftsRead}}
+	require.NoError(t, db.CreateRole(ctx, role))
+
+	w1 := &model.Worker{
+		Email:    "one@example.com",
+		Name:     "One",
+		IsAdmin:  true,
+		Password: "secret",
+		Skills:   []string{"first-aid", "forklift"},
+		Teams:    []model.Membership{{Team: team, IsAdmin: true}},
+		Roles:    []model.RoleID{role.ID},
+	}
+	require.NoError(t, db.CreateWorker(ctx, w1))
+	assert.NotZero(w1.ID)
+	w2 := &model.Worker{Email: "two@example.com", Name: "Two"}
+	require.NoError(t, db.CreateWorker(ctx, w2))
+	assert.NotEqual(w1.ID, w2.ID)
+
+	// Passwords are stored hashed, and workers without one can't log in.
+	got, err := db.GetWorkerById(ctx, w1.ID)
+	require.NoError(t, err)
+	assert.NotEqual("secret", got.Password)
+	got.Password = w1.Password
+	assert.Equal(w1, got)
+	auth, err := db.Authenticate(ctx, "one@example.com", "secret")
+	require.NoError(t, err)
+	assert.Equal(w1.ID, auth.ID)
+	_, err = db.Authenticate(ctx, "one@example.com", "wrong")
+	assert.Error(err)
+	_, err = db.Authenticate(ctx, "two@example.com", "")
+	assert.Error(err)
+	_, err = db.Authenticate(ctx, "nobody@example.com", "secret")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+
+	// Missing lists come back empty.
+	got, err = db.GetWorkerById(ctx, w2.ID)
+	require.NoError(t, err)
+	assert.Equal([]string{}, got.Skills)
+	assert.Equal([]model.Membership{}, got.Teams)
+	assert.Equal([]model.RoleID{}, got.Roles)
+
+	got, err = db.GetWorkerByEmail(ctx, "two@example.com")
+	require.NoError(t, err)
+	assert.Equal(w2.ID, got.ID)
+	_, err = db.GetWorkerByEmail(ctx, "nobody@example.com")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+	_, err = db.GetWorkerById(ctx, w2.ID+100)
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+
+	workers, err := db.GetWorkers(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal([]model.WorkerID{w1.ID, w2.ID}, workerIDs(workers))
+	workers, err = db.GetWorkers(ctx, &team)
+	require.NoError(t, err)
+	assert.Equal([]model.WorkerID{w1.ID}, workerIDs(workers))
+
+	err = db.CreateWorker(ctx, &model.Worker{Email: "one@example.com", Name: "Another One"})
+	assert.ErrorIs(err, store.ErrWorkerEmailExists)
+
+	// Email conflicts are caught by the database for updates too, and
+	// leave the transaction they happen in usable.
+	err = db.WithTx(ctx, func(tx store.Store) error {
+		w := *w2
+		w.Email = "one@example.com"
+		assert.ErrorIs(tx.UpdateWorker(ctx, &w), store.ErrWorkerEmailExists)
+		_, err := tx.GetWorkerById(ctx, w2.ID)
+		return err
+	})
+	assert.NoError(err)
+
+	// Updates replace everything, including the password if there is
+	// one.
+	w1.Name = "Won"
+	w1.Email = "won@example.com"
+	w1.Password = "new-secret"
+	w1.Skills = []string{"forklift"}
+	w1.Teams = []model.Membership{}
+	require.NoError(t, db.UpdateWorker(ctx, w1))
+	got, err = db.GetWorkerById(ctx, w1.ID)
+	require.NoError(t, err)
+	assert.Equal("Won", got.Name)
+	assert.Equal([]string{"forklift"}, got.Skills)
+	assert.Equal([]model.Membership{}, got.Teams)
+	assert.Equal([]model.RoleID{role.ID}, got.Roles)
+	_, err = db.Authenticate(ctx, "won@example.com", "new-secret")
+	assert.NoError(err)
+	_, err = db.GetWorkerByEmail(ctx, "one@example.com")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+
+	// Updates without a password keep the current one.
+	w1.Password = ""
+	w1.Name = "Juan"
+	require.NoError(t, db.UpdateWorker(ctx, w1))
+	got, err = db.GetWorkerById(ctx, w1.ID)
+	require.NoError(t, err)
+	assert.Equal("Juan", got.Name)
+	_, err = db.Authenticate(ctx, "won@example.com", "new-secret")
+	assert.NoError(err)
+
+	err = db.UpdateWorker(ctx, &model.Worker{ID: w2.ID, Email: "won@example.com", Name: "Two"})
+	assert.ErrorIs(err, store.ErrWorkerEmailExists)
+	err = db.UpdateWorker(ctx, &model.Worker{ID: w2.ID + 100, Email: "new@example.com"})
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+
+	require.NoError(t, db.DeleteWorkerById(ctx, w2.ID))
+	_, err = db.GetWorkerById(ctx, w2.ID)
+	assert.ErrorIs(err, store.ErrWorkerNotFound)
+	_, err = db.GetWorkerByEmail(ctx, "two@example.com")
+	assert.ErrorIs(err, store.ErrUnknownWorkerEmail)
+	assert.ErrorIs(db.DeleteWorkerById(ctx, w2.ID), store.ErrWorkerNotFound)
+}
+
+// Workers passed in and handed out by the store don't share anything
+// with what's stored.
+func testWorkerCopies(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+
+	worker := createWorker(t, db, "one@example.com", "first-aid", "forklift")
+	worker.Skills[0] = "changed"
+	got, err := db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]string{"first-aid", "forklift"}, got.Skills)
+
+	got.Skills[0] = "changed"
+	got.Name = "Changed"
+	got, err = db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]string{"first-aid", "forklift"}, got.Skills)
+	assert.Equal("one@example.com", got.Name)
+
+	workers, err := db.GetWorkers(ctx, nil)
+	require.NoError(t, err)
+	workers[0].Skills[0] = "changed"
+	got, err = db.GetWorkerByEmail(ctx, "one@example.com")
+	require.NoError(t, err)
+	assert.Equal([]string{"first-aid", "forklift"}, got.Skills)
+}
+
+func testTeams(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+
+	t1 := createTeam(t, db, "One")
+	t2 := createTeam(t, db, "Two")
+	teams, err := db.GetTeams(ctx)
+	require.NoError(t, err)
+	assert.Equal([]*model.Team{{ID: t1, Name: "One"}, {ID: t2, Name: "Two"}}, teams)
+	team, err := db.GetTeamById(ctx, t2)
+	require.NoError(t, err)
+	assert.Equal("Two", team.Name)
+	_, err = db.GetTeamById(ctx, t2+100)
+	assert.ErrorIs(err, store.ErrTeamNotFound)
+
+	// Memberships can be set one at a time.
+	worker := createWorker(t, db, "one@example.com")
+	require.NoError(t, db.SetTeamMember(ctx, t1, worker.ID, false))
+	require.NoError(t, db.SetTeamMember(ctx, t2, worker.ID, false))
+	require.NoError(t, db.SetTeamMember(ctx, t1, worker.ID, true))
+	got, err := db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]model.Membership{{Team: t1, IsAdmin: true}, {Team: t2}}, got.Teams)
+	assert.ErrorIs(db.SetTeamMember(ctx, t2+100, worker.ID, false), store.ErrTeamNotFound)
+	assert.ErrorIs(db.SetTeamMember(ctx, t1, worker.ID+100, false), store.ErrWorkerNotFound)
+
+	require.NoError(t, db.DeleteTeamMember(ctx, t1, worker.ID))
+	got, err = db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]model.Membership{{Team: t2}}, got.Teams)
+	assert.ErrorIs(db.DeleteTeamMember(ctx, t1, worker.ID), store.ErrTeamNotFound)
+
+	// Deleting a team deletes its shifts and templates, and takes its
+	// members out of it.
+	teamShift := &model.Shift{StartTime: at(0, 8), EndTime: at(0, 16), Capacity: 1, Team: &t2}
+	require.NoError(t, db.CreateShift(ctx, teamShift))
+	otherShift := createShift(t, db, 1, 8, 1)
+	assign(t, db, worker, teamShift)
+	template := &model.ShiftTemplate{Name: "Days", Weekdays: model.NewWeekdays(time.Monday),
+		StartHour: 8, EndHour: 16, Capacity: 1, Team: &t2}
+	require.NoError(t, db.CreateShiftTemplate(ctx, template))
+
+	require.NoError(t, db.DeleteTeamById(ctx, t2))
+	_, err = db.GetTeamById(ctx, t2)
+	assert.ErrorIs(err, store.ErrTeamNotFound)
+	_, err = db.GetShiftById(ctx, teamShift.ID)
+	assert.ErrorIs(err, store.ErrShiftNotFound)
+	_, err = db.GetShiftById(ctx, otherShift.ID)
+	assert.NoError(err)
+	_, err = db.GetShiftTemplateById(ctx, template.ID)
+	assert.ErrorIs(err, store.ErrShiftTemplateNotFound)
+	assert.Empty(weekAssignments(t, db))
+	got, err = db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]model.Membership{}, got.Teams)
+	assert.ErrorIs(db.DeleteTeamById(ctx, t2), store.ErrTeamNotFound)
+}
+
+func testRoles(t *testing.T, db store.Store) {
+	assert := assert.New(t)
+
+	r1 := &model.Role{Name: "Reader", Permissions: []model.Permission{
+		model.PermShiftsRead, model.PermWorkersRead,
+	}}
+	require.NoError(t, db.CreateRole(ctx, r1))
+	r2 := &model.Role{Name: "Nothing"}
+	require.NoError(t, db.CreateRole(ctx, r2))
+
+	got, err := db.GetRoleById(ctx, r1.ID)
+	require.NoError(t, err)
+	assert.Equal(r1, got)
+	got.Permissions[0] = model.PermRolesManage
+	got, err = db.GetRoleById(ctx, r1.ID)
+	require.NoError(t, err)
+	assert.Equal(r1, got)
+	_, err = db.GetRoleById(ctx, r2.ID+100)
+	assert.ErrorIs(err, store.ErrRoleNotFound)
+
+	roles, err := db.GetRoles(ctx)
+	require.NoError(t, err)
+	assert.Equal([]*model.Role{r1, {ID: r2.ID, Name: "Nothing", Permissions: []model.Permission{}}}, roles)
+
+	r2.Name = "Writer"
+	r2.Permissions = []model.Permission{model.PermShiftsWrite}
+	require.NoError(t, db.UpdateRole(ctx, r2))
+	got, err = db.GetRoleById(ctx, r2.ID)
+	require.NoError(t, err)
+	assert.Equal(r2, got)
+	assert.ErrorIs(db.UpdateRole(ctx, &model.Role{ID: r2.ID + 100, Name: "X"}), store.ErrRoleNotFound)
+
+	// Deleting a role takes it away from its workers.
+	worker := &model.Worker{Email: "one@example.com", Name: "One", Roles: []model.RoleID{r1.ID, r2.ID}}
+	require.NoError(t, db.CreateWorker(ctx, worker))
+	require.NoError(t, db.DeleteRoleById(ctx, r1.ID))
+	_, err = db.GetRoleById(ctx, r1.ID)
+	assert.ErrorIs(err, store.ErrRoleNotFound)
+	gotWorker, err := db.GetWorkerById(ctx, worker.ID)
+	require.NoError(t, err)
+	assert.Equal([]model.RoleID{r2.ID}, gotWorker.Roles)
+	assert.ErrorIs(db.DeleteRoleById(ctx, r1.ID), store.ErrRoleNotFound)
+}